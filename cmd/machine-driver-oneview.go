@@ -17,10 +17,247 @@ limitations under the License.
 package main
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
 	"github.com/HewlettPackard/docker-machine-oneview/oneview"
+	"github.com/HewlettPackard/docker-machine-oneview/version"
 	"github.com/docker/machine/libmachine/drivers/plugin"
+	mcnversion "github.com/docker/machine/libmachine/version"
 )
 
 func main() {
+	// docker-machine starts the plugin without arguments, answer version
+	// checks from people and packaging scripts before serving rpc
+	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "version") {
+		fmt.Println(versionString())
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "console" {
+		url, err := consoleURL(os.Args[2:])
+		if err != nil {
+			fail(err)
+		}
+		fmt.Println(url)
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reimage" {
+		if err := reimage(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "destroy" {
+		if err := destroy(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "power" {
+		if err := power(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "run-queue" {
+		if err := runQueue(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "download" {
+		if err := download(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		drifted, err := diff(os.Args[2:])
+		if err != nil {
+			fail(err)
+		}
+		if drifted {
+			os.Exit(driftExitCode)
+		}
+		return
+	}
 	plugin.RegisterDriver(oneview.NewDriver("", ""))
 }
+
+// driftExitCode - diff found drift, apart from the error exit codes so
+// scheduled jobs can tell them apart
+const driftExitCode = 2
+
+// fail - print err and exit with the exit code of its category
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(oneview.ExitCode(err))
+}
+
+// diff - diff <environment.json>, report how the appliance from the
+// ONEVIEW_* environment differs from an environment without changing it,
+// true when it does
+func diff(args []string) (bool, error) {
+	if len(args) != 1 {
+		return false, fmt.Errorf("usage: docker-machine-driver-oneview diff <environment.json>")
+	}
+	report, err := oneview.DiffEnvironment(oneview.ApplianceConfigFromEnv(), args[0])
+	if err != nil {
+		return false, err
+	}
+	fmt.Print(report)
+	return !report.Empty(), nil
+}
+
+// reimage - reimage <machine>, install the os of a machine again on the
+// hardware it has, then provision docker again with docker-machine
+func reimage(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: docker-machine-driver-oneview reimage <machine>")
+	}
+	d, err := oneview.LoadDriver(args[0])
+	if err != nil {
+		return err
+	}
+	if err := d.ReImage(); err != nil {
+		return err
+	}
+	// the machine can come back on another address
+	if err := oneview.SaveDriver(d); err != nil {
+		return err
+	}
+	provision := exec.Command("docker-machine", "provision", args[0])
+	provision.Stdout, provision.Stderr = os.Stdout, os.Stderr
+	if err := provision.Run(); err != nil {
+		return fmt.Errorf("re-imaged %s, but docker-machine provision failed, run it again : %w", args[0], err)
+	}
+	return nil
+}
+
+// destroy - destroy [--force] <machine>, remove the profile and icsp server
+// of a machine whose docker-machine directory is gone, with the appliances
+// from the ONEVIEW_* environment
+func destroy(args []string) error {
+	force := len(args) > 0 && args[0] == "--force"
+	if force {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: docker-machine-driver-oneview destroy [--force] <machine>")
+	}
+	return oneview.DestroyByName(oneview.ApplianceConfigFromEnv(), args[0], force)
+}
+
+// download - download <uri> <dest> [sha256], fetch an artifact like a backup
+// or support dump from the appliance in the ONEVIEW_* environment, resuming
+// an interrupted download of the same dest
+func download(args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("usage: docker-machine-driver-oneview download <uri> <dest> [sha256]")
+	}
+	sum := ""
+	if len(args) == 3 {
+		sum = args[2]
+	}
+	return oneview.DownloadArtifact(oneview.ApplianceConfigFromEnv(), args[0], args[1], sum)
+}
+
+// powerUsage - the power subcommand arguments
+const powerUsage = "usage: docker-machine-driver-oneview power on|off|off-force|restart [--parallel N] [--queue] <machine>..."
+
+// power - power on|off|off-force|restart [--parallel N] <machine>..., change
+// the hardware power of many machines at once with the appliances from the
+// ONEVIEW_* environment, fails when any machine failed
+func power(args []string) error {
+	if len(args) < 2 {
+		return errors.New(powerUsage)
+	}
+	op, args := oneview.PowerOp(args[0]), args[1:]
+	parallelism := 0
+	if args[0] == "--parallel" {
+		if len(args) < 3 {
+			return errors.New(powerUsage)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("--parallel must be a number above 0: %s", args[1])
+		}
+		parallelism, args = n, args[2:]
+	}
+	if args[0] == "--queue" {
+		if len(args) < 2 {
+			return errors.New(powerUsage)
+		}
+		s, err := oneview.OpenOperationQueue(oneview.ApplianceConfigFromEnv())
+		if err != nil {
+			return err
+		}
+		if err := s.EnqueuePower(args[1:], op, parallelism); err != nil {
+			return err
+		}
+		return runPending(s)
+	}
+	summary, err := oneview.PowerOperation(oneview.ApplianceConfigFromEnv(), args, op, parallelism)
+	if err != nil {
+		return err
+	}
+	fmt.Println(summary)
+	if failed := summary.Failed(); len(failed) > 0 {
+		var names []string
+		for _, r := range failed {
+			names = append(names, r.Machine)
+		}
+		return fmt.Errorf("power %s failed for %s", op, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// runQueue - run-queue, run the queued operations that are due, ie; from
+// cron, with the appliances from the ONEVIEW_* environment
+func runQueue(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: docker-machine-driver-oneview run-queue")
+	}
+	s, err := oneview.OpenOperationQueue(oneview.ApplianceConfigFromEnv())
+	if err != nil {
+		return err
+	}
+	return runPending(s)
+}
+
+// runPending - run the due operations of s and say what is left
+func runPending(s *oneview.Scheduler) error {
+	done, err := s.RunPending()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d queued operations ran, %d pending\n", done, len(s.Pending()))
+	return nil
+}
+
+// consoleURL - console [--web] <machine>, a single sign on link to the iLO
+// remote console of a machine, or its iLO web interface with --web
+func consoleURL(args []string) (string, error) {
+	web := false
+	if len(args) > 0 && args[0] == "--web" {
+		web, args = true, args[1:]
+	}
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: docker-machine-driver-oneview console [--web] <machine>")
+	}
+	d, err := oneview.LoadDriver(args[0])
+	if err != nil {
+		return "", err
+	}
+	return d.RemoteConsoleURL(web)
+}
+
+// versionString - the driver version and the libmachine plugin api it speaks
+func versionString() string {
+	return fmt.Sprintf("docker-machine-driver-oneview version %s, build %s (libmachine plugin api %d)",
+		version.Version, version.GitCommit, mcnversion.APIVersion)
+}
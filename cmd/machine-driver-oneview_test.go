@@ -1 +1,58 @@
 package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HewlettPackard/docker-machine-oneview/oneview"
+	"github.com/HewlettPackard/docker-machine-oneview/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionString(t *testing.T) {
+	v := versionString()
+	assert.True(t, strings.HasPrefix(v, "docker-machine-driver-oneview version "+version.Version))
+	assert.Contains(t, v, "libmachine plugin api")
+}
+
+func TestConsoleURLUsage(t *testing.T) {
+	_, err := consoleURL(nil)
+	assert.EqualError(t, err, "usage: docker-machine-driver-oneview console [--web] <machine>")
+
+	_, err = consoleURL([]string{"--web", "a", "b"})
+	assert.Error(t, err)
+}
+
+func TestReImageUsage(t *testing.T) {
+	assert.EqualError(t, reimage(nil), "usage: docker-machine-driver-oneview reimage <machine>")
+}
+
+func TestDestroyUsage(t *testing.T) {
+	assert.EqualError(t, destroy(nil), "usage: docker-machine-driver-oneview destroy [--force] <machine>")
+	assert.EqualError(t, destroy([]string{"--force"}), "usage: docker-machine-driver-oneview destroy [--force] <machine>")
+}
+
+func TestPowerUsage(t *testing.T) {
+	assert.EqualError(t, power([]string{"off"}), powerUsage)
+	assert.EqualError(t, power([]string{"off", "--parallel", "2"}), powerUsage)
+	assert.EqualError(t, power([]string{"off", "--parallel", "x", "docker-1"}), "--parallel must be a number above 0: x")
+	assert.EqualError(t, power([]string{"off", "--parallel", "2", "--queue"}), powerUsage)
+	assert.EqualError(t, runQueue([]string{"now"}), "usage: docker-machine-driver-oneview run-queue")
+}
+
+func TestDownloadUsage(t *testing.T) {
+	assert.EqualError(t, download([]string{"/rest/backups/archive/b.bkp"}), "usage: docker-machine-driver-oneview download <uri> <dest> [sha256]")
+}
+
+func TestDiffUsage(t *testing.T) {
+	_, err := diff(nil)
+	assert.EqualError(t, err, "usage: docker-machine-driver-oneview diff <environment.json>")
+}
+
+func TestUsageExitCode(t *testing.T) {
+	assert.Equal(t, 64, oneview.ExitCode(destroy(nil)))
+	assert.Equal(t, 64, oneview.ExitCode(power([]string{"off", "--parallel", "x", "docker-1"})))
+	_, err := diff(nil)
+	assert.Equal(t, 64, oneview.ExitCode(err))
+	assert.NotEqual(t, driftExitCode, oneview.ExitCode(err))
+}
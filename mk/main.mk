@@ -51,7 +51,7 @@ include mk/validate.mk
 
 # Build native machine and all drivers
 default: build
-build: go-install-oneview build-x
+build: go-install-oneview build-plugins build-x
 release: release-x
 clean: coverage-clean build-clean
 test: go-install-oneview check test-short
@@ -59,6 +59,6 @@ check: dco fmt vet lint
 validate: check test-short test-long
 cross: build-x
 install:
-	cp ./bin/docker-machine* /usr/local/bin/
+	cp ./bin/docker-machine-driver-oneview /usr/local/bin/
 
 .PHONY: .all_build .all_coverage .all_release .all_test .all_validate test build validate clean
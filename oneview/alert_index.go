@@ -0,0 +1,102 @@
+package oneview
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// MachineIndex - docker-machine names by the uris of the resources each
+// machine is made of, its server profile and server hardware.  It is built
+// from the profiles carrying machine metadata, so alert forwarding can name
+// the machine an alert is about rather than a uri.
+type MachineIndex struct {
+	mu    sync.RWMutex
+	byURI map[string]string
+}
+
+// NewMachineIndex - index of the machine profiles
+func NewMachineIndex(profiles []MachineProfile) *MachineIndex {
+	i := &MachineIndex{}
+	i.set(profiles)
+	return i
+}
+
+// BuildMachineIndex - index of every profile with machine metadata on the
+// appliance
+func BuildMachineIndex(c *ov.OVClient) (*MachineIndex, error) {
+	i := &MachineIndex{}
+	return i, i.Refresh(c)
+}
+
+// Refresh - read the machine profiles again, ie; after machines were
+// created or removed.  The index is unchanged when the list fails.
+func (i *MachineIndex) Refresh(c *ov.OVClient) error {
+	profiles, err := ListMachineProfiles(c, nil)
+	if err != nil {
+		return err
+	}
+	i.set(profiles)
+	return nil
+}
+
+// set - replace the index with the profiles
+func (i *MachineIndex) set(profiles []MachineProfile) {
+	byURI := map[string]string{}
+	for _, p := range profiles {
+		name := p.Metadata.Machine
+		if name == "" {
+			continue
+		}
+		for _, uri := range []string{p.URI, p.ServerHardwareURI} {
+			if uri != "" {
+				byURI[uri] = name
+			}
+		}
+	}
+	i.mu.Lock()
+	i.byURI = byURI
+	i.mu.Unlock()
+}
+
+// LookupMachineByResourceURI - the machine a resource belongs to.  A uri
+// below a machine resource, ie; the environmental configuration of its
+// server hardware, belongs to the machine too.
+func (i *MachineIndex) LookupMachineByResourceURI(uri string) (string, bool) {
+	if q := strings.IndexByte(uri, '?'); q >= 0 {
+		uri = uri[:q]
+	}
+	uri = strings.TrimSuffix(uri, "/")
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for uri != "" {
+		if name, ok := i.byURI[uri]; ok {
+			return name, true
+		}
+		slash := strings.LastIndexByte(uri, '/')
+		if slash <= 0 {
+			break
+		}
+		uri = uri[:slash]
+	}
+	return "", false
+}
+
+// MachineAlert - an alert with the machine it is about
+type MachineAlert struct {
+	Alert
+	Machine string `json:"machine"`
+}
+
+// MachineAlerts - the alerts raised against machine resources, with the
+// machine names, in the order given
+func (i *MachineIndex) MachineAlerts(alerts []Alert) []MachineAlert {
+	var list []MachineAlert
+	for _, a := range alerts {
+		if name, ok := i.LookupMachineByResourceURI(a.AssociatedResource.ResourceURI); ok {
+			list = append(list, MachineAlert{Alert: a, Machine: name})
+		}
+	}
+	return list
+}
@@ -0,0 +1,43 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMachineIndex(t *testing.T) {
+	i := NewMachineIndex([]MachineProfile{
+		{
+			ServerProfileSummary: ServerProfileSummary{URI: "/rest/server-profiles/p1", ServerHardwareURI: "/rest/server-hardware/h1"},
+			Metadata:             MachineMetadata{Machine: "docker-1"},
+		},
+		{
+			ServerProfileSummary: ServerProfileSummary{URI: "/rest/server-profiles/p2"},
+			Metadata:             MachineMetadata{Machine: "docker-2"},
+		},
+		{ServerProfileSummary: ServerProfileSummary{URI: "/rest/server-profiles/p3"}},
+	})
+
+	for uri, want := range map[string]string{
+		"/rest/server-profiles/p1":                             "docker-1",
+		"/rest/server-hardware/h1/":                            "docker-1",
+		"/rest/server-hardware/h1/environmentalConfiguration":  "docker-1",
+		"/rest/server-hardware/h1/utilization?fields=CpuUsage": "docker-1",
+		"/rest/server-profiles/p2":                             "docker-2",
+	} {
+		name, ok := i.LookupMachineByResourceURI(uri)
+		assert.True(t, ok, uri)
+		assert.Equal(t, want, name, uri)
+	}
+	for _, uri := range []string{"/rest/server-profiles/p3", "/rest/server-hardware/h10", "/rest", ""} {
+		_, ok := i.LookupMachineByResourceURI(uri)
+		assert.False(t, ok, uri)
+	}
+
+	alerts := i.MachineAlerts([]Alert{
+		{URI: "/rest/alerts/1", AssociatedResource: AlertResource{ResourceURI: "/rest/enclosures/e1"}},
+		{URI: "/rest/alerts/2", AssociatedResource: AlertResource{ResourceURI: "/rest/server-hardware/h1"}},
+	})
+	assert.Equal(t, []MachineAlert{{Alert: Alert{URI: "/rest/alerts/2", AssociatedResource: AlertResource{ResourceURI: "/rest/server-hardware/h1"}}, Machine: "docker-1"}}, alerts)
+}
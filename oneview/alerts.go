@@ -0,0 +1,67 @@
+package oneview
+
+import (
+	"encoding/json"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+const alertsURI = "/rest/alerts"
+
+// alert severities and states reported by the appliance
+const (
+	AlertSeverityCritical = "Critical"
+	AlertSeverityWarning  = "Warning"
+	AlertStateActive      = "Active"
+	AlertStateLocked      = "Locked"
+	AlertStateCleared     = "Cleared"
+)
+
+// AlertResource - the resource an alert was raised against
+type AlertResource struct {
+	ResourceURI      string `json:"resourceUri,omitempty"`
+	ResourceName     string `json:"resourceName,omitempty"`
+	ResourceCategory string `json:"resourceCategory,omitempty"`
+}
+
+// Alert - an appliance alert
+type Alert struct {
+	URI                string        `json:"uri,omitempty"`
+	Severity           string        `json:"severity,omitempty"`
+	AlertState         string        `json:"alertState,omitempty"`
+	Description        string        `json:"description,omitempty"`
+	CorrectiveAction   string        `json:"correctiveAction,omitempty"`
+	AssociatedResource AlertResource `json:"associatedResource,omitempty"`
+	Created            Timestamp     `json:"created,omitempty"`
+}
+
+// listAlerts - alerts matching all of the filters
+func listAlerts(c *ov.OVClient, filters []string) ([]Alert, error) {
+	var list []Alert
+	err := listOrdered(c, alertsURI, ListOptions{Filters: filters, Sort: "created:" + SortAscending}, func(members json.RawMessage) error {
+		var page []Alert
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// criticalAlertsByResource - active critical alerts keyed by resource uri
+func criticalAlertsByResource(c *ov.OVClient) (map[string][]Alert, error) {
+	alerts, err := listAlerts(c, []string{
+		applianceFilter("alertState", AlertStateActive),
+		applianceFilter("severity", AlertSeverityCritical),
+	})
+	if err != nil {
+		return nil, err
+	}
+	byResource := map[string][]Alert{}
+	for _, a := range alerts {
+		uri := a.AssociatedResource.ResourceURI
+		byResource[uri] = append(byResource[uri], a)
+	}
+	return byResource, nil
+}
@@ -0,0 +1,90 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const applianceVersionURI = "/rest/appliance/nodeinfo/version"
+
+// ApplianceVersion - software build of the appliance, the api version alone
+// does not tell patch levels apart
+type ApplianceVersion struct {
+	Major           string `json:"major,omitempty"`
+	Minor           string `json:"minor,omitempty"`
+	Revision        string `json:"revision,omitempty"`
+	Build           string `json:"build,omitempty"`
+	SoftwareVersion string `json:"softwareVersion,omitempty"`
+	Date            string `json:"date,omitempty"`
+	Family          string `json:"family,omitempty"`
+	ModelNumber     string `json:"modelNumber,omitempty"`
+	PlatformType    string `json:"platformType,omitempty"`
+	SerialNumber    string `json:"serialNumber,omitempty"`
+}
+
+// String - ie; OneView 3.10.04-0319871 (Synergy Composer)
+func (v ApplianceVersion) String() string {
+	s := v.SoftwareVersion
+	if s == "" {
+		s = fmt.Sprintf("%s.%s", v.Major, v.Minor)
+		if v.Build != "" {
+			s += "-" + v.Build
+		}
+	}
+	family := v.Family
+	if family == "" {
+		family = "OneView"
+	}
+	if v.PlatformType != "" {
+		return fmt.Sprintf("%s %s (%s)", family, s, v.PlatformType)
+	}
+	return fmt.Sprintf("%s %s", family, s)
+}
+
+// GetApplianceVersion - the software build of the appliance
+func GetApplianceVersion(c *ov.OVClient) (ApplianceVersion, error) {
+	var v ApplianceVersion
+	data, err := ovCall(c, rest.GET, applianceVersionURI, nil)
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+var (
+	applianceVersionsMu sync.Mutex
+	// applianceVersions - versions already looked up, by endpoint, the
+	// build does not change while the driver runs
+	applianceVersions = map[string]ApplianceVersion{}
+)
+
+// logApplianceVersion - log the appliance build at the start of an
+// operation, so debug logs show which appliance a failure came from.  Any
+// error getting it is logged and otherwise ignored.
+func (d *Driver) logApplianceVersion(op string) {
+	if d.ClientOV == nil || d.ClientOV.Endpoint == "" {
+		return
+	}
+	applianceVersionsMu.Lock()
+	v, ok := applianceVersions[d.ClientOV.Endpoint]
+	applianceVersionsMu.Unlock()
+	if !ok {
+		var err error
+		if v, err = GetApplianceVersion(d.ClientOV); err != nil {
+			log.Debugf("%s %s : unable to get the appliance version : %s", op, d.MachineName, err)
+			return
+		}
+		applianceVersionsMu.Lock()
+		applianceVersions[d.ClientOV.Endpoint] = v
+		applianceVersionsMu.Unlock()
+	}
+	log.Debugf("%s %s : %s at %s, api version %d", op, d.MachineName, v, d.ClientOV.Endpoint, d.ClientOV.APIVersion)
+}
@@ -0,0 +1,16 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplianceVersion(t *testing.T) {
+	var v ApplianceVersion
+	data := `{"major":"3","minor":"10","build":"0319871","softwareVersion":"3.10.04-0319871","family":"Synergy Composer","platformType":"Synergy","modelNumber":"713312-B21"}`
+	assert.NoError(t, json.Unmarshal([]byte(data), &v))
+	assert.Equal(t, "Synergy Composer 3.10.04-0319871 (Synergy)", v.String())
+	assert.Equal(t, "OneView 2.00-0262318", ApplianceVersion{Major: "2", Minor: "00", Build: "0262318"}.String())
+}
@@ -0,0 +1,166 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// applyHistoryFile - kept in the machine store, shared by every machine
+const applyHistoryFile = "oneview-apply-history.json"
+
+// applyHistorySize - durations kept per hardware type, older ones drop off
+const applyHistorySize = 10
+
+// ApplyHistory - how long profile applies took, in seconds by server
+// hardware type uri, for estimating the time left on the next one
+type ApplyHistory struct {
+	Durations map[string][]int64 `json:"durations"`
+}
+
+// LoadApplyHistory - the history in a file, empty when there is none yet
+func LoadApplyHistory(path string) (*ApplyHistory, error) {
+	h := &ApplyHistory{Durations: map[string][]int64{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return &ApplyHistory{Durations: map[string][]int64{}}, fmt.Errorf("unable to read apply history %s : %w", path, err)
+	}
+	if h.Durations == nil {
+		h.Durations = map[string][]int64{}
+	}
+	return h, nil
+}
+
+// Save - write the history, replacing the file whole so a concurrent create
+// never reads half of it
+func (h *ApplyHistory) Save(path string) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), applyHistoryFile)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Record - add how long an apply on the hardware type took
+func (h *ApplyHistory) Record(hardwareType string, d time.Duration) {
+	list := append(h.Durations[hardwareType], int64(d/time.Second))
+	if len(list) > applyHistorySize {
+		list = list[len(list)-applyHistorySize:]
+	}
+	h.Durations[hardwareType] = list
+}
+
+// Estimate - the median apply time on the hardware type, false with no history
+func (h *ApplyHistory) Estimate(hardwareType string) (time.Duration, bool) {
+	list := h.Durations[hardwareType]
+	if len(list) == 0 {
+		return 0, false
+	}
+	sorted := append([]int64(nil), list...)
+	sort.Sort(int64s(sorted))
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return time.Duration(median) * time.Second, true
+}
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+
+// etaProgress - ProgressSink adding the time left, from an estimate of
+// the whole, to updates before passing them on
+type etaProgress struct {
+	out      ProgressSink
+	estimate time.Duration
+	start    time.Time
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+func newETAProgress(out ProgressSink, estimate time.Duration) *etaProgress {
+	return &etaProgress{out: out, estimate: estimate, start: time.Now(), now: time.Now}
+}
+
+// WriteProgress - ie; Running 40%, about 3m0s left
+func (e *etaProgress) WriteProgress(p progress.Progress) error {
+	if p.Message == "" && !p.LastUpdate {
+		e.mu.Lock()
+		p.Action += ", " + formatETA(e.estimate, e.now().Sub(e.start))
+		e.mu.Unlock()
+	}
+	return e.out.WriteProgress(p)
+}
+
+// formatETA - the time left when elapsed of estimate has gone
+func formatETA(estimate, elapsed time.Duration) string {
+	left := (estimate - elapsed).Round(time.Second)
+	if left <= 0 {
+		return fmt.Sprintf("taking longer than the usual %s", estimate)
+	}
+	return fmt.Sprintf("about %s left", left)
+}
+
+// applyHistoryPath - the history file in the machine store
+func (d *Driver) applyHistoryPath() string {
+	return filepath.Join(d.StorePath, applyHistoryFile)
+}
+
+// applyProfile - submit the planned profile with the time left shown next
+// to the percent complete, when earlier applies on the hardware type give
+// an estimate, then add this apply to the history
+func (d *Driver) applyProfile(plan *CreatePlan) error {
+	hardwareType := plan.Hardware.ServerHardwareTypeURI
+	path := d.applyHistoryPath()
+	history, err := LoadApplyHistory(path)
+	if err != nil {
+		log.Debugf("%s, starting a new one", err)
+	}
+	out := defaultProgress()
+	if estimate, ok := history.Estimate(hardwareType); ok {
+		log.Infof("Profile applies on this hardware type usually take %s", estimate)
+		out = newETAProgress(out, estimate)
+	}
+	start := time.Now()
+	if err := submitProfile(d.ClientOV, plan.Profile, d.ProfileForce, out); err != nil {
+		return err
+	}
+	if hardwareType == "" {
+		return nil
+	}
+	history.Record(hardwareType, time.Since(start))
+	if err := history.Save(path); err != nil {
+		log.Debugf("unable to save apply history %s : %s", path, err)
+	}
+	return nil
+}
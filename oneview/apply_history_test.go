@@ -0,0 +1,56 @@
+package oneview
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyHistoryEstimate(t *testing.T) {
+	h := &ApplyHistory{Durations: map[string][]int64{}}
+	_, ok := h.Estimate("gen9")
+	assert.False(t, ok)
+
+	for _, s := range []int{600, 300, 400} {
+		h.Record("gen9", time.Duration(s)*time.Second)
+	}
+	e, ok := h.Estimate("gen9")
+	assert.True(t, ok)
+	assert.Equal(t, 400*time.Second, e)
+
+	h.Record("gen9", 500*time.Second)
+	e, _ = h.Estimate("gen9")
+	assert.Equal(t, 450*time.Second, e)
+
+	for i := 0; i < applyHistorySize; i++ {
+		h.Record("gen9", time.Minute)
+	}
+	assert.Len(t, h.Durations["gen9"], applyHistorySize)
+	e, _ = h.Estimate("gen9")
+	assert.Equal(t, time.Minute, e)
+}
+
+func TestApplyHistorySave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apply-history")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, applyHistoryFile)
+
+	h, err := LoadApplyHistory(path)
+	assert.NoError(t, err)
+	h.Record("gen9", 5*time.Minute)
+	assert.NoError(t, h.Save(path))
+
+	h, err = LoadApplyHistory(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{300}, h.Durations["gen9"])
+}
+
+func TestFormatETA(t *testing.T) {
+	assert.Equal(t, "about 3m0s left", formatETA(5*time.Minute, 2*time.Minute))
+	assert.Equal(t, "taking longer than the usual 5m0s", formatETA(5*time.Minute, 6*time.Minute))
+}
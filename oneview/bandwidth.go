@@ -0,0 +1,136 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+const (
+	// bandwidthStep - the appliance only accepts multiples of 100Mb
+	bandwidthStep Mbps = 100
+	// maxPortBandwidth - the fastest FlexNIC port the appliance can configure
+	maxPortBandwidth Mbps = 20000
+)
+
+// Mbps - bandwidth in megabits per second.  The appliance keeps requestedMbps
+// and maximumMbps as strings, Mbps marshals to that form and reads either.
+type Mbps int
+
+// ParseMbps - parse values like 2500, "2500", "2.5Gb", "2.5Gbps" or "500Mb"
+func ParseMbps(s string) (Mbps, error) {
+	v := strings.ToLower(strings.Replace(strings.TrimSpace(s), " ", "", -1))
+	v = strings.TrimSuffix(v, "ps")
+	v = strings.TrimSuffix(v, "/s")
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(v, "gb"):
+		scale, v = 1000, strings.TrimSuffix(v, "gb")
+	case strings.HasSuffix(v, "g"):
+		scale, v = 1000, strings.TrimSuffix(v, "g")
+	case strings.HasSuffix(v, "mb"):
+		v = strings.TrimSuffix(v, "mb")
+	case strings.HasSuffix(v, "m"):
+		v = strings.TrimSuffix(v, "m")
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid bandwidth %q", s)
+	}
+	mb := f * scale
+	if mb != math.Trunc(mb) {
+		return 0, fmt.Errorf("invalid bandwidth %q, must be a whole number of Mbps", s)
+	}
+	return Mbps(mb), nil
+}
+
+// String - the form the appliance expects, ie; 2500
+func (m Mbps) String() string {
+	return strconv.Itoa(int(m))
+}
+
+// Gbps - bandwidth in gigabits per second
+func (m Mbps) Gbps() float64 {
+	return float64(m) / 1000
+}
+
+// MarshalJSON - write as the string the appliance uses
+func (m Mbps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON - read from a json string or number
+func (m *Mbps) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(b, &n); err != nil {
+			return fmt.Errorf("invalid bandwidth %s", string(b))
+		}
+		s = n.String()
+	}
+	if s == "" {
+		*m = 0
+		return nil
+	}
+	v, err := ParseMbps(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Validate - check the appliance rules for a connection bandwidth
+func (m Mbps) Validate() error {
+	if m < bandwidthStep || m > maxPortBandwidth {
+		return fmt.Errorf("bandwidth %sMbps must be between %s and %sMbps", m, bandwidthStep, maxPortBandwidth)
+	}
+	if m%bandwidthStep != 0 {
+		return fmt.Errorf("bandwidth %sMbps must be a multiple of %sMbps", m, bandwidthStep)
+	}
+	return nil
+}
+
+// BandwidthBounds - bandwidth limits from a network's connection template
+type BandwidthBounds struct {
+	TypicalBandwidth Mbps `json:"typicalBandwidth"`
+	MaximumBandwidth Mbps `json:"maximumBandwidth"`
+}
+
+// Validate - check a requested bandwidth fits the connection template
+func (b BandwidthBounds) Validate(requested Mbps) error {
+	if err := requested.Validate(); err != nil {
+		return err
+	}
+	if b.MaximumBandwidth > 0 && requested > b.MaximumBandwidth {
+		return fmt.Errorf("requested bandwidth %sMbps exceeds the connection template maximum of %sMbps", requested, b.MaximumBandwidth)
+	}
+	return nil
+}
+
+// ConnectionTemplate - the parts of a connection template we read
+type ConnectionTemplate struct {
+	URI       string          `json:"uri,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Bandwidth BandwidthBounds `json:"bandwidth"`
+}
+
+// GetBandwidthBounds - read the bounds from a connection template uri, found
+// on a network as connectionTemplateUri
+func GetBandwidthBounds(c *ov.OVClient, connectionTemplateURI string) (BandwidthBounds, error) {
+	var ct ConnectionTemplate
+	data, err := ovCall(c, rest.GET, connectionTemplateURI, nil)
+	if err != nil {
+		return ct.Bandwidth, err
+	}
+	if err := json.Unmarshal(data, &ct); err != nil {
+		return ct.Bandwidth, err
+	}
+	return ct.Bandwidth, nil
+}
@@ -0,0 +1,49 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMbps(t *testing.T) {
+	for in, want := range map[string]Mbps{
+		"2500":     2500,
+		"2.5Gb":    2500,
+		"2.5 Gbps": 2500,
+		"10G":      10000,
+		"500Mb":    500,
+		"100mbps":  100,
+	} {
+		got, err := ParseMbps(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "fast", "-1", "2.5555Gb"} {
+		_, err := ParseMbps(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestMbpsJSON(t *testing.T) {
+	var conn struct {
+		RequestedMbps Mbps `json:"requestedMbps"`
+		MaximumMbps   Mbps `json:"maximumMbps"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"requestedMbps":"2500","maximumMbps":10000}`), &conn))
+	assert.Equal(t, Mbps(2500), conn.RequestedMbps)
+	assert.Equal(t, Mbps(10000), conn.MaximumMbps)
+
+	b, err := json.Marshal(conn)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"requestedMbps":"2500","maximumMbps":"10000"}`, string(b))
+}
+
+func TestBandwidthBoundsValidate(t *testing.T) {
+	b := BandwidthBounds{TypicalBandwidth: 2500, MaximumBandwidth: 10000}
+	assert.NoError(t, b.Validate(2500))
+	assert.Error(t, b.Validate(12000))
+	assert.Error(t, b.Validate(2550))
+	assert.Error(t, b.Validate(0))
+}
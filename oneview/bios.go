@@ -0,0 +1,158 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// problems with a bios setting of a profile
+const (
+	BiosUnknownSetting    = "unknown setting"
+	BiosUnknownOption     = "unknown option"
+	BiosDeprecatedSetting = "deprecated setting"
+)
+
+// BiosOption - one value an enumerated bios setting takes
+type BiosOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// BiosSettingDefinition - a bios setting the server hardware type has,
+// enumerated settings list their options, others take any value
+type BiosSettingDefinition struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name,omitempty"`
+	Category     string       `json:"category,omitempty"`
+	Type         string       `json:"type,omitempty"`
+	DefaultValue string       `json:"defaultValue,omitempty"`
+	Options      []BiosOption `json:"options,omitempty"`
+	Deprecated   bool         `json:"deprecated,omitempty"`
+}
+
+// HasOption - true when the setting takes the value, any value for
+// settings without options
+func (s BiosSettingDefinition) HasOption(value string) bool {
+	if len(s.Options) == 0 {
+		return true
+	}
+	for _, o := range s.Options {
+		if o.ID == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ServerHardwareTypeBios - the bios settings of a server hardware type
+type ServerHardwareTypeBios struct {
+	Settings []BiosSettingDefinition `json:"biosSettings"`
+}
+
+// Setting - the setting with the id, false when the type has none
+func (b ServerHardwareTypeBios) Setting(id string) (BiosSettingDefinition, bool) {
+	for _, s := range b.Settings {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return BiosSettingDefinition{}, false
+}
+
+// BiosProblem - a profile bios setting the hardware type would refuse or
+// no longer honours
+type BiosProblem struct {
+	ID      string
+	Value   string
+	Problem string
+}
+
+// String - ie; unknown option PowerRegulator=Fast
+func (p BiosProblem) String() string {
+	return fmt.Sprintf("%s %s=%s", p.Problem, p.ID, p.Value)
+}
+
+// Diff - the overridden settings of a profile checked against the bios of
+// the type, in the order of the settings
+func (b ServerHardwareTypeBios) Diff(settings []BiosSetting) []BiosProblem {
+	var problems []BiosProblem
+	for _, s := range settings {
+		def, ok := b.Setting(s.ID)
+		switch {
+		case !ok:
+			problems = append(problems, BiosProblem{ID: s.ID, Value: s.Value, Problem: BiosUnknownSetting})
+		case !def.HasOption(s.Value):
+			problems = append(problems, BiosProblem{ID: s.ID, Value: s.Value, Problem: BiosUnknownOption})
+		case def.Deprecated:
+			problems = append(problems, BiosProblem{ID: s.ID, Value: s.Value, Problem: BiosDeprecatedSetting})
+		}
+	}
+	return problems
+}
+
+// GetServerHardwareTypeBios - the bios settings of the server hardware type at uri
+func GetServerHardwareTypeBios(c *ov.OVClient, uri string) (ServerHardwareTypeBios, error) {
+	var b ServerHardwareTypeBios
+	data, err := ovCall(c, rest.GET, uri+"/bios", nil)
+	if err != nil {
+		return b, err
+	}
+	err = json.Unmarshal(data, &b)
+	return b, err
+}
+
+// profileBiosSettings - the hardware type and overridden bios settings of a
+// raw profile
+func profileBiosSettings(profile map[string]interface{}) (string, []BiosSetting) {
+	sht, _ := profile["serverHardwareTypeUri"].(string)
+	data, err := json.Marshal(profile["bios"])
+	if err != nil {
+		return sht, nil
+	}
+	var bios ProfileBios
+	if err := json.Unmarshal(data, &bios); err != nil || !bios.ManageBios {
+		return sht, nil
+	}
+	return sht, bios.OverriddenSettings
+}
+
+// DiffProfileBios - the overridden bios settings of a raw profile checked
+// against its server hardware type, so unknown option ids are found before
+// the appliance refuses the profile half way through creating it
+func DiffProfileBios(c *ov.OVClient, profile map[string]interface{}) ([]BiosProblem, error) {
+	sht, settings := profileBiosSettings(profile)
+	if sht == "" || len(settings) == 0 {
+		return nil, nil
+	}
+	bios, err := GetServerHardwareTypeBios(c, sht)
+	if err != nil {
+		return nil, err
+	}
+	return bios.Diff(settings), nil
+}
+
+// checkProfileBios - fail on bios settings the hardware type does not have,
+// deprecated ones only warn
+func checkProfileBios(c *ov.OVClient, profile map[string]interface{}) error {
+	problems, err := DiffProfileBios(c, profile)
+	if err != nil {
+		return err
+	}
+	var refused []string
+	for _, p := range problems {
+		if p.Problem == BiosDeprecatedSetting {
+			log.Warnf("Profile bios has a %s", p)
+			continue
+		}
+		refused = append(refused, p.String())
+	}
+	if len(refused) > 0 {
+		return fmt.Errorf("%w: bios has %s", ErrInvalidProfile, strings.Join(refused, ", "))
+	}
+	return nil
+}
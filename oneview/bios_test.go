@@ -0,0 +1,46 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBiosDiff(t *testing.T) {
+	var bios ServerHardwareTypeBios
+	assert.NoError(t, json.Unmarshal([]byte(`{"biosSettings": [
+		{"id": "PowerRegulator", "options": [{"id": "StaticHighPerf"}, {"id": "DynamicPowerSavings"}]},
+		{"id": "DynamicPowerCapping", "options": [{"id": "Enabled"}, {"id": "Disabled"}], "deprecated": true},
+		{"id": "ServerAssetTag"}
+	]}`), &bios))
+
+	problems := bios.Diff([]BiosSetting{
+		{ID: "PowerRegulator", Value: "StaticHighPerf"},
+		{ID: "PowerRegulator", Value: "Fast"},
+		{ID: "DynamicPowerCapping", Value: "Disabled"},
+		{ID: "ServerAssetTag", Value: "docker1"},
+		{ID: "NoSuchSetting", Value: "On"},
+	})
+	assert.Equal(t, []BiosProblem{
+		{ID: "PowerRegulator", Value: "Fast", Problem: BiosUnknownOption},
+		{ID: "DynamicPowerCapping", Value: "Disabled", Problem: BiosDeprecatedSetting},
+		{ID: "NoSuchSetting", Value: "On", Problem: BiosUnknownSetting},
+	}, problems)
+	assert.Equal(t, "unknown option PowerRegulator=Fast", problems[0].String())
+}
+
+func TestProfileBiosSettings(t *testing.T) {
+	profile := map[string]interface{}{"serverHardwareTypeUri": "/rest/server-hardware-types/1"}
+	sht, settings := profileBiosSettings(profile)
+	assert.Equal(t, "/rest/server-hardware-types/1", sht)
+	assert.Empty(t, settings)
+
+	setBIOSSetting(profile, "PowerRegulator", "StaticHighPerf")
+	_, settings = profileBiosSettings(profile)
+	assert.Equal(t, []BiosSetting{{ID: "PowerRegulator", Value: "StaticHighPerf"}}, settings)
+
+	profile["bios"].(map[string]interface{})["manageBios"] = false
+	_, settings = profileBiosSettings(profile)
+	assert.Empty(t, settings)
+}
@@ -0,0 +1,198 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// BootVlanUntagged - pxe uses the untagged network of the boot connection
+const BootVlanUntagged = "untagged"
+
+// ErrDriverInvalidBootVlan - the boot vlan flag is neither untagged nor a vlan id
+var ErrDriverInvalidBootVlan = errors.New("Invalid option --oneview-boot-vlan, must be untagged or a vlan id from 1 to 4094")
+
+// BootVlan - how pxe traffic reaches the primary boot connection.  A
+// connection to one network gets it untagged, a connection to a network set
+// gets every network tagged except the native one, so pxe over a set with no
+// native network never gets an answer and the server just sits at the boot
+// prompt.
+type BootVlan struct {
+	// Untagged - pxe over the untagged network, the native network of a set
+	Untagged bool
+	// ID - pxe tagged with this vlan, only a network set carries tags
+	ID int
+}
+
+// parseBootVlan - the --oneview-boot-vlan value, empty keeps the template
+func parseBootVlan(s string) (BootVlan, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "":
+		return BootVlan{}, nil
+	case BootVlanUntagged:
+		return BootVlan{Untagged: true}, nil
+	}
+	id, err := strconv.Atoi(s)
+	if err != nil || id < 1 || id > 4094 {
+		return BootVlan{}, ErrDriverInvalidBootVlan
+	}
+	return BootVlan{ID: id}, nil
+}
+
+// isDefault - true when the boot connection is left as the template made it
+func (b BootVlan) isDefault() bool {
+	return !b.Untagged && b.ID == 0
+}
+
+// String - the flag value
+func (b BootVlan) String() string {
+	switch {
+	case b.Untagged:
+		return BootVlanUntagged
+	case b.ID > 0:
+		return strconv.Itoa(b.ID)
+	}
+	return ""
+}
+
+// connectionNetwork - what the network of a connection carries
+type connectionNetwork struct {
+	URI string
+	// Set - true for a network set
+	Set bool
+	// Native - vlan a set carries untagged, 0 when it has no native network
+	Native int
+	// Vlans - vlan ids the connection carries
+	Vlans []int
+}
+
+// check - fail with why pxe cannot work when the network does not carry
+// the boot vlan the way it was asked for
+func (b BootVlan) check(conn int, n connectionNetwork) error {
+	switch {
+	case b.Untagged && n.Set && n.Native == 0:
+		return fmt.Errorf("boot connection %d uses network set %s which has no native network, pxe needs an untagged network, set one on the network set", conn, n.URI)
+	case b.ID > 0 && !n.Set:
+		return fmt.Errorf("boot connection %d uses network %s which the server only sees untagged, pxe tagged with vlan %d never reaches it, use --oneview-boot-vlan untagged or a network set", conn, n.URI, b.ID)
+	case b.ID > 0 && b.ID == n.Native:
+		return fmt.Errorf("boot connection %d carries vlan %d untagged as the native network of %s, use --oneview-boot-vlan untagged", conn, b.ID, n.URI)
+	case b.ID > 0:
+		for _, v := range n.Vlans {
+			if v == b.ID {
+				return nil
+			}
+		}
+		return fmt.Errorf("boot connection %d uses network set %s which does not carry vlan %d", conn, n.URI, b.ID)
+	}
+	return nil
+}
+
+// apply - set the boot vlan of a raw connection, tagged pxe needs it and
+// untagged pxe must not have it, returns true when something was changed
+func (b BootVlan) apply(conn map[string]interface{}) bool {
+	boot, _ := conn["boot"].(map[string]interface{})
+	current, _ := jsonInt(boot["bootVlanId"])
+	if int(current) == b.ID {
+		return false
+	}
+	if boot == nil {
+		boot = map[string]interface{}{}
+		conn["boot"] = boot
+	}
+	if b.ID == 0 {
+		delete(boot, "bootVlanId")
+	} else {
+		boot["bootVlanId"] = float64(b.ID)
+	}
+	return true
+}
+
+// primaryBootConnection - the ethernet connection the server pxe boots from
+func primaryBootConnection(profile map[string]interface{}) map[string]interface{} {
+	for _, conn := range profileConnections(profile) {
+		if ft, _ := conn["functionType"].(string); ft != "" && ft != FunctionTypeEthernet {
+			continue
+		}
+		boot, _ := conn["boot"].(map[string]interface{})
+		if boot["priority"] == BootPrimary {
+			return conn
+		}
+	}
+	return nil
+}
+
+// readConnectionNetwork - the vlans a connection network carries, member
+// networks are only read when a tagged vlan has to be found
+func (d *Driver) readConnectionNetwork(uri string, members bool) (connectionNetwork, error) {
+	n := connectionNetwork{URI: uri, Set: strings.HasPrefix(uri, environmentCollections[KindNetworkSet]+"/")}
+	network, err := getResourceMap(d.ClientOV, uri)
+	if err != nil {
+		return n, err
+	}
+	vlanOf := func(uri string) (int, error) {
+		net, err := getResourceMap(d.ClientOV, uri)
+		if err != nil {
+			return 0, err
+		}
+		id, _ := jsonInt(net["vlanId"])
+		return int(id), nil
+	}
+	if !n.Set {
+		id, _ := jsonInt(network["vlanId"])
+		n.Vlans = []int{int(id)}
+		return n, nil
+	}
+	if native, _ := network["nativeNetworkUri"].(string); native != "" {
+		if n.Native, err = vlanOf(native); err != nil {
+			return n, err
+		}
+	}
+	if !members {
+		return n, nil
+	}
+	uris, _ := network["networkUris"].([]interface{})
+	for _, u := range uris {
+		s, _ := u.(string)
+		if s == "" {
+			continue
+		}
+		id, err := vlanOf(s)
+		if err != nil {
+			return n, err
+		}
+		n.Vlans = append(n.Vlans, id)
+	}
+	return n, nil
+}
+
+// bootVlanChange - check the primary boot connection carries the boot vlan
+// the way --oneview-boot-vlan asks and set its boot vlan id
+func (d *Driver) bootVlanChange() profileChange {
+	return func(profile map[string]interface{}) (bool, error) {
+		if d.BootVlan.isDefault() {
+			return false, nil
+		}
+		conn := primaryBootConnection(profile)
+		if conn == nil {
+			log.Warnf("--oneview-boot-vlan is set but the profile of %s has no primary boot ethernet connection", d.MachineName)
+			return false, nil
+		}
+		id, _ := jsonInt(conn["id"])
+		uri, _ := conn["networkUri"].(string)
+		if uri == "" {
+			return false, fmt.Errorf("boot connection %d has no network", int(id))
+		}
+		n, err := d.readConnectionNetwork(uri, d.BootVlan.ID > 0)
+		if err != nil {
+			return false, err
+		}
+		if err := d.BootVlan.check(int(id), n); err != nil {
+			return false, err
+		}
+		return d.BootVlan.apply(conn), nil
+	}
+}
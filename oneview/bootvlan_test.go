@@ -0,0 +1,58 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBootVlan(t *testing.T) {
+	for in, want := range map[string]BootVlan{
+		"":          {},
+		" Untagged": {Untagged: true},
+		"100":       {ID: 100},
+	} {
+		b, err := parseBootVlan(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, b, in)
+		assert.Equal(t, in == "", b.isDefault(), in)
+	}
+	for _, in := range []string{"0", "4095", "tagged"} {
+		_, err := parseBootVlan(in)
+		assert.Equal(t, ErrDriverInvalidBootVlan, err, in)
+	}
+}
+
+func TestBootVlanCheck(t *testing.T) {
+	network := connectionNetwork{URI: "/rest/ethernet-networks/1", Vlans: []int{10}}
+	set := connectionNetwork{URI: "/rest/network-sets/1", Set: true, Native: 10, Vlans: []int{10, 20}}
+	noNative := connectionNetwork{URI: "/rest/network-sets/2", Set: true, Vlans: []int{10, 20}}
+
+	untagged := BootVlan{Untagged: true}
+	assert.NoError(t, untagged.check(1, network))
+	assert.NoError(t, untagged.check(1, set))
+	assert.Contains(t, untagged.check(1, noNative).Error(), "no native network")
+
+	tagged := BootVlan{ID: 20}
+	assert.NoError(t, tagged.check(1, set))
+	assert.NoError(t, tagged.check(1, noNative))
+	assert.Contains(t, tagged.check(1, network).Error(), "only sees untagged")
+	assert.Contains(t, BootVlan{ID: 10}.check(1, set).Error(), "untagged as the native network")
+	assert.Contains(t, BootVlan{ID: 30}.check(1, set).Error(), "does not carry vlan 30")
+}
+
+func TestBootVlanApply(t *testing.T) {
+	var profile map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"connections": [
+		{"id": 2, "functionType": "Ethernet", "boot": {"priority": "Primary", "bootVlanId": 20}},
+		{"id": 1, "functionType": "FibreChannel", "boot": {"priority": "Primary"}}]}`), &profile))
+	conn := primaryBootConnection(profile)
+	assert.Equal(t, float64(2), conn["id"])
+
+	assert.False(t, BootVlan{ID: 20}.apply(conn))
+	assert.True(t, BootVlan{Untagged: true}.apply(conn))
+	assert.NotContains(t, conn["boot"], "bootVlanId")
+	assert.True(t, BootVlan{ID: 30}.apply(conn))
+	assert.Equal(t, float64(30), conn["boot"].(map[string]interface{})["bootVlanId"])
+}
@@ -0,0 +1,182 @@
+package oneview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// CallStats - the appliance calls made during one driver operation
+type CallStats struct {
+	mu       sync.Mutex
+	Methods  map[string]int
+	Failed   int
+	Retries  int
+	CallTime time.Duration
+	started  time.Time
+	Elapsed  time.Duration
+
+	// Throttled - calls held back while the appliance was near its rate
+	// limit, for ThrottleTime in all
+	Throttled    int
+	ThrottleTime time.Duration
+
+	// clients - the appliance clients the calls are counted for
+	clients []interface{}
+}
+
+var (
+	callStatsMu sync.Mutex
+	// activeStats - the running operations by the appliance clients they
+	// call through.  A call counts for the operations on its client only, so
+	// operations on other machines running at the same time stay out, while
+	// an operation running another, ie; remove stopping the machine, counts
+	// for both.
+	activeStats = map[interface{}][]*CallStats{}
+)
+
+// startCallStats - count the calls made with clients from now until stop
+func startCallStats(clients ...interface{}) *CallStats {
+	s := &CallStats{Methods: map[string]int{}, started: time.Now()}
+	callStatsMu.Lock()
+	defer callStatsMu.Unlock()
+	for _, c := range clients {
+		if c == nil {
+			continue
+		}
+		activeStats[c] = append(activeStats[c], s)
+		s.clients = append(s.clients, c)
+	}
+	return s
+}
+
+// stop - stop counting
+func (s *CallStats) stop() {
+	callStatsMu.Lock()
+	defer callStatsMu.Unlock()
+	for _, c := range s.clients {
+		active := activeStats[c]
+		for i, a := range active {
+			if a == s {
+				active = append(active[:i:i], active[i+1:]...)
+				break
+			}
+		}
+		if len(active) == 0 {
+			delete(activeStats, c)
+		} else {
+			activeStats[c] = active
+		}
+	}
+	s.mu.Lock()
+	s.Elapsed = time.Since(s.started)
+	s.mu.Unlock()
+}
+
+// eachActive - run fn on the stats of every running operation on client
+func eachActive(client interface{}, fn func(s *CallStats)) {
+	callStatsMu.Lock()
+	active := append([]*CallStats{}, activeStats[client]...)
+	callStatsMu.Unlock()
+	for _, s := range active {
+		s.mu.Lock()
+		fn(s)
+		s.mu.Unlock()
+	}
+}
+
+// recordCall - count an appliance call made with client
+func recordCall(client interface{}, method string, took time.Duration, err error) {
+	eachActive(client, func(s *CallStats) {
+		s.Methods[strings.ToUpper(method)]++
+		s.CallTime += took
+		if err != nil {
+			s.Failed++
+		}
+	})
+}
+
+// libraryCall - run a call of the oneview library with client, the library
+// makes its own requests so they are counted here as one call of method,
+// and its errors carry the response like the rest clients' do
+func libraryCall(client interface{}, method string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	recordCall(client, method, time.Since(start), err)
+	return redactError(applianceError(err))
+}
+
+// recordRetry - count something done again with client after a failure
+func recordRetry(client interface{}) {
+	eachActive(client, func(s *CallStats) { s.Retries++ })
+}
+
+// recordThrottle - count a call with client held back for d
+func recordThrottle(client interface{}, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	eachActive(client, func(s *CallStats) {
+		s.Throttled++
+		s.ThrottleTime += d
+	})
+}
+
+// Total - the number of calls
+func (s *CallStats) Total() int {
+	n := 0
+	for _, c := range s.Methods {
+		n += c
+	}
+	return n
+}
+
+// Summary - ie; 42 calls (30 GET, 10 POST, 2 PUT) taking 12.3s of 4m10s, 3 failed, 1 retry
+func (s *CallStats) Summary() string {
+	methods := make([]string, 0, len(s.Methods))
+	for m := range s.Methods {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	var counts []string
+	for _, m := range methods {
+		counts = append(counts, fmt.Sprintf("%d %s", s.Methods[m], m))
+	}
+	summary := fmt.Sprintf("%d calls", s.Total())
+	if len(counts) > 0 {
+		summary += " (" + strings.Join(counts, ", ") + ")"
+	}
+	summary += fmt.Sprintf(" taking %s of %s", s.CallTime.Round(time.Millisecond), s.Elapsed.Round(time.Millisecond))
+	if s.Failed > 0 {
+		summary += fmt.Sprintf(", %d failed", s.Failed)
+	}
+	switch s.Retries {
+	case 0:
+	case 1:
+		summary += ", 1 retry"
+	default:
+		summary += fmt.Sprintf(", %d retries", s.Retries)
+	}
+	if s.Throttled > 0 {
+		summary += fmt.Sprintf(", %d throttled for %s", s.Throttled, s.ThrottleTime.Round(time.Millisecond))
+	}
+	return summary
+}
+
+// operation - run a driver operation as an event stage, logging the
+// appliance calls it made and warning when they go over the budget
+func (d *Driver) operation(name string, fn func() error) error {
+	stats := startCallStats(d.ClientOV, d.ClientICSP)
+	err := runStage(d.MachineName, name, fn)
+	stats.stop()
+	log.Debugf("%s %s: %s", name, d.MachineName, stats.Summary())
+	if d.CallBudget > 0 && stats.Total() > d.CallBudget {
+		log.Warnf("%s %s made %d appliance calls, over the budget of %d, look for one call per resource where one list would do : %s",
+			name, d.MachineName, stats.Total(), d.CallBudget, stats.Summary())
+	}
+	return classifyError(err)
+}
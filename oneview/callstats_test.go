@@ -0,0 +1,71 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallStatsCountsNestedOperations(t *testing.T) {
+	c := &ov.OVClient{}
+	outer := startCallStats(c)
+	recordCall(c, "get", time.Second, nil)
+	inner := startCallStats(c)
+	recordCall(c, "POST", time.Second, errors.New("boom"))
+	recordRetry(c)
+	inner.stop()
+	recordCall(c, "GET", 0, nil)
+	outer.stop()
+	recordCall(c, "GET", 0, nil)
+
+	assert.Equal(t, 1, inner.Total())
+	assert.Equal(t, 3, outer.Total())
+	assert.Equal(t, 2, outer.Methods["GET"])
+	assert.Equal(t, 1, outer.Failed)
+	assert.Equal(t, 1, outer.Retries)
+	assert.Equal(t, 2*time.Second, outer.CallTime)
+	assert.Empty(t, activeStats)
+}
+
+func TestCallStatsKeptPerOperation(t *testing.T) {
+	first, second := &ov.OVClient{}, &ov.OVClient{}
+	ic := &icsp.ICSPClient{}
+	a := startCallStats(first, ic)
+	b := startCallStats(second, (*icsp.ICSPClient)(nil))
+	recordCall(first, "GET", 0, nil)
+	recordCall(ic, "GET", 0, nil)
+	recordCall(second, "PUT", 0, nil)
+	recordThrottle(second, time.Second)
+	recordThrottle(first, 0)
+	err := libraryCall(first, "POST", func() error { return errors.New("boom") })
+	assert.Error(t, err)
+	a.stop()
+	b.stop()
+
+	assert.Equal(t, map[string]int{"GET": 2, "POST": 1}, a.Methods)
+	assert.Equal(t, 1, a.Failed)
+	assert.Equal(t, 0, a.Throttled)
+	assert.Equal(t, map[string]int{"PUT": 1}, b.Methods)
+	assert.Equal(t, 1, b.Throttled)
+}
+
+func TestCallStatsSummary(t *testing.T) {
+	s := &CallStats{
+		Methods:  map[string]int{"GET": 30, "POST": 10, "PUT": 2},
+		Failed:   3,
+		Retries:  1,
+		CallTime: 12300 * time.Millisecond,
+		Elapsed:  250 * time.Second,
+	}
+	assert.Equal(t, "42 calls (30 GET, 10 POST, 2 PUT) taking 12.3s of 4m10s, 3 failed, 1 retry", s.Summary())
+
+	s = &CallStats{Methods: map[string]int{}}
+	assert.Equal(t, "0 calls taking 0s of 0s", s.Summary())
+
+	s.Throttled, s.ThrottleTime = 2, 1500*time.Millisecond
+	assert.Equal(t, "0 calls taking 0s of 0s, 2 throttled for 1.5s", s.Summary())
+}
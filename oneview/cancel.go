@@ -0,0 +1,65 @@
+package oneview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// task cancellation errors
+var (
+	ErrCancelled          = errors.New("Cancelled by interrupt, running appliance work was asked to stop")
+	ErrTaskNotCancellable = errors.New("Task can not be cancelled")
+)
+
+var (
+	interruptOnce sync.Once
+	// interruptCtx - cancelled once the driver is interrupted, the parent of
+	// every wait on appliance work
+	interruptCtx, interrupt = context.WithCancel(context.Background())
+)
+
+// watchInterrupts - cancel interruptCtx on the first SIGINT or SIGTERM, a
+// second one gets the default behaviour and ends the process
+func watchInterrupts() {
+	interruptOnce.Do(func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			s := <-sig
+			signal.Stop(sig)
+			log.Warnf("Received %s, cancelling appliance work, interrupt again to quit now", s)
+			interrupt()
+		}()
+	})
+}
+
+// CancelTask - ask the appliance to stop a running task, finished tasks are
+// left alone and tasks that can not be cancelled return ErrTaskNotCancellable
+func CancelTask(c *ov.OVClient, uri string) error {
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return err
+	}
+	var t Task
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	if t.isDone() || t.TaskState == TaskStateCancelling {
+		return nil
+	}
+	if !t.IsCancellable {
+		return ErrTaskNotCancellable
+	}
+	log.Infof("Cancelling task %s (%s)", t.Name, uri)
+	_, err = ovCall(c, rest.PUT, uri, map[string]interface{}{"taskState": TaskStateCancelling})
+	return err
+}
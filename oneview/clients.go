@@ -0,0 +1,173 @@
+package oneview
+
+import (
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// SharedClients - the clients of the batch commands, so operating on many
+// machines in one invocation keeps one session per appliance user.
+// docker-machine runs a plugin process per machine, so a driver keeps its
+// own clients.
+var SharedClients = NewClientRegistry()
+
+// loginMu - only one login at a time, so drivers sharing a client that find
+// the session expired together log in once rather than each
+var loginMu sync.Mutex
+
+// clientKey - clients are shared when every connection setting is the same
+type clientKey struct {
+	Endpoint   string
+	User       string
+	Domain     string
+	Password   string
+	SSLVerify  bool
+	APIVersion int
+}
+
+func keyOf(c rest.Client) clientKey {
+	return clientKey{
+		Endpoint:   c.Endpoint,
+		User:       c.User,
+		Domain:     c.Domain,
+		Password:   c.Password,
+		SSLVerify:  c.SSLVerify,
+		APIVersion: c.APIVersion,
+	}
+}
+
+type sharedOV struct {
+	client *ov.OVClient
+	refs   int
+}
+
+type sharedICSP struct {
+	client *icsp.ICSPClient
+	refs   int
+}
+
+// ClientRegistry - authenticated clients keyed by endpoint and user, with a
+// count of the drivers using each
+type ClientRegistry struct {
+	mu   sync.Mutex
+	ov   map[clientKey]*sharedOV
+	icsp map[clientKey]*sharedICSP
+}
+
+// NewClientRegistry - an empty registry
+func NewClientRegistry() *ClientRegistry {
+	return &ClientRegistry{
+		ov:   map[clientKey]*sharedOV{},
+		icsp: map[clientKey]*sharedICSP{},
+	}
+}
+
+// OV - the registered client with the settings of c, c itself when there is
+// none yet.  Each call takes a reference, give it back with ReleaseOV.
+func (r *ClientRegistry) OV(c *ov.OVClient) *ov.OVClient {
+	if c == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := keyOf(c.Client)
+	s, ok := r.ov[key]
+	if !ok {
+		s = &sharedOV{client: c}
+		r.ov[key] = s
+	}
+	s.refs++
+	return s.client
+}
+
+// ReleaseOV - give back a reference, true when it was the last one and the
+// session can be logged out, false for a client the registry never gave out
+func (r *ClientRegistry) ReleaseOV(c *ov.OVClient) bool {
+	last, _ := r.releaseOV(c)
+	return last
+}
+
+// releaseOV - ReleaseOV, held is false for a client the registry never
+// gave out
+func (r *ClientRegistry) releaseOV(c *ov.OVClient) (last, held bool) {
+	if c == nil {
+		return false, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, s := range r.ov {
+		if s.client != c {
+			continue
+		}
+		if s.refs--; s.refs > 0 {
+			return false, true
+		}
+		delete(r.ov, key)
+		return true, true
+	}
+	return false, false
+}
+
+// ICSP - the registered client with the settings of c, c itself when there
+// is none yet.  Each call takes a reference, give it back with ReleaseICSP.
+func (r *ClientRegistry) ICSP(c *icsp.ICSPClient) *icsp.ICSPClient {
+	if c == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := keyOf(c.Client)
+	s, ok := r.icsp[key]
+	if !ok {
+		s = &sharedICSP{client: c}
+		r.icsp[key] = s
+	}
+	s.refs++
+	return s.client
+}
+
+// ReleaseICSP - give back a reference, true when it was the last one and
+// the session can be logged out, false for a client the registry never
+// gave out
+func (r *ClientRegistry) ReleaseICSP(c *icsp.ICSPClient) bool {
+	last, _ := r.releaseICSP(c)
+	return last
+}
+
+// releaseICSP - ReleaseICSP, held is false for a client the registry never
+// gave out
+func (r *ClientRegistry) releaseICSP(c *icsp.ICSPClient) (last, held bool) {
+	if c == nil {
+		return false, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, s := range r.icsp {
+		if s.client != c {
+			continue
+		}
+		if s.refs--; s.refs > 0 {
+			return false, true
+		}
+		delete(r.icsp, key)
+		return true, true
+	}
+	return false, false
+}
+
+// refreshOV - log in again when the session expired, one login at a time
+func refreshOV(c *ov.OVClient) error {
+	loginMu.Lock()
+	defer loginMu.Unlock()
+	return applianceError(c.RefreshLogin())
+}
+
+// refreshICSP - log in again when the session expired, one login at a time
+func refreshICSP(c *icsp.ICSPClient) error {
+	loginMu.Lock()
+	defer loginMu.Unlock()
+	return applianceError(c.RefreshLogin())
+}
@@ -0,0 +1,56 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientRegistryOV(t *testing.T) {
+	r := NewClientRegistry()
+	newClient := func(user string) *ov.OVClient {
+		c := &ov.OVClient{}
+		c.Endpoint, c.User, c.Password, c.APIVersion = "https://ov", user, "secret", 200
+		return c
+	}
+	first := r.OV(newClient("admin"))
+	assert.True(t, first == r.OV(newClient("admin")), "same settings share a client")
+	other := r.OV(newClient("operator"))
+	assert.False(t, first == other, "another user gets its own client")
+
+	assert.False(t, r.ReleaseOV(first))
+	assert.True(t, r.ReleaseOV(first))
+	assert.True(t, r.ReleaseOV(other))
+	assert.False(t, r.ReleaseOV(first), "a released client is not logged out twice")
+	assert.False(t, r.ReleaseOV(newClient("admin")), "clients the registry never gave out are left alone")
+	assert.Nil(t, r.OV(nil))
+}
+
+func TestClientRegistryICSP(t *testing.T) {
+	r := NewClientRegistry()
+	a, b := &icsp.ICSPClient{}, &icsp.ICSPClient{}
+	a.Endpoint, b.Endpoint = "https://icsp", "https://icsp"
+	assert.True(t, a == r.ICSP(a))
+	assert.True(t, a == r.ICSP(b))
+	assert.False(t, r.ReleaseICSP(a))
+	assert.True(t, r.ReleaseICSP(a))
+	assert.False(t, r.ReleaseICSP(a))
+}
+
+func TestDriverKeepsItsOwnClients(t *testing.T) {
+	held := func() int {
+		SharedClients.mu.Lock()
+		defer SharedClients.mu.Unlock()
+		return len(SharedClients.ov)
+	}
+	before := held()
+	d := &Driver{}
+	assert.NoError(t, d.UnmarshalJSON([]byte(`{"ClientOV": {"Endpoint": "https://ov-own", "User": "admin"}}`)))
+	assert.Equal(t, before, held(), "loading a driver takes no reference")
+
+	last, registered := SharedClients.releaseOV(d.ClientOV)
+	assert.False(t, last)
+	assert.False(t, registered, "closeAll logs out a client of the driver's own")
+}
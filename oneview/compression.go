@@ -0,0 +1,43 @@
+package oneview
+
+import (
+	"sync"
+)
+
+var (
+	compressionMu sync.RWMutex
+	// compressionDisabled - ask the appliance for uncompressed responses
+	compressionDisabled bool
+)
+
+// SetCompression - turn gzip responses on or off for every following
+// request.  Go's http transport asks for gzip and decompresses on its own,
+// which the rest clients and the driver's own http calls get for free, large
+// list pages shrink several times over.  Some appliance versions and proxies
+// send broken gzip streams, turning compression off asks for identity
+// encoding instead.
+func SetCompression(enabled bool) {
+	compressionMu.Lock()
+	defer compressionMu.Unlock()
+	compressionDisabled = !enabled
+}
+
+// compressionEnabled - false after SetCompression(false)
+func compressionEnabled() bool {
+	compressionMu.RLock()
+	defer compressionMu.RUnlock()
+	return !compressionDisabled
+}
+
+// compressionHook - an explicit Accept-Encoding stops the transport from
+// asking for gzip, so the appliance answers uncompressed
+func compressionHook(method, url string, headers map[string]string) error {
+	if !compressionEnabled() {
+		headers["Accept-Encoding"] = "identity"
+	}
+	return nil
+}
+
+func init() {
+	AddRequestHook(compressionHook)
+}
@@ -0,0 +1,147 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/mcnutils"
+)
+
+// GetRemoteConsoleURL - a single sign on link opening the iLO integrated
+// remote console of server hardware, ie; hplocons://addr=...&sessionkey=...
+// The session key stands in for iLO credentials and expires with the session.
+func GetRemoteConsoleURL(c *ov.OVClient, hardwareURI string) (string, error) {
+	data, err := ovCall(c, rest.GET, hardwareURI+"/remoteConsoleUrl", nil)
+	if err != nil {
+		return "", err
+	}
+	var r struct {
+		RemoteConsoleURL string `json:"remoteConsoleUrl"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return "", err
+	}
+	if r.RemoteConsoleURL == "" {
+		return "", fmt.Errorf("appliance returned no remote console link for %s", hardwareURI)
+	}
+	return r.RemoteConsoleURL, nil
+}
+
+// GetILOSSOURL - a single sign on link to the iLO web interface of server
+// hardware, for browsers that can not open the remote console application
+func GetILOSSOURL(c *ov.OVClient, hardwareURI string) (string, error) {
+	data, err := ovCall(c, rest.GET, hardwareURI+"/iloSsoUrl", nil)
+	if err != nil {
+		return "", err
+	}
+	var r struct {
+		ILOSSOURL string `json:"iloSsoUrl"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return "", err
+	}
+	if r.ILOSSOURL == "" {
+		return "", fmt.Errorf("appliance returned no iLO link for %s", hardwareURI)
+	}
+	return r.ILOSSOURL, nil
+}
+
+// GetRemoteConsoleURL - the remote console link of the hardware, see
+// GetRemoteConsoleURL
+func (h ServerHardwareDetail) GetRemoteConsoleURL(c *ov.OVClient) (string, error) {
+	return GetRemoteConsoleURL(c, h.URI)
+}
+
+// GetIloSsoURL - the iLO web interface link of the hardware, see
+// GetILOSSOURL
+func (h ServerHardwareDetail) GetIloSsoURL(c *ov.OVClient) (string, error) {
+	return GetILOSSOURL(c, h.URI)
+}
+
+// RemoteConsoleURL - the remote console link for the machine blade, or the
+// iLO web link when web is set
+func (d *Driver) RemoteConsoleURL(web bool) (string, error) {
+	var profile ov.ServerProfile
+	err := libraryCall(d.ClientOV, "GET", func() (err error) {
+		profile, err = d.ClientOV.GetProfileByName(d.MachineName)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if profile.URI.IsNil() || profile.ServerHardwareURI.IsNil() {
+		return "", fmt.Errorf("unable to find the server hardware of machine %s in oneview", d.MachineName)
+	}
+	if web {
+		return GetILOSSOURL(d.ClientOV, profile.ServerHardwareURI.String())
+	}
+	return GetRemoteConsoleURL(d.ClientOV, profile.ServerHardwareURI.String())
+}
+
+// machineStorePath - where docker-machine keeps machines, following
+// MACHINE_STORAGE_PATH like docker-machine does
+func machineStorePath() string {
+	if p := os.Getenv("MACHINE_STORAGE_PATH"); p != "" {
+		return p
+	}
+	return filepath.Join(mcnutils.GetHomeDir(), ".docker", "machine")
+}
+
+// machineConfigPath - the docker-machine config of a machine
+func machineConfigPath(machine string) string {
+	return filepath.Join(machineStorePath(), "machines", machine, "config.json")
+}
+
+// LoadDriver - the driver of a machine created with this driver, read from
+// the docker-machine store, for helpers running outside docker-machine
+func LoadDriver(machine string) (*Driver, error) {
+	path := machineConfigPath(machine)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read machine %s : %w", machine, err)
+	}
+	var host struct {
+		DriverName string
+		Driver     json.RawMessage
+	}
+	if err := json.Unmarshal(data, &host); err != nil {
+		return nil, fmt.Errorf("unable to read machine %s : %w", machine, err)
+	}
+	if host.DriverName != driverName {
+		return nil, fmt.Errorf("machine %s uses the %s driver, not %s", machine, host.DriverName, driverName)
+	}
+	d := NewDriver(machine, machineStorePath()).(*Driver)
+	if err := json.Unmarshal(host.Driver, d); err != nil {
+		return nil, fmt.Errorf("unable to read machine %s driver settings : %w", machine, err)
+	}
+	if d.ClientOV == nil {
+		return nil, fmt.Errorf("machine %s has no OneView settings", machine)
+	}
+	return d, nil
+}
+
+// SaveDriver - write the driver back to its machine in the docker-machine
+// store, the rest of the machine config is kept as docker-machine wrote it
+func SaveDriver(d *Driver) error {
+	path := machineConfigPath(d.MachineName)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read machine %s : %w", d.MachineName, err)
+	}
+	var host map[string]json.RawMessage
+	if err := json.Unmarshal(data, &host); err != nil {
+		return fmt.Errorf("unable to read machine %s : %w", d.MachineName, err)
+	}
+	if host["Driver"], err = json.Marshal(d); err != nil {
+		return err
+	}
+	if data, err = json.MarshalIndent(host, "", "    "); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
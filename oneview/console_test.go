@@ -0,0 +1,61 @@
+package oneview
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer os.Setenv("MACHINE_STORAGE_PATH", os.Getenv("MACHINE_STORAGE_PATH"))
+	os.Setenv("MACHINE_STORAGE_PATH", dir)
+
+	write := func(machine, config string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "machines", machine), 0700))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "machines", machine, "config.json"), []byte(config), 0600))
+	}
+	write("docker1", `{"DriverName": "oneview", "Driver": {"ServerTemplate": "DOCKER", "ClientOV": {"Endpoint": "https://ov"}}}`)
+	write("vbox", `{"DriverName": "virtualbox", "Driver": {}}`)
+
+	d, err := LoadDriver("docker1")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker1", d.MachineName)
+	assert.Equal(t, "DOCKER", d.ServerTemplate)
+	assert.Equal(t, "https://ov", d.ClientOV.Endpoint)
+
+	_, err = LoadDriver("vbox")
+	assert.EqualError(t, err, "machine vbox uses the virtualbox driver, not oneview")
+
+	_, err = LoadDriver("missing")
+	assert.Error(t, err)
+}
+
+func TestSaveDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer os.Setenv("MACHINE_STORAGE_PATH", os.Getenv("MACHINE_STORAGE_PATH"))
+	os.Setenv("MACHINE_STORAGE_PATH", dir)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "machines", "docker1"), 0700))
+	config := `{"ConfigVersion": 3, "DriverName": "oneview", "Driver": {"ServerTemplate": "DOCKER", "ClientOV": {"Endpoint": "https://ov"}}, "Name": "docker1"}`
+	assert.NoError(t, ioutil.WriteFile(machineConfigPath("docker1"), []byte(config), 0600))
+
+	d, err := LoadDriver("docker1")
+	assert.NoError(t, err)
+	d.IPAddress = "10.0.0.9"
+	assert.NoError(t, SaveDriver(d))
+
+	d, err = LoadDriver("docker1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.9", d.IPAddress)
+	assert.Equal(t, "DOCKER", d.ServerTemplate)
+	data, err := ioutil.ReadFile(machineConfigPath("docker1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"ConfigVersion": 3`)
+}
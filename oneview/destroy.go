@@ -0,0 +1,196 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// ApplianceConfig - how to reach the appliances, for library calls made
+// without a docker-machine driver.  With an OVSessionToken the OneView
+// session it names is used rather than logging in with the user and password.
+type ApplianceConfig struct {
+	OVEndpoint     string
+	OVUser         string
+	OVPassword     string
+	OVDomain       string
+	OVAPIVersion   int
+	OVSessionToken string
+	ICSPEndpoint   string
+	ICSPUser       string
+	ICSPPassword   string
+	ICSPDomain     string
+	ICSPAPIVersion int
+	SSLVerify      bool
+}
+
+// ApplianceConfigFromEnv - the appliance settings from the ONEVIEW_*
+// environment variables the driver options read, with the same defaults
+func ApplianceConfigFromEnv() ApplianceConfig {
+	envInt := func(name string, def int) int {
+		if n, err := strconv.Atoi(os.Getenv(name)); err == nil {
+			return n
+		}
+		return def
+	}
+	envOr := func(name, def string) string {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+		return def
+	}
+	sslVerify, _ := strconv.ParseBool(os.Getenv("ONEVIEW_SSLVERIFY"))
+	return ApplianceConfig{
+		OVEndpoint:     os.Getenv("ONEVIEW_OV_ENDPOINT"),
+		OVUser:         os.Getenv("ONEVIEW_OV_USER"),
+		OVPassword:     os.Getenv("ONEVIEW_OV_PASSWORD"),
+		OVDomain:       envOr("ONEVIEW_OV_DOMAIN", "LOCAL"),
+		OVAPIVersion:   envInt("ONEVIEW_OV_APIVERSION", 201),
+		OVSessionToken: os.Getenv("ONEVIEW_OV_SESSION_TOKEN"),
+		ICSPEndpoint:   os.Getenv("ONEVIEW_ICSP_ENDPOINT"),
+		ICSPUser:       os.Getenv("ONEVIEW_ICSP_USER"),
+		ICSPPassword:   os.Getenv("ONEVIEW_ICSP_PASSWORD"),
+		ICSPDomain:     envOr("ONEVIEW_ICSP_DOMAIN", "LOCAL"),
+		ICSPAPIVersion: envInt("ONEVIEW_ICSP_APIVERSION", 200),
+		SSLVerify:      sslVerify,
+	}
+}
+
+// clients - logged out clients for the appliances, no icsp client without
+// an icsp endpoint
+func (cfg ApplianceConfig) clients() (*ov.OVClient, *icsp.ICSPClient, error) {
+	if cfg.OVEndpoint == "" {
+		return nil, nil, ErrDriverMissingEndPointOptionOV
+	}
+	ovEndpoint, err := normalizeEndpoint(cfg.OVEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	var c *ov.OVClient
+	if cfg.OVSessionToken != "" {
+		if c, err = NewOVClientFromToken(ovEndpoint, cfg.OVSessionToken, cfg.SSLVerify, cfg.OVAPIVersion); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		c = SharedClients.OV(c.NewOVClient(cfg.OVUser, cfg.OVPassword, cfg.OVDomain, ovEndpoint, cfg.SSLVerify, cfg.OVAPIVersion))
+	}
+	if cfg.ICSPEndpoint == "" {
+		return c, nil, nil
+	}
+	icspEndpoint, err := normalizeEndpoint(cfg.ICSPEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ic *icsp.ICSPClient
+	ic = SharedClients.ICSP(ic.NewICSPClient(cfg.ICSPUser, cfg.ICSPPassword, cfg.ICSPDomain, icspEndpoint, cfg.SSLVerify, cfg.ICSPAPIVersion))
+	return c, ic, nil
+}
+
+// ErrNotMachineProfile - the profile has no docker-machine metadata for the
+// machine being destroyed
+var ErrNotMachineProfile = errors.New("was not created by docker-machine for this machine, use --force to destroy it anyway")
+
+// checkMachineProfile - only profiles whose description metadata names the
+// machine are destroyed, unless forced
+func checkMachineProfile(profile ov.ServerProfile, name string, force bool) error {
+	if force {
+		return nil
+	}
+	_, m, err := DecodeDescription(profile.Description)
+	if err != nil || m == nil || m.Machine != name {
+		return fmt.Errorf("server profile %s %w", name, ErrNotMachineProfile)
+	}
+	return nil
+}
+
+// DestroyByName - remove what the appliances hold for a machine, its icsp
+// server, server profile and the profile identities, without the machine
+// directory docker-machine keeps, ie; after it was lost with a laptop.
+// Anything already gone is skipped, so running it again after a failure
+// finishes the job, and a machine with nothing left is not an error.
+// Profiles without the machine's docker-machine metadata are only
+// destroyed with force.
+func DestroyByName(cfg ApplianceConfig, name string, force bool) error {
+	c, ic, err := cfg.clients()
+	if err != nil {
+		return err
+	}
+	d := &Driver{ClientOV: c, ClientICSP: ic}
+	defer closeAll(d)
+
+	profile, err := c.GetProfileByName(name)
+	if err != nil {
+		return err
+	}
+	if profile.URI.IsNil() {
+		log.Infof("No server profile %s, nothing left to destroy in oneview", name)
+		return nil
+	}
+	if err := checkMachineProfile(profile, name, force); err != nil {
+		return err
+	}
+	if !profile.ServerHardwareURI.IsNil() {
+		hardware, err := c.GetServerHardware(profile.ServerHardwareURI)
+		if err != nil {
+			return err
+		}
+		if err := destroyICSPServer(ic, hardware, name); err != nil {
+			return err
+		}
+		// the profile can only be removed from powered off hardware
+		if err := hardware.PowerOff(); err != nil {
+			return fmt.Errorf("unable to power off %s: %w", name, err)
+		}
+	}
+	identities := map[string][]string{}
+	if raw, err := getResourceMap(c, profile.URI.String()); err == nil {
+		identities = profileIdentities(raw)
+	}
+	if err := DeleteProfile(c, name); err != nil {
+		return err
+	}
+	left, err := ReclaimIdentities(c, identities, false)
+	if err != nil {
+		log.Warnf("Unable to check the identities of %s went back to their pools : %s", name, err)
+	}
+	for pool, ids := range left {
+		log.Warnf("%s identities of %s are still allocated : %s", pool, name, strings.Join(ids, ", "))
+	}
+	log.Infof("Destroyed %s", name)
+	return nil
+}
+
+// destroyICSPServer - delete the icsp server of the hardware when icsp still
+// has one
+func destroyICSPServer(ic *icsp.ICSPClient, hardware ov.ServerHardware, name string) error {
+	if ic == nil {
+		log.Warnf("No icsp endpoint, the icsp server of %s is left alone", name)
+		return nil
+	}
+	serial := hardware.SerialNumber.String()
+	if !hardware.VirtualSerialNumber.IsNil() {
+		serial = hardware.VirtualSerialNumber.String()
+	}
+	server, err := ic.GetServerBySerialNumber(serial)
+	if err != nil {
+		return err
+	}
+	if server.MID == "" {
+		log.Debugf("no icsp server for %s", name)
+		return nil
+	}
+	isDeleted, err := ic.DeleteServer(server.MID)
+	if err != nil {
+		return err
+	}
+	if !isDeleted {
+		return fmt.Errorf("Unable to delete the server from icsp : %s, %s", name, server.MID)
+	}
+	return nil
+}
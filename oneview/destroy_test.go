@@ -0,0 +1,50 @@
+package oneview
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplianceConfigFromEnv(t *testing.T) {
+	for k, v := range map[string]string{
+		"ONEVIEW_OV_ENDPOINT":   "https://ov",
+		"ONEVIEW_OV_USER":       "admin",
+		"ONEVIEW_OV_APIVERSION": "800",
+		"ONEVIEW_SSLVERIFY":     "true",
+	} {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+	cfg := ApplianceConfigFromEnv()
+	assert.Equal(t, "https://ov", cfg.OVEndpoint)
+	assert.Equal(t, "admin", cfg.OVUser)
+	assert.Equal(t, "LOCAL", cfg.OVDomain)
+	assert.Equal(t, 800, cfg.OVAPIVersion)
+	assert.Equal(t, 200, cfg.ICSPAPIVersion)
+	assert.True(t, cfg.SSLVerify)
+}
+
+func TestDestroyByNameNeedsEndpoint(t *testing.T) {
+	assert.Equal(t, ErrDriverMissingEndPointOptionOV, DestroyByName(ApplianceConfig{}, "docker1", false))
+}
+
+func TestCheckMachineProfile(t *testing.T) {
+	owned, err := EncodeDescription("swarm node", MachineMetadata{Machine: "docker1"})
+	assert.NoError(t, err)
+	assert.NoError(t, checkMachineProfile(ov.ServerProfile{Description: owned}, "docker1", false))
+
+	for _, description := range []string{"", "hand made", owned, "docker-machine:{broken"} {
+		name := "docker1"
+		if description == owned {
+			name = "docker2"
+		}
+		err := checkMachineProfile(ov.ServerProfile{Description: description}, name, false)
+		assert.True(t, errors.Is(err, ErrNotMachineProfile), description)
+		assert.Equal(t, CategoryUser, CategoryOf(err))
+		assert.NoError(t, checkMachineProfile(ov.ServerProfile{Description: description}, name, true))
+	}
+}
@@ -0,0 +1,299 @@
+package oneview
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	defaultChunkSize    = 32 * 1024 * 1024
+	defaultChunkRetries = 5
+	downloadPartialExt  = ".part"
+)
+
+// downloadRetryBackoff - pause before asking for a failed chunk again
+var downloadRetryBackoff = 2 * time.Second
+
+var (
+	// ErrChecksumMismatch - the downloaded file does not match the expected checksum
+	ErrChecksumMismatch = errors.New("downloaded file does not match the expected sha256 checksum")
+	// ErrChunkMismatch - a range does not match the checksum the appliance sent with it
+	ErrChunkMismatch = errors.New("downloaded range does not match the checksum sent with it")
+)
+
+// Downloader - fetches large appliance artifacts, like backups and support
+// dumps, in ranged chunks so a dropped connection only costs one chunk
+type Downloader struct {
+	Client     *ov.OVClient
+	HTTPClient *http.Client
+	// ChunkSize - bytes requested per range
+	ChunkSize int64
+	// Retries - attempts per chunk before giving up
+	Retries int
+	// Progress - where download progress is reported
+	Progress ProgressSink
+}
+
+// NewDownloader - downloader with default chunk size and retries
+func NewDownloader(c *ov.OVClient) *Downloader {
+	return &Downloader{
+		Client:     c,
+		HTTPClient: newApplianceHTTPClient(c.SSLVerify),
+		ChunkSize:  defaultChunkSize,
+		Retries:    defaultChunkRetries,
+		Progress:   defaultProgress(),
+	}
+}
+
+// Download - download uri to dest, resuming from dest.part when a previous
+// attempt was interrupted.  Each range is checked against what it claims to
+// cover, and against its checksum when the appliance sends one, and only a
+// bad range is asked for again.  When sha256sum is set the finished file is
+// verified too; as every range was already checked a mismatch means the
+// artifact itself is not the expected one, so the partial file is removed
+// rather than downloaded again.
+func (dl *Downloader) Download(uri, dest, sha256sum string) error {
+	part := dest + downloadPartialExt
+	if err := dl.fetch(uri, part); err != nil {
+		return err
+	}
+	if sha256sum != "" {
+		ok, err := fileMatchesSHA256(part, sha256sum)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := os.Remove(part); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, uri)
+		}
+	}
+	return os.Rename(part, dest)
+}
+
+// DownloadArtifact - download uri, ie; a backup or support dump, from the
+// OneView appliance of cfg to dest, see Downloader
+func DownloadArtifact(cfg ApplianceConfig, uri, dest, sha256sum string) error {
+	c, ic, err := cfg.clients()
+	if err != nil {
+		return err
+	}
+	defer closeAll(&Driver{ClientOV: c, ClientICSP: ic})
+	return NewDownloader(c).Download(uri, dest, sha256sum)
+}
+
+// fetch - fill the partial file until the whole resource is downloaded
+func (dl *Downloader) fetch(uri, part string) error {
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		log.Infof("resuming download of %s at %d bytes", uri, offset)
+	}
+	out := dl.Progress
+	if out == nil {
+		out = defaultProgress()
+	}
+	pw := newProgressWriter(f, out, offset, 0, uri, "Downloading")
+	for {
+		var n, total int64
+		for try := 1; ; try++ {
+			n, total, err = dl.fetchChunk(uri, pw, offset, pw.total)
+			if err == nil {
+				break
+			}
+			if try >= dl.Retries {
+				return fmt.Errorf("unable to download %s at offset %d after %d tries: %w", uri, offset, try, err)
+			}
+			log.Warnf("retrying chunk at %d of %s : %s", offset, uri, err)
+			recordRetry(dl.Client)
+			// throw away whatever part of the chunk made it to disk
+			if err := f.Truncate(offset); err != nil {
+				return err
+			}
+			if _, err := f.Seek(offset, io.SeekStart); err != nil {
+				return err
+			}
+			pw.current = offset
+			var busy *BusyError
+			if errors.As(err, &busy) {
+				time.Sleep(busy.RetryAfter)
+			} else {
+				time.Sleep(downloadRetryBackoff)
+			}
+		}
+		offset += n
+		pw.total = total
+		if offset >= total {
+			pw.update(true)
+			return nil
+		}
+	}
+}
+
+// fetchChunk - request one range and verify the appliance sent exactly that
+// range of a resource still known bytes long, 0 before the first range.
+// Returns the bytes written and the total size of the resource.
+func (dl *Downloader) fetchChunk(uri string, w io.Writer, offset, known int64) (int64, int64, error) {
+	req, err := newApplianceRequest(dl.Client, "GET", uri)
+	if err != nil {
+		return 0, 0, err
+	}
+	end := offset + dl.ChunkSize - 1
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, end))
+
+	resp, err := dl.HTTPClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		// already have every byte
+		var total int64
+		fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes */%d", &total)
+		if total == offset {
+			return 0, total, nil
+		}
+		return 0, 0, fmt.Errorf("range %d- not satisfiable, resource is %d bytes", offset, total)
+	case http.StatusOK:
+		// no range support, only usable when starting from the beginning
+		if offset != 0 {
+			return 0, 0, fmt.Errorf("appliance does not support ranged downloads for %s", uri)
+		}
+		n, err := copyChunk(w, resp, resp.ContentLength)
+		return n, n, err
+	default:
+		if err := busyFromResponse(resp, time.Now()); err != nil {
+			return 0, 0, err
+		}
+		return 0, 0, &ApplianceError{StatusCode: resp.StatusCode, Err: fmt.Errorf("unexpected status %s", resp.Status)}
+	}
+
+	start, last, total, err := parseContentRange(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return 0, 0, err
+	}
+	// a shorter range than asked for is allowed, one starting elsewhere or
+	// running past it is not
+	if start != offset || last > end {
+		return 0, 0, fmt.Errorf("asked for range %d-%d, got %d-%d", offset, end, start, last)
+	}
+	if known > 0 && total != known {
+		return 0, 0, fmt.Errorf("resource changed from %d to %d bytes during the download", known, total)
+	}
+	n, err := copyChunk(w, resp, last-start+1)
+	if err != nil {
+		return 0, 0, fmt.Errorf("range %d-%d : %w", start, last, err)
+	}
+	return n, total, nil
+}
+
+// copyChunk - copy a response body of want bytes, -1 when unknown, and check
+// it against the checksum the appliance sent with it
+func copyChunk(w io.Writer, resp *http.Response, want int64) (int64, error) {
+	digest, err := parseChunkDigest(resp.Header)
+	if err != nil {
+		return 0, err
+	}
+	if digest != nil {
+		w = io.MultiWriter(w, digest)
+	}
+	var n int64
+	if want < 0 {
+		n, err = io.Copy(w, resp.Body)
+	} else if n, err = io.CopyN(w, resp.Body, want); err != nil {
+		return n, fmt.Errorf("short read, got %d of %d bytes : %w", n, want, err)
+	}
+	if err != nil {
+		return n, err
+	}
+	if extra, _ := io.CopyN(ioutil.Discard, resp.Body, 1); extra > 0 {
+		return n, fmt.Errorf("more than the %d bytes claimed", want)
+	}
+	if digest != nil && !bytes.Equal(digest.Sum(nil), digest.want) {
+		return n, fmt.Errorf("%w, %s", ErrChunkMismatch, digest.name)
+	}
+	return n, nil
+}
+
+// chunkDigest - the checksum an appliance sent with a response
+type chunkDigest struct {
+	hash.Hash
+	name string
+	want []byte
+}
+
+// parseChunkDigest - the sha-256 of a Digest header, or else Content-MD5,
+// nil when the response has neither
+func parseChunkDigest(h http.Header) (*chunkDigest, error) {
+	for _, d := range strings.Split(h.Get("Digest"), ",") {
+		i := strings.Index(d, "=")
+		if i < 0 || !strings.EqualFold(strings.TrimSpace(d[:i]), "sha-256") {
+			continue
+		}
+		want, err := base64.StdEncoding.DecodeString(strings.TrimSpace(d[i+1:]))
+		if err != nil || len(want) != sha256.Size {
+			return nil, fmt.Errorf("invalid Digest %q", d)
+		}
+		return &chunkDigest{Hash: sha256.New(), name: "sha-256", want: want}, nil
+	}
+	if m := strings.TrimSpace(h.Get("Content-MD5")); m != "" {
+		want, err := base64.StdEncoding.DecodeString(m)
+		if err != nil || len(want) != md5.Size {
+			return nil, fmt.Errorf("invalid Content-MD5 %q", m)
+		}
+		return &chunkDigest{Hash: md5.New(), name: "md5", want: want}, nil
+	}
+	return nil, nil
+}
+
+// parseContentRange - parse a header like bytes 0-99/1000
+func parseContentRange(h string) (start, last, total int64, err error) {
+	if _, err = fmt.Sscanf(strings.TrimSpace(h), "bytes %d-%d/%d", &start, &last, &total); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", h)
+	}
+	if last < start || total <= last {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", h)
+	}
+	return start, last, total, nil
+}
+
+// fileMatchesSHA256 - compare the sha256 of a file with a hex checksum
+func fileMatchesSHA256(path, sum string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return false, err
+	}
+	return strings.EqualFold(hex.EncodeToString(h.Sum(nil)), strings.TrimSpace(sum)), nil
+}
@@ -0,0 +1,168 @@
+package oneview
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDownloadServer - serves content with range support, failing every
+// third request to simulate a flaky link
+func newTestDownloadServer(content []byte) *httptest.Server {
+	requests := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests%3 == 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeContent(w, r, "dump.sdmp", time.Time{}, bytes.NewReader(content))
+	}))
+}
+
+func TestDownloaderResume(t *testing.T) {
+	downloadRetryBackoff = 0
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	ts := newTestDownloadServer(content)
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "oneview-download")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dest := filepath.Join(dir, "dump.sdmp")
+
+	// pretend an earlier attempt got part of the way
+	assert.NoError(t, ioutil.WriteFile(dest+downloadPartialExt, content[:1234], 0600))
+
+	sum := sha256.Sum256(content)
+	dl := &Downloader{
+		Client:     &ov.OVClient{Client: rest.Client{Endpoint: ts.URL}},
+		HTTPClient: http.DefaultClient,
+		ChunkSize:  999,
+		Retries:    3,
+	}
+	err = dl.Download("/rest/appliance/support-dumps/dump.sdmp", dest, hex.EncodeToString(sum[:]))
+	assert.NoError(t, err)
+
+	got, err := ioutil.ReadFile(dest)
+	assert.NoError(t, err)
+	assert.Equal(t, content, got)
+	_, err = os.Stat(dest + downloadPartialExt)
+	assert.True(t, os.IsNotExist(err))
+}
+
+// newTestRangeServer - serves ranges of content with a Digest header, the
+// first answer for the range at corruptAt has a flipped byte.  requests
+// counts the requests for each range start.
+func newTestRangeServer(content []byte, corruptAt int64, requests map[int64]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start, last int64
+		fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &last)
+		if last >= int64(len(content)) {
+			last = int64(len(content)) - 1
+		}
+		requests[start]++
+		chunk := append([]byte{}, content[start:last+1]...)
+		sum := sha256.Sum256(chunk)
+		if start == corruptAt && requests[start] == 1 {
+			chunk[0] ^= 0xff
+		}
+		w.Header().Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum[:]))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, last, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(chunk)
+	}))
+}
+
+func TestDownloaderRetriesBadRange(t *testing.T) {
+	downloadRetryBackoff = 0
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	requests := map[int64]int{}
+	ts := newTestRangeServer(content, 2000, requests)
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "oneview-download")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dest := filepath.Join(dir, "backup.bkp")
+
+	dl := &Downloader{
+		Client:     &ov.OVClient{Client: rest.Client{Endpoint: ts.URL}},
+		HTTPClient: http.DefaultClient,
+		ChunkSize:  1000,
+		Retries:    3,
+	}
+	assert.NoError(t, dl.Download("/rest/backups/archive/backup.bkp", dest, ""))
+	got, err := ioutil.ReadFile(dest)
+	assert.NoError(t, err)
+	assert.Equal(t, content, got)
+	assert.Equal(t, 2, requests[2000], "the bad range is asked for again")
+	assert.Equal(t, 1, requests[0])
+	assert.Equal(t, 1, requests[9000], "the other ranges are not")
+}
+
+func TestDownloaderChecksumMismatch(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	ts := newTestRangeServer(content, -1, map[int64]int{})
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "oneview-download")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dest := filepath.Join(dir, "backup.bkp")
+
+	dl := &Downloader{
+		Client:     &ov.OVClient{Client: rest.Client{Endpoint: ts.URL}},
+		HTTPClient: http.DefaultClient,
+		ChunkSize:  300,
+		Retries:    1,
+	}
+	err = dl.Download("/rest/backups/archive/backup.bkp", dest, strings.Repeat("0", 64))
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	_, err = os.Stat(dest + downloadPartialExt)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestParseChunkDigest(t *testing.T) {
+	d, err := parseChunkDigest(http.Header{})
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+
+	d, err = parseChunkDigest(http.Header{"Digest": {"md5=abc, SHA-256=" + base64.StdEncoding.EncodeToString(make([]byte, 32))}})
+	assert.NoError(t, err)
+	assert.Equal(t, "sha-256", d.name)
+
+	d, err = parseChunkDigest(http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(make([]byte, 16))}})
+	assert.NoError(t, err)
+	assert.Equal(t, "md5", d.name)
+
+	_, err = parseChunkDigest(http.Header{"Digest": {"sha-256=short"}})
+	assert.Error(t, err)
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, last, total, err := parseContentRange("bytes 10-19/100")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 19, 100}, []int64{start, last, total})
+
+	_, _, _, err = parseContentRange("bytes 10-5/100")
+	assert.Error(t, err)
+	_, _, _, err = parseContentRange("bytes */100")
+	assert.Error(t, err)
+}
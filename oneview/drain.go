@@ -0,0 +1,178 @@
+package oneview
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	labelsResourcesURI = "/rest/labels/resources"
+	// CordonLabel - label put on the profile of a machine that is being
+	// drained, so other tooling leaves it alone until it is started again
+	CordonLabel = "cordoned"
+)
+
+// ErrDrainTimeout - the drain script did not finish in time
+var ErrDrainTimeout = errors.New("Drain script did not finish within --oneview-drain-timeout, the machine was left running")
+
+// resourceLabels - the labels assigned to one resource
+type resourceLabels struct {
+	ResourceURI string          `json:"resourceUri"`
+	Labels      []resourceLabel `json:"labels"`
+}
+
+// resourceLabel - a label, only the name is needed to assign one
+type resourceLabel struct {
+	URI  string `json:"uri,omitempty"`
+	Name string `json:"name"`
+}
+
+// GetLabels - names of the labels assigned to a resource
+func GetLabels(c *ov.OVClient, resourceURI string) ([]string, error) {
+	rl, err := getResourceLabels(c, resourceURI)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, l := range rl.Labels {
+		names = append(names, l.Name)
+	}
+	return names, nil
+}
+
+// hasLabel - true when name is one of labels
+func hasLabel(labels []string, name string) bool {
+	for _, l := range labels {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddLabel - assign a label to a resource, the label is created when the
+// appliance does not have it yet
+func AddLabel(c *ov.OVClient, resourceURI, name string) error {
+	rl, err := getResourceLabels(c, resourceURI)
+	if err != nil {
+		return err
+	}
+	for _, l := range rl.Labels {
+		if l.Name == name {
+			return nil
+		}
+	}
+	rl.Labels = append(rl.Labels, resourceLabel{Name: name})
+	return putResourceLabels(c, rl)
+}
+
+// RemoveLabel - take a label off a resource, missing labels are ignored
+func RemoveLabel(c *ov.OVClient, resourceURI, name string) error {
+	rl, err := getResourceLabels(c, resourceURI)
+	if err != nil {
+		return err
+	}
+	labels := rl.Labels[:0]
+	for _, l := range rl.Labels {
+		if l.Name != name {
+			labels = append(labels, l)
+		}
+	}
+	if len(labels) == len(rl.Labels) {
+		return nil
+	}
+	rl.Labels = labels
+	return putResourceLabels(c, rl)
+}
+
+// getResourceLabels - the labels of a resource, resources that never had a
+// label have no entry yet
+func getResourceLabels(c *ov.OVClient, resourceURI string) (resourceLabels, error) {
+	rl := resourceLabels{ResourceURI: resourceURI}
+	data, err := ovCall(c, rest.GET, labelsResourcesURI+resourceURI, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return rl, nil
+		}
+		return rl, err
+	}
+	if err := json.Unmarshal(data, &rl); err != nil {
+		return rl, err
+	}
+	rl.ResourceURI = resourceURI
+	return rl, nil
+}
+
+// putResourceLabels - replace the labels of a resource
+func putResourceLabels(c *ov.OVClient, rl resourceLabels) error {
+	_, err := ovCall(c, rest.PUT, labelsResourcesURI+rl.ResourceURI, rl)
+	return err
+}
+
+// drainCommand - a remote command that runs the script as root, the script
+// travels base64 encoded so it needs no quoting or upload step
+func drainCommand(script []byte) string {
+	return fmt.Sprintf("echo %s | base64 -d | sudo sh -s", base64.StdEncoding.EncodeToString(script))
+}
+
+// cordon - label the profile and run the drain script before the machine is
+// stopped, the label stays when the drain fails so the machine is not
+// picked up again half drained
+func (d *Driver) cordon() error {
+	if d.DrainScript == "" {
+		return nil
+	}
+	script, err := ioutil.ReadFile(d.DrainScript)
+	if err != nil {
+		return fmt.Errorf("unable to read drain script : %w", err)
+	}
+	log.Infof("Cordoning %s", d.MachineName)
+	if err := AddLabel(d.ClientOV, d.Profile.URI.String(), CordonLabel); err != nil {
+		return err
+	}
+
+	log.Infof("Draining %s with %s", d.MachineName, d.DrainScript)
+	done := make(chan error, 1)
+	var out string
+	go func() {
+		var err error
+		out, err = drivers.RunSSHCommandFromDriver(d, drainCommand(script))
+		done <- err
+	}()
+	var timeout <-chan time.Time
+	if d.DrainTimeout > 0 {
+		timeout = time.After(d.DrainTimeout)
+	}
+	select {
+	case err := <-done:
+		if s := strings.TrimSpace(out); s != "" {
+			log.Debugf("drain output : %s", s)
+		}
+		if err != nil {
+			return fmt.Errorf("drain script failed, the machine was left running : %w", err)
+		}
+	case <-timeout:
+		return ErrDrainTimeout
+	case <-interruptCtx.Done():
+		return ErrCancelled
+	}
+	return nil
+}
+
+// uncordon - take the cordon label off once the machine is started again
+func (d *Driver) uncordon() error {
+	if d.DrainScript == "" {
+		return nil
+	}
+	return RemoveLabel(d.ClientOV, d.Profile.URI.String(), CordonLabel)
+}
@@ -0,0 +1,49 @@
+package oneview
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainCommand(t *testing.T) {
+	script := []byte("#!/bin/sh\ndocker node update --availability drain \"$(hostname)\"\n")
+	cmd := drainCommand(script)
+	assert.True(t, strings.HasSuffix(cmd, " | base64 -d | sudo sh -s"))
+	encoded := strings.TrimSuffix(strings.TrimPrefix(cmd, "echo "), " | base64 -d | sudo sh -s")
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, script, decoded)
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, isNotFound(applianceError(errors.New("Error with request: Response Status: 404 Not Found"))))
+	assert.True(t, isNotFound(fmt.Errorf("labels : %w", &ApplianceError{StatusCode: 404, Err: errors.New("gone")})))
+	assert.False(t, isNotFound(applianceError(errors.New("Error with request: Response Status: 500 Internal Server Error"))))
+	// only the status counts, not a 404 or not found in the message
+	assert.False(t, isNotFound(errors.New("server 404 not found in icsp")))
+	assert.False(t, isNotFound(nil))
+}
+
+func TestDeleteKeyPairAgain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keys")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	d := &Driver{BaseDriver: &drivers.BaseDriver{MachineName: "docker1", StorePath: dir}}
+	assert.NoError(t, os.MkdirAll(filepath.Dir(d.GetSSHKeyPath()), 0700))
+	assert.NoError(t, ioutil.WriteFile(d.GetSSHKeyPath(), []byte("key"), 0600))
+
+	// a remove that failed after the keys went can be run again
+	assert.NoError(t, d.deleteKeyPair())
+	assert.NoError(t, d.deleteKeyPair())
+	_, err = os.Stat(d.GetSSHKeyPath())
+	assert.True(t, os.IsNotExist(err))
+}
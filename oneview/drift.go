@@ -0,0 +1,169 @@
+package oneview
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// kinds of drift
+const (
+	DriftMissing = "missing"
+	DriftChanged = "changed"
+)
+
+// Drift - one way the appliance differs from an environment
+type Drift struct {
+	// Resource - ie; networks/prod
+	Resource string `json:"resource"`
+	Kind     string `json:"kind"`
+	// Attribute - dotted path of the attribute, ie; bandwidth.maximumBandwidth
+	// or connections[0].networkUri, empty for a missing resource
+	Attribute string      `json:"attribute,omitempty"`
+	Want      interface{} `json:"want,omitempty"`
+	Have      interface{} `json:"have,omitempty"`
+}
+
+// String - ie; ~ networks/prod vlanId: 20 (appliance 21)
+func (d Drift) String() string {
+	if d.Kind == DriftMissing {
+		return fmt.Sprintf("- %s is not on the appliance", d.Resource)
+	}
+	return fmt.Sprintf("~ %s %s: %s (appliance %s)", d.Resource, d.Attribute, diffValue(d.Want), diffValue(d.Have))
+}
+
+// DriftReport - how the appliance differs from an environment
+type DriftReport struct {
+	// Checked - resources of the environment looked up
+	Checked int     `json:"checked"`
+	Drifts  []Drift `json:"drifts"`
+}
+
+// Empty - true when the appliance matches the environment
+func (r DriftReport) Empty() bool {
+	return len(r.Drifts) == 0
+}
+
+// String - one line for each drift
+func (r DriftReport) String() string {
+	var b bytes.Buffer
+	if r.Empty() {
+		fmt.Fprintf(&b, "%d resources checked, no drift\n", r.Checked)
+		return b.String()
+	}
+	for _, d := range r.Drifts {
+		fmt.Fprintf(&b, "%s\n", d)
+	}
+	fmt.Fprintf(&b, "%d resources checked, %d drifted\n", r.Checked, len(r.Drifts))
+	return b.String()
+}
+
+// Diff - compare an environment with the appliance without changing
+// anything.  Resources missing by name are reported, and for the ones that
+// exist only the attributes the environment sets are compared, the
+// appliance fills in plenty more.  References to missing resources are
+// shown as written, ie; @networks/prod.
+func (e *Environment) Diff(c *ov.OVClient) (DriftReport, error) {
+	var r DriftReport
+	ordered, err := e.plan()
+	if err != nil {
+		return r, err
+	}
+	uris := map[string]string{}
+	for _, res := range ordered {
+		r.Checked++
+		uri, err := findURIByName(c, environmentCollections[res.Kind], res.Name)
+		if err != nil {
+			return r, err
+		}
+		if uri == "" {
+			uris[res.key()] = environmentRefStart + res.key()
+			r.Drifts = append(r.Drifts, Drift{Resource: res.key(), Kind: DriftMissing})
+			continue
+		}
+		uris[res.key()] = uri
+		if res.External {
+			continue
+		}
+		live, err := getResourceMap(c, uri)
+		if err != nil {
+			return r, fmt.Errorf("unable to read %s : %w", res.key(), err)
+		}
+		want, _ := resolveEnvironmentRefs(map[string]interface{}(res.Body), uris).(map[string]interface{})
+		r.Drifts = append(r.Drifts, resourceDrift(res.key(), want, live)...)
+	}
+	return r, nil
+}
+
+// resourceDrift - attributes of want the live resource does not match
+func resourceDrift(key string, want, live map[string]interface{}) []Drift {
+	return attributeDrift(nil, key, "", want, live)
+}
+
+func attributeDrift(drifts []Drift, key, path string, want, have interface{}) []Drift {
+	changed := func() []Drift {
+		return append(drifts, Drift{Resource: key, Kind: DriftChanged, Attribute: path, Want: want, Have: have})
+	}
+	switch w := want.(type) {
+	case nil:
+		return drifts
+	case map[string]interface{}:
+		h, ok := have.(map[string]interface{})
+		if !ok {
+			return changed()
+		}
+		keys := make([]string, 0, len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			drifts = attributeDrift(drifts, key, p, w[k], h[k])
+		}
+		return drifts
+	case []interface{}:
+		// list items are compared in order, the appliance keeps the order
+		// they were created in
+		h, ok := have.([]interface{})
+		if !ok || len(h) != len(w) {
+			return changed()
+		}
+		for i := range w {
+			drifts = attributeDrift(drifts, key, fmt.Sprintf("%s[%d]", path, i), w[i], h[i])
+		}
+		return drifts
+	}
+	if _, ok := jsonFloat(want); ok {
+		if _, ok := jsonFloat(have); ok && compareNumbers(want, have) == 0 {
+			return drifts
+		}
+		return changed()
+	}
+	if !reflect.DeepEqual(want, have) {
+		return changed()
+	}
+	return drifts
+}
+
+// DiffEnvironment - compare the environment in a file with the appliance
+// from the config, for scheduled compliance checks
+func DiffEnvironment(cfg ApplianceConfig, path string) (DriftReport, error) {
+	env, err := LoadEnvironment(path)
+	if err != nil {
+		return DriftReport{}, err
+	}
+	cfg.ICSPEndpoint = ""
+	c, _, err := cfg.clients()
+	if err != nil {
+		return DriftReport{}, err
+	}
+	defer closeAll(&Driver{ClientOV: c})
+	return env.Diff(c)
+}
@@ -0,0 +1,68 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceDrift(t *testing.T) {
+	want := map[string]interface{}{
+		"name":   "prod",
+		"vlanId": 20.0,
+		"bandwidth": map[string]interface{}{
+			"maximumBandwidth": 10000.0,
+		},
+		"networkUris": []interface{}{"/rest/ethernet-networks/1"},
+	}
+	live := map[string]interface{}{
+		"name":     "prod",
+		"vlanId":   json.Number("20"),
+		"uri":      "/rest/ethernet-networks/2",
+		"category": "ethernet-networks",
+		"bandwidth": map[string]interface{}{
+			"maximumBandwidth": json.Number("10000"),
+			"typicalBandwidth": json.Number("2500"),
+		},
+		"networkUris": []interface{}{"/rest/ethernet-networks/1"},
+	}
+	assert.Empty(t, resourceDrift("networks/prod", want, live))
+
+	live["vlanId"] = json.Number("21")
+	live["networkUris"] = []interface{}{"/rest/ethernet-networks/1", "/rest/ethernet-networks/3"}
+	delete(live, "bandwidth")
+	drifts := resourceDrift("networks/prod", want, live)
+	assert.Equal(t, []Drift{
+		{Resource: "networks/prod", Kind: DriftChanged, Attribute: "bandwidth", Want: want["bandwidth"]},
+		{Resource: "networks/prod", Kind: DriftChanged, Attribute: "networkUris", Want: want["networkUris"], Have: live["networkUris"]},
+		{Resource: "networks/prod", Kind: DriftChanged, Attribute: "vlanId", Want: 20.0, Have: json.Number("21")},
+	}, drifts)
+	assert.Equal(t, "~ networks/prod vlanId: 20 (appliance 21)", drifts[2].String())
+}
+
+func TestResourceDriftListItems(t *testing.T) {
+	want := map[string]interface{}{
+		"connections": []interface{}{
+			map[string]interface{}{"id": 1.0, "networkUri": "@networks/prod"},
+		},
+	}
+	live := map[string]interface{}{
+		"connections": []interface{}{
+			map[string]interface{}{"id": json.Number("1"), "networkUri": "/rest/ethernet-networks/1", "mac": "aa"},
+		},
+	}
+	drifts := resourceDrift("server-profile-templates/web", want, live)
+	assert.Len(t, drifts, 1)
+	assert.Equal(t, "connections[0].networkUri", drifts[0].Attribute)
+}
+
+func TestDriftReport(t *testing.T) {
+	r := DriftReport{Checked: 2}
+	assert.True(t, r.Empty())
+	assert.Equal(t, "2 resources checked, no drift\n", r.String())
+
+	r.Drifts = []Drift{{Resource: "networks/prod", Kind: DriftMissing}}
+	assert.False(t, r.Empty())
+	assert.Equal(t, "- networks/prod is not on the appliance\n2 resources checked, 1 drifted\n", r.String())
+}
@@ -0,0 +1,194 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// ErrEnclosureGroupNotFound - no enclosure group has the name
+var ErrEnclosureGroupNotFound = errors.New("Enclosure group not found")
+
+// enclosureGroupTypes - the enclosure group type string of each api
+// version, as profileTypes
+var enclosureGroupTypes = map[int]string{
+	200: "EnclosureGroupV200",
+	300: "EnclosureGroupV300",
+	600: "EnclosureGroupV400",
+}
+
+// enclosureBays - interconnect bays of a c7000 enclosure
+const enclosureBays = 8
+
+// InterconnectBayMapping - the logical interconnect group an interconnect
+// bay of the enclosures is set up from
+type InterconnectBayMapping struct {
+	InterconnectBay             int    `json:"interconnectBay"`
+	LogicalInterconnectGroupURI string `json:"logicalInterconnectGroupUri,omitempty"`
+}
+
+// EnclosureGroup - enclosures set up alike, profiles for blades name the
+// group of their enclosure
+type EnclosureGroup struct {
+	Type                                string                   `json:"type,omitempty"`
+	URI                                 string                   `json:"uri,omitempty"`
+	ETag                                string                   `json:"eTag,omitempty"`
+	Name                                string                   `json:"name,omitempty"`
+	Description                         string                   `json:"description,omitempty"`
+	StackingMode                        string                   `json:"stackingMode,omitempty"`
+	EnclosureTypeURI                    string                   `json:"enclosureTypeUri,omitempty"`
+	EnclosureCount                      int                      `json:"enclosureCount,omitempty"`
+	PowerMode                           string                   `json:"powerMode,omitempty"`
+	IPAddressingMode                    string                   `json:"ipAddressingMode,omitempty"`
+	InterconnectBayMappingCount         int                      `json:"interconnectBayMappingCount,omitempty"`
+	InterconnectBayMappings             []InterconnectBayMapping `json:"interconnectBayMappings"`
+	AssociatedLogicalInterconnectGroups []string                 `json:"associatedLogicalInterconnectGroups,omitempty"`
+	Status                              ResourceStatus           `json:"status,omitempty"`
+	State                               string                   `json:"state,omitempty"`
+}
+
+// LogicalInterconnectGroupURIs - the logical interconnect groups the bays
+// are mapped to, each once and sorted
+func (g EnclosureGroup) LogicalInterconnectGroupURIs() []string {
+	seen := map[string]bool{}
+	var uris []string
+	for _, m := range g.InterconnectBayMappings {
+		if m.LogicalInterconnectGroupURI != "" && !seen[m.LogicalInterconnectGroupURI] {
+			seen[m.LogicalInterconnectGroupURI] = true
+			uris = append(uris, m.LogicalInterconnectGroupURI)
+		}
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+// Validate - check the group has a name and each bay is mapped once
+func (g EnclosureGroup) Validate() error {
+	var problems []string
+	if g.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	bays := map[int]bool{}
+	for _, m := range g.InterconnectBayMappings {
+		switch {
+		case m.InterconnectBay < 1 || m.InterconnectBay > enclosureBays:
+			problems = append(problems, fmt.Sprintf("interconnect bay %d is not between 1 and %d", m.InterconnectBay, enclosureBays))
+		case bays[m.InterconnectBay]:
+			problems = append(problems, fmt.Sprintf("interconnect bay %d is mapped more than once", m.InterconnectBay))
+		}
+		bays[m.InterconnectBay] = true
+	}
+	switch {
+	case len(problems) == 0:
+		return nil
+	case g.Name == "":
+		return fmt.Errorf("invalid enclosure group: %s", strings.Join(problems, "; "))
+	}
+	return fmt.Errorf("invalid enclosure group %s: %s", g.Name, strings.Join(problems, "; "))
+}
+
+// GetEnclosureGroups - enclosure groups matching the filter, empty for all
+func GetEnclosureGroups(c *ov.OVClient, filter string) ([]EnclosureGroup, error) {
+	var opts ListOptions
+	if filter = strings.TrimSpace(filter); filter != "" {
+		opts.Filters = []string{filter}
+	}
+	var list []EnclosureGroup
+	err := listOrdered(c, enclosureGroupsURI, opts, func(members json.RawMessage) error {
+		var page []EnclosureGroup
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// GetEnclosureGroup - the enclosure group at uri
+func GetEnclosureGroup(c *ov.OVClient, uri string) (EnclosureGroup, error) {
+	var g EnclosureGroup
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return g, err
+	}
+	err = json.Unmarshal(data, &g)
+	return g, err
+}
+
+// GetEnclosureGroupByName - the named enclosure group,
+// ErrEnclosureGroupNotFound when there is none
+func GetEnclosureGroupByName(c *ov.OVClient, name string) (EnclosureGroup, error) {
+	list, err := GetEnclosureGroups(c, applianceFilter("name", name))
+	if err != nil {
+		return EnclosureGroup{}, err
+	}
+	for _, g := range list {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+	return EnclosureGroup{}, fmt.Errorf("%w: %s", ErrEnclosureGroupNotFound, name)
+}
+
+// CreateEnclosureGroup - create an enclosure group, the appliance answers
+// with the group straight away.  A group with no type gets the type of the
+// client api version.
+func CreateEnclosureGroup(c *ov.OVClient, g EnclosureGroup) (EnclosureGroup, error) {
+	if err := g.Validate(); err != nil {
+		return g, err
+	}
+	if g.Type == "" {
+		g.Type = typeForVersion(enclosureGroupTypes, c.APIVersion)
+	}
+	g.InterconnectBayMappingCount = len(g.InterconnectBayMappings)
+	var created EnclosureGroup
+	data, err := ovCall(c, rest.POST, enclosureGroupsURI, g)
+	if err != nil {
+		return created, err
+	}
+	err = json.Unmarshal(data, &created)
+	return created, err
+}
+
+// UpdateEnclosureGroup - put a changed enclosure group, sending its eTag as
+// If-Match so a change someone else made since it was read is refused
+// rather than overwritten
+func UpdateEnclosureGroup(c *ov.OVClient, g EnclosureGroup) (EnclosureGroup, error) {
+	if g.URI == "" {
+		return g, fmt.Errorf("enclosure group %s has no uri, it has to be read from the appliance before it can be updated", g.Name)
+	}
+	if err := g.Validate(); err != nil {
+		return g, err
+	}
+	g.InterconnectBayMappingCount = len(g.InterconnectBayMappings)
+	var headers map[string]string
+	if g.ETag != "" {
+		headers = map[string]string{"If-Match": g.ETag}
+	}
+	var updated EnclosureGroup
+	data, err := ovHeaderCall(c, rest.PUT, g.URI, headers, g)
+	if err != nil {
+		return updated, err
+	}
+	err = json.Unmarshal(data, &updated)
+	return updated, err
+}
+
+// DeleteEnclosureGroup - delete the named enclosure group, the appliance
+// refuses while enclosures or profiles still use it
+func DeleteEnclosureGroup(c *ov.OVClient, name string) error {
+	uri, err := findURIByName(c, enclosureGroupsURI, name)
+	if err != nil {
+		return err
+	}
+	if uri == "" {
+		return fmt.Errorf("%w: %s", ErrEnclosureGroupNotFound, name)
+	}
+	return deleteResource(c, uri)
+}
@@ -0,0 +1,28 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnclosureGroupValidate(t *testing.T) {
+	g := EnclosureGroup{Name: "eg1", InterconnectBayMappings: []InterconnectBayMapping{
+		{InterconnectBay: 1, LogicalInterconnectGroupURI: "/rest/logical-interconnect-groups/b"},
+		{InterconnectBay: 2, LogicalInterconnectGroupURI: "/rest/logical-interconnect-groups/a"},
+		{InterconnectBay: 3, LogicalInterconnectGroupURI: "/rest/logical-interconnect-groups/b"},
+		{InterconnectBay: 4},
+	}}
+	assert.NoError(t, g.Validate())
+	assert.Equal(t, []string{"/rest/logical-interconnect-groups/a", "/rest/logical-interconnect-groups/b"}, g.LogicalInterconnectGroupURIs())
+
+	g.Name = ""
+	g.InterconnectBayMappings = append(g.InterconnectBayMappings, InterconnectBayMapping{InterconnectBay: 1}, InterconnectBayMapping{InterconnectBay: 9})
+	assert.EqualError(t, g.Validate(), "invalid enclosure group: name is required; interconnect bay 1 is mapped more than once; interconnect bay 9 is not between 1 and 8")
+}
+
+func TestEnclosureGroupType(t *testing.T) {
+	assert.Equal(t, "", typeForVersion(enclosureGroupTypes, 120))
+	assert.Equal(t, "EnclosureGroupV300", typeForVersion(enclosureGroupTypes, 500))
+	assert.Equal(t, "EnclosureGroupV400", typeForVersion(enclosureGroupTypes, 800))
+}
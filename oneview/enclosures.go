@@ -0,0 +1,224 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	enclosuresURI      = "/rest/enclosures"
+	enclosureGroupsURI = "/rest/enclosure-groups"
+	firmwareDriversURI = "/rest/firmware-drivers"
+)
+
+// licensing intents for imported enclosures
+const (
+	LicensingOneView      = "OneView"
+	LicensingOneViewNoILO = "OneViewNoiLO"
+)
+
+// ErrEnclosureNotFound - no enclosure has the name
+var ErrEnclosureNotFound = errors.New("Enclosure not found")
+
+// enclosure import errors
+var (
+	ErrEnclosureImportMissingHost  = errors.New("Enclosure import needs the onboard administrator hostname")
+	ErrEnclosureImportMissingLogin = errors.New("Enclosure import needs the onboard administrator username and password")
+	ErrEnclosureImportMissingGroup = errors.New("Enclosure import needs an enclosure group, unless it is only monitored")
+)
+
+// EnclosureDeviceBay - a bay of an enclosure and the blade in it
+type EnclosureDeviceBay struct {
+	BayNumber                     int    `json:"bayNumber"`
+	DevicePresence                string `json:"devicePresence,omitempty"`
+	DeviceURI                     string `json:"deviceUri,omitempty"`
+	ProfileURI                    string `json:"profileUri,omitempty"`
+	CoveredByDevice               string `json:"coveredByDevice,omitempty"`
+	AvailableForFullHeightProfile bool   `json:"availableForFullHeightProfile,omitempty"`
+}
+
+// Enclosure - a c7000 enclosure or synergy frame and its bays
+type Enclosure struct {
+	Type              string               `json:"type,omitempty"`
+	URI               string               `json:"uri,omitempty"`
+	Name              string               `json:"name,omitempty"`
+	SerialNumber      string               `json:"serialNumber,omitempty"`
+	PartNumber        string               `json:"partNumber,omitempty"`
+	EnclosureType     string               `json:"enclosureType,omitempty"`
+	EnclosureGroupURI string               `json:"enclosureGroupUri,omitempty"`
+	State             string               `json:"state,omitempty"`
+	Status            ResourceStatus       `json:"status,omitempty"`
+	LicensingIntent   string               `json:"licensingIntent,omitempty"`
+	FwBaselineURI     string               `json:"fwBaselineUri,omitempty"`
+	IsFwManaged       bool                 `json:"isFwManaged,omitempty"`
+	DeviceBayCount    int                  `json:"deviceBayCount,omitempty"`
+	DeviceBays        []EnclosureDeviceBay `json:"deviceBays,omitempty"`
+}
+
+// FreeBays - bays with no blade in them, by number
+func (e Enclosure) FreeBays() []int {
+	var free []int
+	for _, b := range e.DeviceBays {
+		if b.DevicePresence == "Absent" && b.CoveredByDevice == "" {
+			free = append(free, b.BayNumber)
+		}
+	}
+	return free
+}
+
+// GetEnclosures - enclosures matching the filter, empty for all
+func GetEnclosures(c *ov.OVClient, filter string) ([]Enclosure, error) {
+	var opts ListOptions
+	if filter = strings.TrimSpace(filter); filter != "" {
+		opts.Filters = []string{filter}
+	}
+	var list []Enclosure
+	err := listOrdered(c, enclosuresURI, opts, func(members json.RawMessage) error {
+		var page []Enclosure
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// GetEnclosure - the enclosure at uri
+func GetEnclosure(c *ov.OVClient, uri string) (Enclosure, error) {
+	var e Enclosure
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(data, &e)
+	return e, err
+}
+
+// GetEnclosureByName - the named enclosure, ErrEnclosureNotFound when there
+// is none
+func GetEnclosureByName(c *ov.OVClient, name string) (Enclosure, error) {
+	list, err := GetEnclosures(c, applianceFilter("name", name))
+	if err != nil {
+		return Enclosure{}, err
+	}
+	for _, e := range list {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return Enclosure{}, fmt.Errorf("%w: %s", ErrEnclosureNotFound, name)
+}
+
+// EnclosureImport - how to claim a c7000 enclosure through its onboard
+// administrator (OA), synergy frames are discovered by the appliance instead
+type EnclosureImport struct {
+	// Hostname - ip or hostname of the active OA
+	Hostname string
+	// Username, Password - OA credentials, OneView creates its own account
+	// on the OA with them
+	Username string
+	Password string
+	// EnclosureGroup - name of the enclosure group to add the enclosure to
+	EnclosureGroup string
+	// LicensingIntent - OneView or OneViewNoiLO, defaults to OneView
+	LicensingIntent string
+	// Monitored - only monitor the enclosure, no profiles can be applied
+	Monitored bool
+	// FirmwareBaseline - optional name of an uploaded service pack to update
+	// the enclosure to while it is added
+	FirmwareBaseline string
+	// Force - take the enclosure over from another appliance managing it
+	Force bool
+}
+
+// Validate - check the import has what the appliance needs
+func (e EnclosureImport) Validate() error {
+	if e.Hostname == "" {
+		return ErrEnclosureImportMissingHost
+	}
+	if e.Username == "" || e.Password == "" {
+		return ErrEnclosureImportMissingLogin
+	}
+	if !e.Monitored && e.EnclosureGroup == "" {
+		return ErrEnclosureImportMissingGroup
+	}
+	return nil
+}
+
+// body - the add enclosure request, with names resolved to uris
+func (e EnclosureImport) body(groupURI, baselineURI string) map[string]interface{} {
+	body := map[string]interface{}{
+		"hostname": e.Hostname,
+		"username": e.Username,
+		"password": e.Password,
+		"force":    e.Force,
+	}
+	if e.Monitored {
+		body["state"] = "Monitored"
+		return body
+	}
+	licensing := e.LicensingIntent
+	if licensing == "" {
+		licensing = LicensingOneView
+	}
+	body["licensingIntent"] = licensing
+	body["enclosureGroupUri"] = groupURI
+	if baselineURI != "" {
+		body["firmwareBaselineUri"] = baselineURI
+		body["updateFirmwareOn"] = "EnclosureOnly"
+	}
+	return body
+}
+
+// AddEnclosure - claim an enclosure and wait for the import to finish, the
+// import takes the enclosure from factory state to having its server
+// hardware ready for profiles.  Returns the uri of the new enclosure.
+func AddEnclosure(c *ov.OVClient, e EnclosureImport) (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	var groupURI, baselineURI string
+	var err error
+	if !e.Monitored {
+		if groupURI, err = findURIByName(c, enclosureGroupsURI, e.EnclosureGroup); err != nil {
+			return "", err
+		}
+		if groupURI == "" {
+			return "", fmt.Errorf("unable to find enclosure group %s", e.EnclosureGroup)
+		}
+		if e.FirmwareBaseline != "" {
+			if baselineURI, err = findURIByName(c, firmwareDriversURI, e.FirmwareBaseline); err != nil {
+				return "", err
+			}
+			if baselineURI == "" {
+				return "", fmt.Errorf("unable to find firmware baseline %s", e.FirmwareBaseline)
+			}
+		}
+	}
+
+	log.Infof("Adding enclosure %s", e.Hostname)
+	data, err := ovCall(c, rest.POST, enclosuresURI, e.body(groupURI, baselineURI))
+	if err != nil {
+		return "", err
+	}
+	var t Task
+	if err := json.Unmarshal(data, &t); err != nil {
+		return "", err
+	}
+	if t.URI == "" {
+		return "", fmt.Errorf("appliance did not return a task to wait on")
+	}
+	t, err = waitForTaskResult(interruptCtx, c, t.URI)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Added enclosure %s as %s", e.Hostname, t.AssociatedResource.ResourceName)
+	return t.AssociatedResource.ResourceURI, nil
+}
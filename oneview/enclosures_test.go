@@ -0,0 +1,45 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnclosureImportValidate(t *testing.T) {
+	e := EnclosureImport{Hostname: "oa1", Username: "Administrator", Password: "pw", EnclosureGroup: "eg1"}
+	assert.NoError(t, e.Validate())
+	assert.Equal(t, ErrEnclosureImportMissingHost, EnclosureImport{}.Validate())
+	assert.Equal(t, ErrEnclosureImportMissingLogin, EnclosureImport{Hostname: "oa1"}.Validate())
+	assert.Equal(t, ErrEnclosureImportMissingGroup, EnclosureImport{Hostname: "oa1", Username: "a", Password: "b"}.Validate())
+	assert.NoError(t, EnclosureImport{Hostname: "oa1", Username: "a", Password: "b", Monitored: true}.Validate())
+}
+
+func TestEnclosureImportBody(t *testing.T) {
+	e := EnclosureImport{Hostname: "oa1", Username: "Administrator", Password: "pw", EnclosureGroup: "eg1"}
+	assert.Equal(t, map[string]interface{}{
+		"hostname":            "oa1",
+		"username":            "Administrator",
+		"password":            "pw",
+		"force":               false,
+		"licensingIntent":     LicensingOneView,
+		"enclosureGroupUri":   "/rest/enclosure-groups/1",
+		"firmwareBaselineUri": "/rest/firmware-drivers/spp",
+		"updateFirmwareOn":    "EnclosureOnly",
+	}, e.body("/rest/enclosure-groups/1", "/rest/firmware-drivers/spp"))
+
+	e.Monitored = true
+	body := e.body("", "")
+	assert.Equal(t, "Monitored", body["state"])
+	assert.NotContains(t, body, "enclosureGroupUri")
+}
+
+func TestEnclosureFreeBays(t *testing.T) {
+	e := Enclosure{DeviceBays: []EnclosureDeviceBay{
+		{BayNumber: 1, DevicePresence: "Present", DeviceURI: "/rest/server-hardware/1"},
+		{BayNumber: 2, DevicePresence: "Absent"},
+		{BayNumber: 3, DevicePresence: "Present"},
+		{BayNumber: 11, DevicePresence: "Absent", CoveredByDevice: "/rest/server-hardware/3"},
+	}}
+	assert.Equal(t, []int{2}, e.FreeBays())
+}
@@ -0,0 +1,318 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// environment resource kinds, also the prefix of a reference, ie; @networks/prod
+const (
+	KindNetwork         = "networks"
+	KindNetworkSet      = "network-sets"
+	KindEnclosureGroup  = "enclosure-groups"
+	KindServerTemplate  = "server-profile-templates"
+	KindStorageVolume   = "storage-volumes"
+	environmentRefStart = "@"
+	// EnvironmentLabel - label Apply puts on the resources it created, only
+	// those are deleted by Destroy
+	EnvironmentLabel = "docker-machine-environment"
+)
+
+// environmentCollections - where each kind of resource lives on the appliance
+var environmentCollections = map[string]string{
+	KindNetwork:        "/rest/ethernet-networks",
+	KindNetworkSet:     "/rest/network-sets",
+	KindEnclosureGroup: enclosureGroupsURI,
+	KindServerTemplate: serverProfileTemplatesURI,
+	KindStorageVolume:  storageVolumesURI,
+}
+
+// Environment - the appliance resources a set of docker hosts needs, applied
+// and destroyed as one.  Resources are raw appliance bodies, and any string
+// value of the form @kind/name is replaced with the uri of that resource, ie;
+// a connection with "networkUri": "@networks/prod".  References decide the
+// order resources are created in.
+type Environment struct {
+	Networks    []map[string]interface{} `json:"networks,omitempty"`
+	NetworkSets []map[string]interface{} `json:"networkSets,omitempty"`
+	// EnclosureGroups - names of existing enclosure groups that may be
+	// referenced, they are never created or deleted
+	EnclosureGroups []string                 `json:"enclosureGroups,omitempty"`
+	Volumes         []map[string]interface{} `json:"volumes,omitempty"`
+	ServerTemplates []map[string]interface{} `json:"serverTemplates,omitempty"`
+}
+
+// envResource - one resource of an environment
+type envResource struct {
+	Kind string
+	Name string
+	Body map[string]interface{}
+	// External - already on the appliance, only looked up
+	External bool
+}
+
+// key - ie; networks/prod
+func (r envResource) key() string {
+	return r.Kind + "/" + r.Name
+}
+
+// LoadEnvironment - read an environment from a json file
+func LoadEnvironment(path string) (*Environment, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	env := &Environment{}
+	if err := json.Unmarshal(data, env); err != nil {
+		return nil, fmt.Errorf("unable to read environment %s : %w", path, err)
+	}
+	return env, nil
+}
+
+// resources - every resource in declaration order
+func (e *Environment) resources() ([]envResource, error) {
+	var list []envResource
+	seen := map[string]bool{}
+	add := func(r envResource) error {
+		if r.Name == "" {
+			return fmt.Errorf("environment has %s without a name", r.Kind)
+		}
+		if seen[r.key()] {
+			return fmt.Errorf("environment declares %s more than once", r.key())
+		}
+		seen[r.key()] = true
+		list = append(list, r)
+		return nil
+	}
+	for _, name := range e.EnclosureGroups {
+		if err := add(envResource{Kind: KindEnclosureGroup, Name: name, External: true}); err != nil {
+			return nil, err
+		}
+	}
+	sections := []struct {
+		kind   string
+		bodies []map[string]interface{}
+	}{
+		{KindNetwork, e.Networks},
+		{KindNetworkSet, e.NetworkSets},
+		{KindStorageVolume, e.Volumes},
+		{KindServerTemplate, e.ServerTemplates},
+	}
+	for _, s := range sections {
+		for _, body := range s.bodies {
+			name, _ := body["name"].(string)
+			if err := add(envResource{Kind: s.kind, Name: name, Body: body}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return list, nil
+}
+
+// parseEnvironmentRef - the resource key of a reference value
+func parseEnvironmentRef(v string) (string, bool) {
+	if !strings.HasPrefix(v, environmentRefStart) {
+		return "", false
+	}
+	key := strings.TrimPrefix(v, environmentRefStart)
+	i := strings.Index(key, "/")
+	if i < 0 {
+		return "", false
+	}
+	if _, ok := environmentCollections[key[:i]]; !ok {
+		return "", false
+	}
+	return key, true
+}
+
+// environmentRefs - keys of every resource referenced from v
+func environmentRefs(v interface{}) []string {
+	var refs []string
+	switch t := v.(type) {
+	case string:
+		if key, ok := parseEnvironmentRef(t); ok {
+			refs = append(refs, key)
+		}
+	case map[string]interface{}:
+		for _, item := range t {
+			refs = append(refs, environmentRefs(item)...)
+		}
+	case []interface{}:
+		for _, item := range t {
+			refs = append(refs, environmentRefs(item)...)
+		}
+	}
+	return refs
+}
+
+// resolveEnvironmentRefs - copy of v with references replaced by uris
+func resolveEnvironmentRefs(v interface{}, uris map[string]string) interface{} {
+	switch t := v.(type) {
+	case string:
+		if key, ok := parseEnvironmentRef(t); ok {
+			return uris[key]
+		}
+		return t
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			out[k] = resolveEnvironmentRefs(item, uris)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			out[i] = resolveEnvironmentRefs(item, uris)
+		}
+		return out
+	}
+	return v
+}
+
+// plan - resources ordered so each comes after everything it references,
+// keeping declaration order where references allow
+func (e *Environment) plan() ([]envResource, error) {
+	list, err := e.resources()
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, r := range list {
+		index[r.key()] = i
+	}
+	deps := make([][]int, len(list))
+	for i, r := range list {
+		refs := environmentRefs(map[string]interface{}(r.Body))
+		sort.Strings(refs)
+		for _, ref := range refs {
+			j, ok := index[ref]
+			if !ok {
+				return nil, fmt.Errorf("%s references %s which is not in the environment", r.key(), ref)
+			}
+			if j == i {
+				return nil, fmt.Errorf("%s references itself", r.key())
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(list))
+	var ordered []envResource
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		path = append(path, list[i].key())
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("environment has a reference cycle: %s", strings.Join(path, " -> "))
+		}
+		state[i] = visiting
+		for _, j := range deps[i] {
+			if err := visit(j, path); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		ordered = append(ordered, list[i])
+		return nil
+	}
+	for i := range list {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Apply - create every resource missing from the appliance, in dependency
+// order, and label them with EnvironmentLabel.  Resources that already exist
+// by name are left alone, so applying an environment twice is safe.
+func (e *Environment) Apply(c *ov.OVClient) error {
+	ordered, err := e.plan()
+	if err != nil {
+		return err
+	}
+	uris := map[string]string{}
+	for _, r := range ordered {
+		collection := environmentCollections[r.Kind]
+		uri, err := findURIByName(c, collection, r.Name)
+		if err != nil {
+			return err
+		}
+		if uri != "" {
+			log.Infof("Environment %s exists at %s", r.key(), uri)
+			uris[r.key()] = uri
+			continue
+		}
+		if r.External {
+			return fmt.Errorf("environment needs %s which is not on the appliance", r.key())
+		}
+		log.Infof("Creating environment %s", r.key())
+		body := resolveEnvironmentRefs(map[string]interface{}(r.Body), uris)
+		data, err := ovCall(c, rest.POST, collection, body)
+		if err != nil {
+			return fmt.Errorf("unable to create %s : %w", r.key(), err)
+		}
+		if err := waitForTaskResponse(c, data); err != nil {
+			return fmt.Errorf("unable to create %s : %w", r.key(), err)
+		}
+		if uri, err = findURIByName(c, collection, r.Name); err != nil {
+			return err
+		}
+		uris[r.key()] = uri
+		if err := AddLabel(c, uri, EnvironmentLabel); err != nil {
+			return fmt.Errorf("unable to label %s as created by the environment, delete it by hand before destroying the environment : %w", r.key(), err)
+		}
+	}
+	return nil
+}
+
+// Destroy - delete the resources Apply created, dependents first.  External
+// resources, resources already gone and resources without EnvironmentLabel,
+// that were on the appliance before Apply, are skipped.
+func (e *Environment) Destroy(c *ov.OVClient) error {
+	ordered, err := e.plan()
+	if err != nil {
+		return err
+	}
+	for i := len(ordered) - 1; i >= 0; i-- {
+		r := ordered[i]
+		if r.External {
+			continue
+		}
+		uri, err := findURIByName(c, environmentCollections[r.Kind], r.Name)
+		if err != nil {
+			return err
+		}
+		if uri == "" {
+			log.Debugf("environment %s is already gone", r.key())
+			continue
+		}
+		labels, err := GetLabels(c, uri)
+		if err != nil {
+			return err
+		}
+		if !hasLabel(labels, EnvironmentLabel) {
+			log.Infof("Leaving environment %s, it was not created by the environment", r.key())
+			continue
+		}
+		log.Infof("Deleting environment %s", r.key())
+		if err := deleteResource(c, uri); err != nil {
+			return fmt.Errorf("unable to delete %s : %w", r.key(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testEnvironment(t *testing.T, data string) *Environment {
+	env := &Environment{}
+	assert.NoError(t, json.Unmarshal([]byte(data), env))
+	return env
+}
+
+func TestEnvironmentPlan(t *testing.T) {
+	env := testEnvironment(t, `{
+		"enclosureGroups": ["eg1"],
+		"serverTemplates": [{
+			"name": "docker",
+			"enclosureGroupUri": "@enclosure-groups/eg1",
+			"connections": [{"id": 1, "networkUri": "@network-sets/docker"}]
+		}],
+		"networkSets": [{"name": "docker", "networkUris": ["@networks/prod", "@networks/mgmt"]}],
+		"networks": [{"name": "prod", "vlanId": 10}, {"name": "mgmt", "vlanId": 20}]
+	}`)
+	ordered, err := env.plan()
+	assert.NoError(t, err)
+	var keys []string
+	for _, r := range ordered {
+		keys = append(keys, r.key())
+	}
+	assert.Equal(t, []string{
+		"enclosure-groups/eg1",
+		"networks/prod",
+		"networks/mgmt",
+		"network-sets/docker",
+		"server-profile-templates/docker",
+	}, keys)
+}
+
+func TestEnvironmentPlanErrors(t *testing.T) {
+	_, err := testEnvironment(t, `{"networkSets": [{"name": "s", "networkUris": ["@networks/missing"]}]}`).plan()
+	assert.Error(t, err)
+
+	_, err = testEnvironment(t, `{"networks": [{"name": "a"}, {"name": "a"}]}`).plan()
+	assert.Error(t, err)
+
+	_, err = testEnvironment(t, `{"networkSets": [
+		{"name": "a", "nativeNetworkUri": "@network-sets/b"},
+		{"name": "b", "nativeNetworkUri": "@network-sets/a"}]}`).plan()
+	assert.Contains(t, err.Error(), "cycle")
+}
+
+func TestResolveEnvironmentRefs(t *testing.T) {
+	body := map[string]interface{}{
+		"name":        "s",
+		"description": "@not a reference",
+		"networkUris": []interface{}{"@networks/prod"},
+	}
+	resolved := resolveEnvironmentRefs(body, map[string]string{"networks/prod": "/rest/ethernet-networks/1"}).(map[string]interface{})
+	assert.Equal(t, []interface{}{"/rest/ethernet-networks/1"}, resolved["networkUris"])
+	assert.Equal(t, "@not a reference", resolved["description"])
+	assert.Equal(t, []interface{}{"@networks/prod"}, body["networkUris"])
+}
+
+func TestEnvironmentDestroysOnlyWhatApplyCreated(t *testing.T) {
+	env := testEnvironment(t, `{"networks": [{"name": "prod", "vlanId": 10}, {"name": "mgmt", "vlanId": 20}]}`)
+	find := func(name, uri string) fakeCall {
+		data := `{"members":[]}`
+		if uri != "" {
+			data = `{"members":[{"uri":"` + uri + `","name":"` + name + `"}]}`
+		}
+		return fakeCall{method: "GET", uri: "/rest/ethernet-networks",
+			query: map[string]interface{}{"filter": []string{"name='" + name + "'"}}, data: data}
+	}
+
+	// prod was already on the appliance, mgmt is created and labelled
+	c, done := fakeOV(t,
+		find("prod", "/rest/ethernet-networks/1"),
+		find("mgmt", ""),
+		fakeCall{method: "POST", uri: "/rest/ethernet-networks", data: `{"uri":"/rest/tasks/1","type":"TaskResourceV2"}`},
+		fakeCall{method: "GET", uri: "/rest/tasks/1", data: `{"name":"Create","taskState":"Completed","percentComplete":100}`},
+		find("mgmt", "/rest/ethernet-networks/2"),
+		fakeCall{method: "GET", uri: "/rest/labels/resources/rest/ethernet-networks/2", data: `{"labels":[]}`},
+		fakeCall{method: "PUT", uri: "/rest/labels/resources/rest/ethernet-networks/2", body: func(body interface{}) {
+			assert.Equal(t, []resourceLabel{{Name: EnvironmentLabel}}, body.(resourceLabels).Labels)
+		}},
+	)
+	assert.NoError(t, env.Apply(c))
+	done()
+
+	c, done = fakeOV(t,
+		find("mgmt", "/rest/ethernet-networks/2"),
+		fakeCall{method: "GET", uri: "/rest/labels/resources/rest/ethernet-networks/2",
+			data: `{"labels":[{"name":"` + EnvironmentLabel + `"}]}`},
+		fakeCall{method: "DELETE", uri: "/rest/ethernet-networks/2"},
+		find("prod", "/rest/ethernet-networks/1"),
+		fakeCall{method: "GET", uri: "/rest/labels/resources/rest/ethernet-networks/1", data: `{"labels":[]}`},
+	)
+	assert.NoError(t, env.Destroy(c))
+	done()
+}
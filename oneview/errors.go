@@ -0,0 +1,242 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrorCategory - what kind of failure an error is, so pipelines can tell a
+// bad flag from an appliance that is out of blades
+type ErrorCategory string
+
+// error categories
+const (
+	CategoryUser              ErrorCategory = "user-error"
+	CategoryTransient         ErrorCategory = "transient"
+	CategoryResourceExhausted ErrorCategory = "resource-exhausted"
+	CategoryApplianceFault    ErrorCategory = "appliance-fault"
+)
+
+// exit codes for each category, following sysexits.h
+var categoryExitCodes = map[ErrorCategory]int{
+	CategoryUser:              64, // EX_USAGE
+	CategoryResourceExhausted: 69, // EX_UNAVAILABLE
+	CategoryApplianceFault:    70, // EX_SOFTWARE
+	CategoryTransient:         75, // EX_TEMPFAIL
+}
+
+// DriverError - an error with its category, the message is prefixed with
+// the category as docker-machine only passes the message on from the plugin
+type DriverError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+func (e *DriverError) Error() string {
+	return fmt.Sprintf("[oneview %s] %s", e.Category, e.Err)
+}
+
+// Unwrap - the categorized error, for errors.Is and errors.As
+func (e *DriverError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode - exit code a wrapper should use for the error
+func (e *DriverError) ExitCode() int {
+	return categoryExitCodes[e.Category]
+}
+
+// ExitCode - exit code for a failed command, from the category of err
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return classifyError(err).(*DriverError).ExitCode()
+}
+
+// Retryable - true when trying again later may succeed
+func (e *DriverError) Retryable() bool {
+	return e.Category == CategoryTransient
+}
+
+// userErrors - errors caused by how the driver was called
+var userErrors = []error{
+	ErrDriverMissingEndPointOptionOV,
+	ErrDriverMissingEndPointOptionICSP,
+	ErrDriverMissingTemplateOption,
+	ErrDriverMissingBuildPlanOption,
+	ErrDriverInvalidPortAllocation,
+	ErrDriverInvalidHideFlexNics,
+	ErrDriverInvalidBootVlan,
+	ErrDriverInvalidProvisioning,
+	ErrDriverMissingHTTPBootURL,
+	ErrDriverMissingILOKnownHosts,
+	ErrDriverInvalidForceOption,
+	ErrPlanOnly,
+	ErrCancelled,
+	ErrPolicyRefused,
+	ErrInvalidProfile,
+	ErrICSPAPIVersion,
+	ErrNotMachineProfile,
+	ErrServerHardwareTypeMismatch,
+}
+
+// resourceErrors - errors caused by the appliance running out of something
+var resourceErrors = []error{
+	ErrNoEligibleHardware,
+	ErrIdentityPoolExhausted,
+	ErrHardwareMonitoredOnly,
+}
+
+// transientErrors - errors that may clear up by themselves
+var transientErrors = []error{
+	ErrHardwareReserved,
+}
+
+// message fragments the appliance uses when it runs out of something, it
+// answers those with a plain 400
+var resourceMessages = []string{"no available", "license", "pool is exhausted", "no more", "insufficient"}
+
+// message fragments of the driver's own errors, only looked at for errors
+// that did not come from an appliance response
+var (
+	userMessages      = []string{"invalid option", "missing option", "unable to find server template", "must be", "usage:"}
+	transientMessages = []string{"timeout", "timed out", "connection refused", "connection reset", "busy", "try again"}
+)
+
+// statusRE - the response status the rest library ends its errors with
+var statusRE = regexp.MustCompile(`Response Status: (\d{3})`)
+
+// ApplianceError - an appliance call answered with an error status
+type ApplianceError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *ApplianceError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap - the error of the call
+func (e *ApplianceError) Unwrap() error {
+	return e.Err
+}
+
+// applianceError - err as an *ApplianceError when it ends with a response
+// status.  The rest library only reports the status in its messages, so
+// this runs where its calls return and everything after uses the type.
+func applianceError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var ae *ApplianceError
+	if errors.As(err, &ae) {
+		return err
+	}
+	m := statusRE.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	code, _ := strconv.Atoi(m[1])
+	return &ApplianceError{StatusCode: code, Err: err}
+}
+
+// statusCodeOf - the http status of a failed appliance call, 0 when err
+// did not come from an appliance response
+func statusCodeOf(err error) int {
+	var ae *ApplianceError
+	if errors.As(err, &ae) {
+		return ae.StatusCode
+	}
+	return 0
+}
+
+// categoryOfStatus - the category of an appliance response status
+func categoryOfStatus(code int) ErrorCategory {
+	switch {
+	case code == 429 || code == 502 || code == 503 || code == 504:
+		return CategoryTransient
+	case code >= 400 && code < 500:
+		return CategoryUser
+	}
+	return CategoryApplianceFault
+}
+
+// CategoryOf - the category of an error, uncategorized errors from the
+// appliance are treated as appliance faults
+func CategoryOf(err error) ErrorCategory {
+	var de *DriverError
+	if errors.As(err, &de) {
+		return de.Category
+	}
+	var be *BusyError
+	if errors.As(err, &be) {
+		return CategoryTransient
+	}
+	var je *ICSPJobError
+	if errors.As(err, &je) {
+		return je.Category
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && (ne.Timeout() || ne.Temporary()) {
+		return CategoryTransient
+	}
+	for _, u := range userErrors {
+		if errors.Is(err, u) {
+			return CategoryUser
+		}
+	}
+	for _, r := range resourceErrors {
+		if errors.Is(err, r) {
+			return CategoryResourceExhausted
+		}
+	}
+	for _, t := range transientErrors {
+		if errors.Is(err, t) {
+			return CategoryTransient
+		}
+	}
+	msg := strings.ToLower(err.Error())
+	if code := statusCodeOf(err); code > 0 {
+		if code < 500 && containsAny(msg, resourceMessages) {
+			return CategoryResourceExhausted
+		}
+		return categoryOfStatus(code)
+	}
+	switch {
+	case containsAny(msg, userMessages):
+		return CategoryUser
+	case containsAny(msg, resourceMessages):
+		return CategoryResourceExhausted
+	case containsAny(msg, transientMessages):
+		return CategoryTransient
+	}
+	return CategoryApplianceFault
+}
+
+// classifyError - wrap an error with its category, nil stays nil
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var de *DriverError
+	if errors.As(err, &de) {
+		return err
+	}
+	// errors of the library calls made without libraryCall, ie; the login,
+	// have not been through the error body policy yet
+	return &DriverError{Category: CategoryOf(err), Err: redactError(err)}
+}
+
+func containsAny(s string, fragments []string) bool {
+	for _, f := range fragments {
+		if strings.Contains(s, f) {
+			return true
+		}
+	}
+	return false
+}
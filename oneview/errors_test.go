@@ -0,0 +1,65 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryOf(t *testing.T) {
+	assert.Equal(t, CategoryUser, CategoryOf(ErrDriverMissingTemplateOption))
+	assert.Equal(t, CategoryResourceExhausted, CategoryOf(ErrNoEligibleHardware))
+	assert.Equal(t, CategoryTransient, CategoryOf(&BusyError{}))
+	assert.Equal(t, CategoryTransient, CategoryOf(errors.New("dial tcp 10.0.0.1:443: connection refused")))
+	assert.Equal(t, CategoryResourceExhausted, CategoryOf(errors.New("No available licenses for OneView Advanced")))
+	assert.Equal(t, CategoryApplianceFault, CategoryOf(errors.New("task ended in state Error")))
+}
+
+func TestCategoryOfStatus(t *testing.T) {
+	// the status decides, not words in the appliance's message
+	assert.Equal(t, CategoryTransient, CategoryOf(applianceError(errors.New("Error in response: appliance is starting\n Response Status: 503 Service Unavailable"))))
+	assert.Equal(t, CategoryUser, CategoryOf(applianceError(errors.New("Error in response: name must be unique, timeout 30\n Response Status: 409 Conflict"))))
+	assert.Equal(t, CategoryApplianceFault, CategoryOf(applianceError(errors.New("Error in response: serial 5032 must be set\n Response Status: 500 Internal Server Error"))))
+	assert.Equal(t, CategoryResourceExhausted, CategoryOf(applianceError(errors.New("Error in response: insufficient licenses\n Response Status: 400 Bad Request"))))
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 64, ExitCode(ErrDriverMissingTemplateOption))
+	assert.Equal(t, 64, ExitCode(errors.New("usage: docker-machine-driver-oneview destroy <machine>")))
+	assert.Equal(t, 69, ExitCode(ErrNoEligibleHardware))
+	assert.Equal(t, 70, ExitCode(errors.New("task ended in state Error")))
+	assert.Equal(t, 75, ExitCode(&BusyError{}))
+}
+
+func TestClassifyError(t *testing.T) {
+	assert.Nil(t, classifyError(nil))
+
+	err := classifyError(ErrNoEligibleHardware)
+	de, ok := err.(*DriverError)
+	assert.True(t, ok)
+	assert.Equal(t, 69, de.ExitCode())
+	assert.False(t, de.Retryable())
+	assert.Contains(t, err.Error(), "[oneview resource-exhausted]")
+
+	// classifying twice keeps the first category
+	assert.Equal(t, err, classifyError(err))
+}
+
+func TestWrappedErrors(t *testing.T) {
+	wrapped := fmt.Errorf("create failed: %w", ErrNoEligibleHardware)
+	assert.Equal(t, CategoryResourceExhausted, CategoryOf(wrapped))
+
+	err := classifyError(wrapped)
+	assert.True(t, errors.Is(err, ErrNoEligibleHardware))
+
+	failed := classifyError(fmt.Errorf("apply profile: %w", Task{Name: "Create", TaskState: "Error"}.err()))
+	var tf *TaskFailedError
+	assert.True(t, errors.As(failed, &tf))
+	assert.Equal(t, "Create", tf.Task.Name)
+
+	busy := fmt.Errorf("download: %w", &BusyError{})
+	assert.Equal(t, CategoryTransient, CategoryOf(busy))
+}
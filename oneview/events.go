@@ -0,0 +1,162 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// event types
+const (
+	EventStageStarted   = "stage-started"
+	EventStageCompleted = "stage-completed"
+	EventStageFailed    = "stage-failed"
+	EventProgress       = "progress"
+)
+
+// Event - one line of the machine readable event stream
+type Event struct {
+	Time     time.Time     `json:"time"`
+	Event    string        `json:"event"`
+	Machine  string        `json:"machine,omitempty"`
+	Stage    string        `json:"stage,omitempty"`
+	ID       string        `json:"id,omitempty"`
+	Action   string        `json:"action,omitempty"`
+	Current  int64         `json:"current,omitempty"`
+	Total    int64         `json:"total,omitempty"`
+	Elapsed  time.Duration `json:"elapsedMs,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Category ErrorCategory `json:"category,omitempty"`
+}
+
+// MarshalJSON - elapsed in milliseconds
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	e.Elapsed /= time.Millisecond
+	return json.Marshal(event(e))
+}
+
+// eventSink - json lines written to a file or descriptor, next to the log
+type eventSink struct {
+	mu     sync.Mutex
+	target string
+	w      io.WriteCloser
+	now    func() time.Time
+}
+
+var (
+	eventsMu sync.Mutex
+	events   *eventSink
+)
+
+// OpenEvents - write events as json lines to target, a file appended to.
+// An empty target stops the events.
+func OpenEvents(target string) error {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	if events != nil && events.target == target {
+		return nil
+	}
+	if events != nil {
+		events.w.Close()
+		events = nil
+	}
+	if target == "" {
+		return nil
+	}
+	w, err := openEventTarget(target)
+	if err != nil {
+		return fmt.Errorf("unable to open event output %s: %w", target, err)
+	}
+	events = &eventSink{target: target, w: w, now: time.Now}
+	return nil
+}
+
+// eventsOpen - true while events are written
+func eventsOpen() bool {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	return events != nil
+}
+
+// openEventTarget - the file named by target.  Descriptors are refused,
+// docker-machine passes none to the plugin so fd:N would be one of the
+// plugin's own.
+func openEventTarget(target string) (io.WriteCloser, error) {
+	if strings.HasPrefix(target, "fd:") {
+		return nil, fmt.Errorf("invalid option --oneview-events %s, must be a file, descriptors are not passed to the driver plugin", target)
+	}
+	return os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+}
+
+// write - one event as a line, failures are only logged so events never
+// break the driver
+func (s *eventSink) write(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.Time.IsZero() {
+		e.Time = s.now().UTC()
+	}
+	data, err := json.Marshal(e)
+	if err == nil {
+		_, err = s.w.Write(append(data, '\n'))
+	}
+	if err != nil {
+		log.Debugf("unable to write event to %s : %s", s.target, err)
+	}
+}
+
+// emit - send an event when events are on
+func emit(e Event) {
+	eventsMu.Lock()
+	s := events
+	eventsMu.Unlock()
+	if s != nil {
+		s.write(e)
+	}
+}
+
+// runStage - run fn as a named stage of the machine, with started and
+// completed or failed events around it
+func runStage(machine, stage string, fn func() error) error {
+	start := time.Now()
+	emit(Event{Event: EventStageStarted, Machine: machine, Stage: stage})
+	// the sink rather than defaultProgress, the stage events are sent already
+	sink := currentProgressSink()
+	sink.WriteProgress(progress.Progress{ID: machine, Action: stage})
+	err := fn()
+	e := Event{Event: EventStageCompleted, Machine: machine, Stage: stage, Elapsed: time.Since(start)}
+	done := stage + " done"
+	if err != nil {
+		e.Event, e.Error, e.Category = EventStageFailed, err.Error(), CategoryOf(err)
+		done = stage + " failed"
+	}
+	emit(e)
+	sink.WriteProgress(progress.Progress{ID: machine, Action: done, LastUpdate: true})
+	return err
+}
+
+// eventProgress - ProgressSink sending each update as an event before
+// handing it on
+type eventProgress struct {
+	next ProgressSink
+}
+
+// WriteProgress - emit the update and pass it on
+func (p *eventProgress) WriteProgress(u progress.Progress) error {
+	if u.Message != "" || u.Action != "" {
+		action := u.Action
+		if action == "" {
+			action = u.Message
+		}
+		emit(Event{Event: EventProgress, ID: u.ID, Action: action, Current: u.Current, Total: u.Total})
+	}
+	return p.next.WriteProgress(u)
+}
@@ -0,0 +1,55 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.json")
+	assert.NoError(t, OpenEvents(path))
+	defer OpenEvents("")
+
+	assert.NoError(t, runStage("docker1", "allocate", func() error {
+		defaultProgress().WriteProgress(progress.Progress{ID: "Create profile docker1", Action: "Running 40%"})
+		return nil
+	}))
+	assert.Error(t, runStage("docker1", "deploy", func() error { return errors.New("profile apply failed") }))
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	var got []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &e))
+		delete(e, "time")
+		delete(e, "elapsedMs")
+		got = append(got, e)
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"event": "stage-started", "machine": "docker1", "stage": "allocate"},
+		{"event": "progress", "id": "Create profile docker1", "action": "Running 40%"},
+		{"event": "stage-completed", "machine": "docker1", "stage": "allocate"},
+		{"event": "stage-started", "machine": "docker1", "stage": "deploy"},
+		{"event": "stage-failed", "machine": "docker1", "stage": "deploy", "error": "profile apply failed", "category": "appliance-fault"},
+	}, got)
+}
+
+func TestOpenEventsDescriptor(t *testing.T) {
+	assert.Error(t, OpenEvents("fd:x"))
+	err := OpenEvents("fd:3")
+	assert.Error(t, err)
+	assert.Equal(t, CategoryUser, CategoryOf(err))
+	assert.False(t, eventsOpen())
+}
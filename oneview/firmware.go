@@ -0,0 +1,74 @@
+package oneview
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// firmwareOfflineInstall - install type that needs no agent in the os, the
+// appliance boots the server into the service pack with the server off
+const firmwareOfflineInstall = "FirmwareOnlyOfflineMode"
+
+// firmwareChange - profile change managing firmware with the baseline,
+// installed offline as there is no os yet.  An empty baseline keeps the one
+// the profile has from its server template.
+func firmwareChange(baselineURI string) profileChange {
+	return func(profile map[string]interface{}) (bool, error) {
+		fw, _ := profile["firmware"].(map[string]interface{})
+		if fw == nil {
+			fw = map[string]interface{}{}
+		}
+		if baselineURI == "" {
+			baselineURI, _ = fw["firmwareBaselineUri"].(string)
+		}
+		if baselineURI == "" {
+			return false, fmt.Errorf("no firmware baseline to update to, set --oneview-firmware-baseline or one in the server template")
+		}
+		want := map[string]interface{}{
+			"manageFirmware":       true,
+			"firmwareBaselineUri":  baselineURI,
+			"firmwareInstallType":  firmwareOfflineInstall,
+			"forceInstallFirmware": false,
+		}
+		changed := false
+		for k, v := range want {
+			if fw[k] != v {
+				fw[k] = v
+				changed = true
+			}
+		}
+		profile["firmware"] = fw
+		return changed, nil
+	}
+}
+
+// firmwareBaselineURI - the uri of the named firmware baseline, empty for
+// the server template baseline
+func (d *Driver) firmwareBaselineURI() (string, error) {
+	if d.FirmwareBaseline == "" {
+		return "", nil
+	}
+	uri, err := findURIByName(d.ClientOV, firmwareDriversURI, d.FirmwareBaseline)
+	if err != nil {
+		return "", err
+	}
+	if uri == "" {
+		return "", fmt.Errorf("unable to find firmware baseline %s", d.FirmwareBaseline)
+	}
+	return uri, nil
+}
+
+// updateFirmware - bring the blade to the firmware baseline before the os
+// install, the profile update only completes once the firmware is applied
+func (d *Driver) updateFirmware() error {
+	if !d.UpdateFirmware {
+		return nil
+	}
+	baselineURI, err := d.firmwareBaselineURI()
+	if err != nil {
+		return err
+	}
+	log.Infof("Updating the firmware of %s, this can take an hour...", d.MachineName)
+	return d.updateProfile(firmwareChange(baselineURI))
+}
@@ -0,0 +1,51 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirmwareChange(t *testing.T) {
+	profile := map[string]interface{}{"firmware": map[string]interface{}{
+		"manageFirmware":      false,
+		"firmwareBaselineUri": "/rest/firmware-drivers/SPP_2016",
+	}}
+	changed, err := firmwareChange("")(profile)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]interface{}{
+		"manageFirmware":       true,
+		"firmwareBaselineUri":  "/rest/firmware-drivers/SPP_2016",
+		"firmwareInstallType":  "FirmwareOnlyOfflineMode",
+		"forceInstallFirmware": false,
+	}, profile["firmware"])
+
+	changed, err = firmwareChange("/rest/firmware-drivers/SPP_2016")(profile)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, err = firmwareChange("/rest/firmware-drivers/SPP_2017")(profile)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	_, err = firmwareChange("")(map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestProfileBaselineURI(t *testing.T) {
+	assert.Equal(t, "", profileBaselineURI(map[string]interface{}{}))
+	assert.Equal(t, "", profileBaselineURI(map[string]interface{}{"firmware": map[string]interface{}{
+		"manageFirmware": false, "firmwareBaselineUri": "/rest/firmware-drivers/SPP_2016",
+	}}))
+	assert.Equal(t, "/rest/firmware-drivers/SPP_2016", profileBaselineURI(map[string]interface{}{"firmware": map[string]interface{}{
+		"manageFirmware": true, "firmwareBaselineUri": "/rest/firmware-drivers/SPP_2016",
+	}}))
+
+	f := ServerFirmware{Components: []FirmwareComponent{{Name: "System ROM", Version: "I36 v2.52"}}}
+	c, ok := f.Component("System ROM")
+	assert.True(t, ok)
+	assert.Equal(t, "I36 v2.52", c.Version)
+	_, ok = f.Component("iLO")
+	assert.False(t, ok)
+}
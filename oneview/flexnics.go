@@ -0,0 +1,128 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// port allocation strategies for profile connections
+const (
+	PortAllocationAuto     = "auto"
+	PortAllocationExplicit = "explicit"
+
+	autoPortID = "Auto"
+)
+
+// Error messages
+var (
+	ErrDriverInvalidPortAllocation = errors.New("Invalid option --oneview-port-allocation, must be one of auto or explicit")
+	ErrDriverInvalidHideFlexNics   = errors.New("Invalid option --oneview-hide-unused-flexnics, must be true, false or empty")
+)
+
+// NetworkSettings - profile settings that change how the OS enumerates nics
+type NetworkSettings struct {
+	// HideUnusedFlexNics - "true" or "false", empty keeps the template setting
+	HideUnusedFlexNics string
+	// PortAllocation - auto lets OneView pick ports, explicit uses physical
+	// ports, empty keeps the template ports
+	PortAllocation string
+	// Ports - physical port ids assigned in connection id order when explicit
+	Ports []string
+}
+
+// newNetworkSettings - build the settings from flag values
+func newNetworkSettings(hide, allocation, ports string) (NetworkSettings, error) {
+	ns := NetworkSettings{
+		HideUnusedFlexNics: strings.ToLower(strings.TrimSpace(hide)),
+		PortAllocation:     strings.ToLower(strings.TrimSpace(allocation)),
+	}
+	if ns.HideUnusedFlexNics != "" {
+		if _, err := strconv.ParseBool(ns.HideUnusedFlexNics); err != nil {
+			return ns, ErrDriverInvalidHideFlexNics
+		}
+	}
+	switch ns.PortAllocation {
+	case "", PortAllocationAuto, PortAllocationExplicit:
+	default:
+		return ns, ErrDriverInvalidPortAllocation
+	}
+	for _, p := range strings.Split(ports, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			ns.Ports = append(ns.Ports, p)
+		}
+	}
+	return ns, nil
+}
+
+// isDefault - true when the settings leave the profile as the template made it
+func (ns NetworkSettings) isDefault() bool {
+	return ns.HideUnusedFlexNics == "" && ns.PortAllocation == "" && len(ns.Ports) == 0
+}
+
+// apply - change a raw profile to match the settings, returns true when
+// something was changed
+func (ns NetworkSettings) apply(profile map[string]interface{}) (bool, error) {
+	changed := false
+	if ns.HideUnusedFlexNics != "" {
+		hide, _ := strconv.ParseBool(ns.HideUnusedFlexNics)
+		if current, ok := profile["hideUnusedFlexNics"].(bool); !ok || current != hide {
+			profile["hideUnusedFlexNics"] = hide
+			changed = true
+		}
+	}
+
+	conns := profileConnections(profile)
+	switch ns.PortAllocation {
+	case PortAllocationAuto:
+		for _, conn := range conns {
+			if conn["portId"] != autoPortID {
+				conn["portId"] = autoPortID
+				changed = true
+			}
+		}
+	case PortAllocationExplicit:
+		if len(ns.Ports) > 0 && len(ns.Ports) != len(conns) {
+			return changed, fmt.Errorf("--oneview-connection-ports has %d ports but the profile has %d connections", len(ns.Ports), len(conns))
+		}
+		for i, conn := range conns {
+			if len(ns.Ports) > 0 {
+				if conn["portId"] != ns.Ports[i] {
+					conn["portId"] = ns.Ports[i]
+					changed = true
+				}
+				continue
+			}
+			if port, _ := conn["portId"].(string); port == "" || port == autoPortID {
+				return changed, fmt.Errorf("connection %v has no physical port, set --oneview-connection-ports or fix the server template", conn["id"])
+			}
+		}
+	}
+	return changed, nil
+}
+
+// profileConnections - connections of a raw profile ordered by connection id
+func profileConnections(profile map[string]interface{}) []map[string]interface{} {
+	raw, _ := profile["connections"].([]interface{})
+	var conns []map[string]interface{}
+	for _, c := range raw {
+		if conn, ok := c.(map[string]interface{}); ok {
+			conns = append(conns, conn)
+		}
+	}
+	sort.Sort(connectionsByID(conns))
+	return conns
+}
+
+// connectionsByID - sort raw connections by id
+type connectionsByID []map[string]interface{}
+
+func (c connectionsByID) Len() int      { return len(c) }
+func (c connectionsByID) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c connectionsByID) Less(i, j int) bool {
+	a, _ := jsonInt(c[i]["id"])
+	b, _ := jsonInt(c[j]["id"])
+	return a < b
+}
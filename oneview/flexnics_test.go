@@ -0,0 +1,67 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProfileConnections = `{
+	"hideUnusedFlexNics": true,
+	"connections": [
+		{"id": 2, "portId": "Flb 1:2-a"},
+		{"id": 1, "portId": "Auto"}
+	]
+}`
+
+func getTestProfileMap(t *testing.T) map[string]interface{} {
+	var p map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(testProfileConnections), &p))
+	return p
+}
+
+func TestNewNetworkSettings(t *testing.T) {
+	ns, err := newNetworkSettings("", "", "")
+	assert.NoError(t, err)
+	assert.True(t, ns.isDefault())
+
+	ns, err = newNetworkSettings("False", "EXPLICIT", "Flb 1:1-a, Flb 1:2-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "false", ns.HideUnusedFlexNics)
+	assert.Equal(t, PortAllocationExplicit, ns.PortAllocation)
+	assert.Equal(t, []string{"Flb 1:1-a", "Flb 1:2-a"}, ns.Ports)
+
+	_, err = newNetworkSettings("maybe", "", "")
+	assert.Equal(t, ErrDriverInvalidHideFlexNics, err)
+	_, err = newNetworkSettings("", "random", "")
+	assert.Equal(t, ErrDriverInvalidPortAllocation, err)
+}
+
+func TestNetworkSettingsApply(t *testing.T) {
+	p := getTestProfileMap(t)
+	changed, err := NetworkSettings{HideUnusedFlexNics: "false", PortAllocation: PortAllocationAuto}.apply(p)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, false, p["hideUnusedFlexNics"])
+	for _, conn := range profileConnections(p) {
+		assert.Equal(t, autoPortID, conn["portId"])
+	}
+
+	p = getTestProfileMap(t)
+	changed, err = NetworkSettings{PortAllocation: PortAllocationExplicit, Ports: []string{"Flb 1:1-a", "Flb 1:2-a"}}.apply(p)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	conns := profileConnections(p)
+	assert.Equal(t, "Flb 1:1-a", conns[0]["portId"])
+	assert.Equal(t, "Flb 1:2-a", conns[1]["portId"])
+
+	// explicit without ports needs every connection on a physical port
+	p = getTestProfileMap(t)
+	_, err = NetworkSettings{PortAllocation: PortAllocationExplicit}.apply(p)
+	assert.Error(t, err)
+
+	p = getTestProfileMap(t)
+	_, err = NetworkSettings{PortAllocation: PortAllocationExplicit, Ports: []string{"Flb 1:1-a"}}.apply(p)
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ForceOption - a kind of profile warning the appliance is told to go ahead
+// despite, rather than refusing the create or update
+type ForceOption string
+
+// force options of server profile creates and updates
+const (
+	ForceIgnoreSANWarnings  ForceOption = "ignoreSANWarnings"
+	ForceIgnoreServerHealth ForceOption = "ignoreServerHealth"
+	ForceIgnoreLSWarnings   ForceOption = "ignoreLSWarnings"
+	ForceAll                ForceOption = "all"
+)
+
+// ErrDriverInvalidForceOption - a --oneview-profile-force value the
+// appliance does not know
+var ErrDriverInvalidForceOption = errors.New("Invalid option --oneview-profile-force, use ignoreSANWarnings, ignoreServerHealth, ignoreLSWarnings or all")
+
+var forceOptions = []ForceOption{ForceIgnoreSANWarnings, ForceIgnoreServerHealth, ForceIgnoreLSWarnings, ForceAll}
+
+// ParseForceOptions - force options from their names, case does not matter
+func ParseForceOptions(names []string) ([]ForceOption, error) {
+	var opts []ForceOption
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, o := range forceOptions {
+			if strings.EqualFold(name, string(o)) {
+				opts = append(opts, o)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrDriverInvalidForceOption, name)
+		}
+	}
+	return opts, nil
+}
+
+// withForce - uri with the force query parameter when there are options
+func withForce(uri string, force []ForceOption) string {
+	if len(force) == 0 {
+		return uri
+	}
+	names := make([]string, len(force))
+	for i, o := range force {
+		names[i] = string(o)
+	}
+	sep := "?"
+	if strings.Contains(uri, "?") {
+		sep = "&"
+	}
+	return uri + sep + "force=" + strings.Join(names, ",")
+}
@@ -0,0 +1,23 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseForceOptions(t *testing.T) {
+	opts, err := ParseForceOptions([]string{"ignoresanwarnings", " ignoreServerHealth", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []ForceOption{ForceIgnoreSANWarnings, ForceIgnoreServerHealth}, opts)
+	_, err = ParseForceOptions([]string{"ignoreEverything"})
+	assert.True(t, errors.Is(err, ErrDriverInvalidForceOption))
+}
+
+func TestWithForce(t *testing.T) {
+	assert.Equal(t, "/rest/server-profiles", withForce("/rest/server-profiles", nil))
+	assert.Equal(t, "/rest/server-profiles?force=ignoreSANWarnings,all",
+		withForce("/rest/server-profiles", []ForceOption{ForceIgnoreSANWarnings, ForceAll}))
+	assert.Equal(t, "/rest/server-profiles/1?x=1&force=all", withForce("/rest/server-profiles/1?x=1", []ForceOption{ForceAll}))
+}
@@ -0,0 +1,191 @@
+package oneview
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Generation - server hardware generation, it decides the defaults for boot
+// mode, firmware handling and connection port naming
+type Generation string
+
+// known hardware generations
+const (
+	GenerationUnknown Generation = ""
+	Gen8              Generation = "Gen8"
+	Gen9              Generation = "Gen9"
+	GenSynergy        Generation = "Synergy"
+)
+
+// boot modes for a profile
+const (
+	BootModeBIOS          = "BIOS"
+	BootModeUEFI          = "UEFI"
+	BootModeUEFIOptimized = "UEFIOptimized"
+)
+
+var genModel = regexp.MustCompile(`(?i)\bgen\s*(\d+)\b`)
+
+// ParseGeneration - parse a generation given as a flag, ie; gen9 or synergy
+func ParseGeneration(s string) (Generation, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return GenerationUnknown, nil
+	case "gen8":
+		return Gen8, nil
+	case "gen9":
+		return Gen9, nil
+	case "synergy":
+		return GenSynergy, nil
+	}
+	return GenerationUnknown, fmt.Errorf("Invalid option --oneview-hardware-generation %q, must be one of gen8, gen9 or synergy", s)
+}
+
+// DetectGeneration - work out the generation from a hardware or hardware type
+// model, ie; ProLiant BL460c Gen9 or Synergy 480 Gen9
+func DetectGeneration(model string) Generation {
+	if strings.Contains(strings.ToLower(model), "synergy") {
+		return GenSynergy
+	}
+	if m := genModel.FindStringSubmatch(model); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch {
+		case n == 8:
+			return Gen8
+		case n >= 9:
+			// anything newer behaves like the latest generation we know
+			return Gen9
+		}
+	}
+	return GenerationUnknown
+}
+
+// GenerationDefaults - profile defaults for a hardware generation
+type GenerationDefaults struct {
+	// BootMode - boot mode set when the template does not manage it
+	BootMode string
+	// UEFI - the generation supports uefi boot modes
+	UEFI bool
+	// FirmwareInstallType - how firmware baselines get installed
+	FirmwareInstallType string
+	// PortPrefix - the adapter part of a connection port id, ie; Flb 1
+	PortPrefix string
+}
+
+// DefaultsFor - defaults for the generation
+func DefaultsFor(g Generation) GenerationDefaults {
+	switch g {
+	case Gen8:
+		// no uefi, and firmware can only be applied offline
+		return GenerationDefaults{BootMode: BootModeBIOS, FirmwareInstallType: "FirmwareOnlyOfflineMode", PortPrefix: "Flb 1"}
+	case GenSynergy:
+		return GenerationDefaults{BootMode: BootModeUEFIOptimized, UEFI: true, FirmwareInstallType: "FirmwareOnly", PortPrefix: "Mezz 3"}
+	case Gen9:
+		return GenerationDefaults{BootMode: BootModeBIOS, UEFI: true, FirmwareInstallType: "FirmwareOnly", PortPrefix: "Flb 1"}
+	}
+	return GenerationDefaults{PortPrefix: "Flb 1"}
+}
+
+// GenerationSettings - generation driven profile settings and their overrides
+type GenerationSettings struct {
+	// Generation - forced generation, detected from the hardware when unknown
+	Generation Generation
+	// BootMode - forced boot mode, empty uses the template or generation default
+	BootMode string
+}
+
+// normalizeBootMode - match the appliance spelling of a boot mode
+func normalizeBootMode(s string) (string, error) {
+	for _, m := range []string{BootModeBIOS, BootModeUEFI, BootModeUEFIOptimized} {
+		if strings.EqualFold(strings.TrimSpace(s), m) {
+			return m, nil
+		}
+	}
+	if strings.TrimSpace(s) == "" {
+		return "", nil
+	}
+	return "", fmt.Errorf("Invalid option --oneview-boot-mode %q, must be one of BIOS, UEFI or UEFIOptimized", s)
+}
+
+// qualifyPort - add the generation adapter to a bare port id, so 1-a
+// becomes Flb 1:1-a on blades and Mezz 3:1-a on synergy
+func (gd GenerationDefaults) qualifyPort(port string) string {
+	if port == autoPortID || strings.Contains(port, " ") || gd.PortPrefix == "" {
+		return port
+	}
+	return gd.PortPrefix + ":" + port
+}
+
+// apply - change a raw profile for hardware of the given model
+func (gs GenerationSettings) apply(model string, profile map[string]interface{}) (bool, error) {
+	gen := gs.Generation
+	if gen == GenerationUnknown {
+		gen = DetectGeneration(model)
+	}
+	gd := DefaultsFor(gen)
+	changed := false
+
+	// boot mode, forced by flag or defaulted when the template leaves it alone
+	bootMode, _ := profile["bootMode"].(map[string]interface{})
+	managed, _ := bootMode["manageMode"].(bool)
+	mode := gs.BootMode
+	if mode == "" && !managed {
+		mode = gd.BootMode
+	}
+	if mode != "" {
+		if mode != BootModeBIOS && !gd.UEFI && gen != GenerationUnknown {
+			return changed, fmt.Errorf("%s hardware does not support boot mode %s", gen, mode)
+		}
+		if current, _ := bootMode["mode"].(string); !managed || current != mode {
+			if bootMode == nil {
+				bootMode = map[string]interface{}{}
+			}
+			bootMode["manageMode"] = true
+			bootMode["mode"] = mode
+			if mode != BootModeBIOS {
+				if _, ok := bootMode["pxeBootPolicy"]; !ok {
+					bootMode["pxeBootPolicy"] = "Auto"
+				}
+			} else {
+				delete(bootMode, "pxeBootPolicy")
+			}
+			profile["bootMode"] = bootMode
+			changed = true
+		}
+	}
+
+	// firmware install type, only when the profile manages firmware
+	if fw, ok := profile["firmware"].(map[string]interface{}); ok && gd.FirmwareInstallType != "" {
+		if manage, _ := fw["manageFirmware"].(bool); manage {
+			if t, _ := fw["firmwareInstallType"].(string); t == "" {
+				fw["firmwareInstallType"] = gd.FirmwareInstallType
+				changed = true
+			}
+		}
+	}
+
+	// bare port ids get the adapter name for the generation
+	for _, conn := range profileConnections(profile) {
+		port, _ := conn["portId"].(string)
+		if q := gd.qualifyPort(port); port != "" && q != port {
+			conn["portId"] = q
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// generationChange - profile change applying the generation settings for the
+// machine hardware
+func (d *Driver) generationChange() profileChange {
+	return func(profile map[string]interface{}) (bool, error) {
+		hw, err := getResourceMap(d.ClientOV, d.Profile.ServerHardwareURI.String())
+		if err != nil {
+			return false, err
+		}
+		model, _ := hw["model"].(string)
+		return d.GenerationSettings.apply(model, profile)
+	}
+}
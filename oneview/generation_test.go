@@ -0,0 +1,56 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectGeneration(t *testing.T) {
+	assert.Equal(t, Gen8, DetectGeneration("ProLiant BL460c Gen8"))
+	assert.Equal(t, Gen9, DetectGeneration("ProLiant BL460c Gen9"))
+	assert.Equal(t, Gen9, DetectGeneration("ProLiant DL380 Gen10"))
+	assert.Equal(t, GenSynergy, DetectGeneration("Synergy 480 Gen9"))
+	assert.Equal(t, GenerationUnknown, DetectGeneration("ProLiant BL460c G7"))
+}
+
+func TestParseGeneration(t *testing.T) {
+	g, err := ParseGeneration("Gen9")
+	assert.NoError(t, err)
+	assert.Equal(t, Gen9, g)
+	_, err = ParseGeneration("gen7")
+	assert.Error(t, err)
+}
+
+func TestGenerationSettingsApply(t *testing.T) {
+	profile := map[string]interface{}{
+		"firmware": map[string]interface{}{"manageFirmware": true},
+		"connections": []interface{}{
+			map[string]interface{}{"id": float64(1), "portId": "1-a"},
+			map[string]interface{}{"id": float64(2), "portId": "Auto"},
+		},
+	}
+	changed, err := GenerationSettings{}.apply("Synergy 480 Gen9", profile)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, BootModeUEFIOptimized, profile["bootMode"].(map[string]interface{})["mode"])
+	assert.Equal(t, "FirmwareOnly", profile["firmware"].(map[string]interface{})["firmwareInstallType"])
+	conns := profileConnections(profile)
+	assert.Equal(t, "Mezz 3:1-a", conns[0]["portId"])
+	assert.Equal(t, "Auto", conns[1]["portId"])
+
+	// nothing left to change the second time around
+	changed, err = GenerationSettings{}.apply("Synergy 480 Gen9", profile)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// template managed boot mode is kept
+	profile = map[string]interface{}{"bootMode": map[string]interface{}{"manageMode": true, "mode": BootModeUEFI}}
+	changed, err = GenerationSettings{}.apply("ProLiant BL460c Gen9", profile)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// gen8 can not do uefi
+	_, err = GenerationSettings{BootMode: BootModeUEFI}.apply("ProLiant BL460c Gen8", map[string]interface{}{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+const serverHardwareURI = "/rest/server-hardware"
+
+// hardware states reported by the appliance
+const (
+	HardwareStateNoProfileApplied = "NoProfileApplied"
+	HardwareStateProfileApplied   = "ProfileApplied"
+	// HardwareStateMonitored - added for monitoring only, without a license
+	// to manage it profiles can not be applied
+	HardwareStateMonitored = "Monitored"
+)
+
+// ErrHardwareMonitoredOnly - the hardware is monitored, not managed
+var ErrHardwareMonitoredOnly = errors.New("Server hardware is only monitored by OneView, profiles can not be applied to it")
+
+// ServerHardwareInventory - server hardware with the capacity details used
+// when choosing where to place a machine
+type ServerHardwareInventory struct {
+	URI                   string         `json:"uri,omitempty"`
+	Name                  string         `json:"name,omitempty"`
+	Model                 string         `json:"model,omitempty"`
+	SerialNumber          string         `json:"serialNumber,omitempty"`
+	State                 string         `json:"state,omitempty"`
+	PowerState            string         `json:"powerState,omitempty"`
+	Status                ResourceStatus `json:"status,omitempty"`
+	ServerHardwareTypeURI string         `json:"serverHardwareTypeUri,omitempty"`
+	ServerGroupURI        string         `json:"serverGroupUri,omitempty"`
+	ServerProfileURI      string         `json:"serverProfileUri,omitempty"`
+	MemoryMb              int            `json:"memoryMb,omitempty"`
+	ProcessorCount        int            `json:"processorCount,omitempty"`
+	ProcessorCoreCount    int            `json:"processorCoreCount,omitempty"`
+	ProcessorSpeedMhz     int            `json:"processorSpeedMhz,omitempty"`
+	ProcessorType         string         `json:"processorType,omitempty"`
+	LicensingIntent       string         `json:"licensingIntent,omitempty"`
+	// LocationURI - the enclosure of a blade, empty for rack servers
+	LocationURI string `json:"locationUri,omitempty"`
+}
+
+// IsMonitoredOnly - true for hardware OneView monitors but does not manage
+func (h ServerHardwareInventory) IsMonitoredOnly() bool {
+	return h.State == HardwareStateMonitored
+}
+
+// MonitoredHardwareError - the hardware that matched the machine but is
+// only monitored, with how to bring it under management
+type MonitoredHardwareError struct {
+	Hardware []ServerHardwareInventory
+}
+
+func (e *MonitoredHardwareError) Error() string {
+	var names []string
+	rack := false
+	for _, h := range e.Hardware {
+		names = append(names, h.Name)
+		rack = rack || h.isRackServer()
+	}
+	path := "add the enclosure again with an enclosure group and the OneView license"
+	if rack {
+		path += ", add rack servers again as managed with the OneView license"
+	}
+	return fmt.Sprintf("%s: %s. To use them %s", ErrHardwareMonitoredOnly, strings.Join(names, ", "), path)
+}
+
+// Unwrap - ErrHardwareMonitoredOnly
+func (e *MonitoredHardwareError) Unwrap() error {
+	return ErrHardwareMonitoredOnly
+}
+
+// isRackServer - rack servers are added on their own, blades with their enclosure
+func (h ServerHardwareInventory) isRackServer() bool {
+	return h.LocationURI == ""
+}
+
+// MemoryGb - installed memory in whole gigabytes
+func (h ServerHardwareInventory) MemoryGb() int {
+	return h.MemoryMb / 1024
+}
+
+// TotalCores - cores across all processors, processorCoreCount is per processor
+func (h ServerHardwareInventory) TotalCores() int {
+	if h.ProcessorCount <= 0 {
+		return h.ProcessorCoreCount
+	}
+	return h.ProcessorCount * h.ProcessorCoreCount
+}
+
+// String - short description for logs
+func (h ServerHardwareInventory) String() string {
+	return fmt.Sprintf("%s (%s, %dGB, %d cores @ %dMHz)", h.Name, h.Model, h.MemoryGb(), h.TotalCores(), h.ProcessorSpeedMhz)
+}
+
+// listHardwareInventory - list server hardware matching all of the filters,
+// only the hardware in the scope unless scopeURI is empty
+func listHardwareInventory(c *ov.OVClient, filters []string, scopeURI string) ([]ServerHardwareInventory, error) {
+	var list []ServerHardwareInventory
+	err := listOrdered(c, serverHardwareURI, ListOptions{Filters: filters, Scope: scopeURI}, func(members json.RawMessage) error {
+		var page []ServerHardwareInventory
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
@@ -0,0 +1,62 @@
+package oneview
+
+import (
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// server hardware power states
+const (
+	PowerStateOn  = "On"
+	PowerStateOff = "Off"
+)
+
+// how the power button is pressed, a momentary press asks the os to shut
+// down, press and hold cuts the power, cold boot powers off then on and
+// reset restarts without removing power
+const (
+	PowerControlMomentaryPress = "MomentaryPress"
+	PowerControlPressAndHold   = "PressAndHold"
+	PowerControlColdBoot       = "ColdBoot"
+	PowerControlReset          = "Reset"
+)
+
+// powerStateRequest - body of a server hardware power state change
+func powerStateRequest(state, control string) map[string]interface{} {
+	return map[string]interface{}{"powerState": state, "powerControl": control}
+}
+
+// setServerHardwarePower - put a power state to the server hardware at uri
+// and wait for the task
+func setServerHardwarePower(c *ov.OVClient, uri, state, control string) error {
+	data, err := ovCall(c, rest.PUT, uri+"/powerState", powerStateRequest(state, control))
+	if err != nil {
+		return err
+	}
+	return waitForTaskResponse(c, data)
+}
+
+// PowerOn - power the hardware on
+func (h ServerHardwareDetail) PowerOn(c *ov.OVClient) error {
+	return setServerHardwarePower(c, h.URI, PowerStateOn, PowerControlMomentaryPress)
+}
+
+// PowerOff - power the hardware off, asking the os to shut down first
+// unless force holds the power button
+func (h ServerHardwareDetail) PowerOff(c *ov.OVClient, force bool) error {
+	control := PowerControlMomentaryPress
+	if force {
+		control = PowerControlPressAndHold
+	}
+	return setServerHardwarePower(c, h.URI, PowerStateOff, control)
+}
+
+// ColdBoot - remove the power and turn it back on
+func (h ServerHardwareDetail) ColdBoot(c *ov.OVClient) error {
+	return setServerHardwarePower(c, h.URI, PowerStateOff, PowerControlColdBoot)
+}
+
+// Reset - restart the hardware without removing the power
+func (h ServerHardwareDetail) Reset(c *ov.OVClient) error {
+	return setServerHardwarePower(c, h.URI, PowerStateOn, PowerControlReset)
+}
@@ -0,0 +1,62 @@
+package oneview
+
+import (
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// server hardware refresh states
+const (
+	RefreshStateNotRefreshing = "NotRefreshing"
+	RefreshStatePending       = "RefreshPending"
+	RefreshStateRefreshing    = "Refreshing"
+	RefreshStateFailed        = "RefreshFailed"
+)
+
+// NeedsRefresh - true when OneView lost track of the hardware, its state is
+// Unknown with no refresh under way or the last refresh failed, profiles
+// can not be applied until it is refreshed
+func (h ServerHardwareDetail) NeedsRefresh() bool {
+	switch h.RefreshState {
+	case RefreshStateFailed:
+		return true
+	case RefreshStatePending, RefreshStateRefreshing:
+		return false
+	}
+	return h.State == "Unknown"
+}
+
+// RefreshServerHardware - have OneView read the hardware at uri again and wait for it
+func RefreshServerHardware(c *ov.OVClient, uri string) error {
+	body := map[string]interface{}{"refreshState": RefreshStatePending}
+	data, err := ovCall(c, rest.PUT, uri+"/refreshState", body)
+	if err != nil {
+		return err
+	}
+	return waitForTaskResponse(c, data)
+}
+
+// Refresh - RefreshServerHardware for this hardware
+func (h ServerHardwareDetail) Refresh(c *ov.OVClient) error {
+	return RefreshServerHardware(c, h.URI)
+}
+
+// refreshStaleHardware - refresh the hardware before a profile goes on it
+// when OneView lost track of it
+func refreshStaleHardware(c *ov.OVClient, uri string) error {
+	h, err := GetServerHardware(c, uri)
+	if err != nil {
+		return err
+	}
+	if !h.NeedsRefresh() {
+		return nil
+	}
+	log.Infof("Refreshing %s, its state is %s and refresh state %s", h.Name, h.State, h.RefreshState)
+	if err := h.Refresh(c); err != nil {
+		return fmt.Errorf("unable to refresh %s : %w", h.Name, err)
+	}
+	return nil
+}
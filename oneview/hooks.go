@@ -0,0 +1,76 @@
+package oneview
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RequestHook - called before each appliance request the driver makes, it
+// can add or change headers, ie; to sign requests for an api gateway in front
+// of the appliance.  Returning an error stops the request.
+type RequestHook func(method, url string, headers map[string]string) error
+
+var (
+	requestHooksMu sync.RWMutex
+	requestHooks   []RequestHook
+	// the --oneview-header headers, kept apart from the hooks so loading a
+	// driver again replaces them instead of adding another hook
+	driverHeaders map[string]string
+)
+
+// AddRequestHook - run hook on every following request, hooks run in the
+// order they were added
+func AddRequestHook(hook RequestHook) {
+	requestHooksMu.Lock()
+	defer requestHooksMu.Unlock()
+	requestHooks = append(requestHooks, hook)
+}
+
+// applyRequestHooks - run the hooks over the headers of a request
+func applyRequestHooks(method, url string, headers map[string]string) error {
+	requestHooksMu.RLock()
+	defer requestHooksMu.RUnlock()
+	for k, v := range driverHeaders {
+		headers[k] = v
+	}
+	for _, hook := range requestHooks {
+		if err := hook(method, url, headers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setDriverHeaders - set the --oneview-header headers on every request
+func setDriverHeaders(headers map[string]string) {
+	requestHooksMu.Lock()
+	defer requestHooksMu.Unlock()
+	driverHeaders = headers
+}
+
+// StaticHeaders - hook setting the same headers on every request
+func StaticHeaders(extra map[string]string) RequestHook {
+	return func(method, url string, headers map[string]string) error {
+		for k, v := range extra {
+			headers[k] = v
+		}
+		return nil
+	}
+}
+
+// parseHeaders - headers given as Name: value
+func parseHeaders(list []string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, h := range list {
+		if strings.TrimSpace(h) == "" {
+			continue
+		}
+		i := strings.Index(h, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid option --oneview-header %q, must be Name: value", h)
+		}
+		headers[strings.TrimSpace(h[:i])] = strings.TrimSpace(h[i+1:])
+	}
+	return headers, nil
+}
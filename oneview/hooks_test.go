@@ -0,0 +1,68 @@
+package oneview
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHeaders(t *testing.T) {
+	h, err := parseHeaders([]string{"X-Gateway-Key: abc:123", " Authorization-Extra :token ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Gateway-Key": "abc:123", "Authorization-Extra": "token"}, h)
+
+	_, err = parseHeaders([]string{"no separator"})
+	assert.Error(t, err)
+}
+
+func TestRequestHooks(t *testing.T) {
+	saved := requestHooks
+	defer func() { requestHooks = saved }()
+	requestHooks = nil
+
+	AddRequestHook(StaticHeaders(map[string]string{"X-Gateway-Key": "abc"}))
+	AddRequestHook(func(method, url string, headers map[string]string) error {
+		headers["X-Signature"] = method + " " + url + " " + headers["X-Gateway-Key"]
+		return nil
+	})
+	headers := map[string]string{"auth": "session"}
+	assert.NoError(t, applyRequestHooks("GET", "https://ov/rest/version", headers))
+	assert.Equal(t, "GET https://ov/rest/version abc", headers["X-Signature"])
+	assert.Equal(t, "session", headers["auth"])
+}
+
+func TestDriverHeadersRestored(t *testing.T) {
+	defer setDriverHeaders(nil)
+
+	d := &Driver{}
+	assert.NoError(t, d.UnmarshalJSON([]byte(`{"ExtraHeaders": {"X-Gateway-Key": "abc"}}`)))
+	assert.NoError(t, d.UnmarshalJSON([]byte(`{"ExtraHeaders": {"X-Gateway-Key": "def"}}`)))
+	headers := map[string]string{}
+	assert.NoError(t, applyRequestHooks("GET", "https://ov/rest/version", headers))
+	assert.Equal(t, map[string]string{"X-Gateway-Key": "def"}, headers)
+}
+
+func TestCompressionRestored(t *testing.T) {
+	defer SetCompression(compressionEnabled())
+
+	assert.NoError(t, (&Driver{}).UnmarshalJSON([]byte(`{"DisableCompression": true}`)))
+	assert.False(t, compressionEnabled())
+	assert.NoError(t, (&Driver{}).UnmarshalJSON([]byte(`{}`)))
+	assert.True(t, compressionEnabled())
+}
+
+func TestCompressionHook(t *testing.T) {
+	defer SetCompression(compressionEnabled())
+
+	SetCompression(true)
+	headers := map[string]string{}
+	assert.NoError(t, compressionHook("GET", "https://ov/rest/server-hardware", headers))
+	assert.Empty(t, headers)
+	assert.False(t, newHTTPClient(true).Transport.(*http.Transport).DisableCompression)
+
+	SetCompression(false)
+	assert.NoError(t, compressionHook("GET", "https://ov/rest/server-hardware", headers))
+	assert.Equal(t, "identity", headers["Accept-Encoding"])
+	assert.True(t, newHTTPClient(true).Transport.(*http.Transport).DisableCompression)
+}
@@ -0,0 +1,58 @@
+package oneview
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// httpTimeout - timeout for the raw http calls the driver makes itself
+var httpTimeout = 10 * time.Minute
+
+// newHTTPClient - http client for calls the rest library can not make, like
+// streaming or ranged downloads
+func newHTTPClient(sslVerify bool) *http.Client {
+	tr := &http.Transport{
+		Proxy:              http.ProxyFromEnvironment,
+		DialContext:        resolveDialContext,
+		TLSClientConfig:    &tls.Config{InsecureSkipVerify: !sslVerify},
+		DisableCompression: !compressionEnabled(),
+	}
+	return &http.Client{Transport: tr, Timeout: httpTimeout}
+}
+
+// newApplianceHTTPClient - newHTTPClient for calls to the appliances, which
+// checks pinned certificate fingerprints when there are any and keeps the
+// rate and session limits the appliance reports
+func newApplianceHTTPClient(sslVerify bool) *http.Client {
+	c := newHTTPClient(sslVerify)
+	tr := c.Transport.(*http.Transport)
+	tr.TLSClientConfig = applianceTLSConfig(sslVerify)
+	c.Transport = limitsTransport{next: tr}
+	return c
+}
+
+// newApplianceRequest - build a raw request to the appliance carrying the
+// session headers of the ov client
+func newApplianceRequest(c *ov.OVClient, method, uri string) (*http.Request, error) {
+	if err := authorizeOV(c); err != nil {
+		return nil, err
+	}
+	recordThrottle(c, throttle(c.Endpoint))
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.Endpoint, "/")+uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	headers := c.GetAuthHeaderMap()
+	if err := applyRequestHooks(method, req.URL.String(), headers); err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	recordCall(c, method, 0, nil)
+	return req, nil
+}
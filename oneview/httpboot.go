@@ -0,0 +1,204 @@
+package oneview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// provisioning backends, how the os gets on the server
+const (
+	ProvisioningICSP     = "icsp"
+	ProvisioningHTTPBoot = "http-boot"
+)
+
+// uefi bios attributes for http boot
+const (
+	// biosURLBootFile - the image the uefi network stack fetches
+	biosURLBootFile = "UrlBootFile"
+	// biosDhcpv4 - the boot url is fetched over an address from dhcp
+	biosDhcpv4 = "Dhcpv4"
+)
+
+// httpBootPollInterval - how often the address and ssh port are tried
+var httpBootPollInterval = 10 * time.Second
+
+// Error messages
+var (
+	ErrDriverInvalidProvisioning = errors.New("Invalid option --oneview-provisioning, must be icsp or http-boot")
+	ErrDriverMissingHTTPBootURL  = errors.New("Missing option --oneview-http-boot-url, http-boot provisioning needs an http or https image url")
+)
+
+// HTTPBoot - uefi http boot provisioning, the server fetches a boot image
+// straight from a web server with no ICsp, pxe server or virtual media.  The
+// image brings the os up with the docker account, the driver then installs
+// its key over ssh as it does after ICsp.
+type HTTPBoot struct {
+	// URL - the image, http or https
+	URL string
+	// Address - where the booted os is reached, empty looks up the machine
+	// name, for images registering themselves in dns over dhcp
+	Address string
+}
+
+// newHTTPBoot - the settings from flag values
+func newHTTPBoot(provisioning, imageURL, address string) (HTTPBoot, error) {
+	switch provisioning {
+	case "", ProvisioningICSP:
+		return HTTPBoot{}, nil
+	case ProvisioningHTTPBoot:
+	default:
+		return HTTPBoot{}, ErrDriverInvalidProvisioning
+	}
+	u, err := url.Parse(imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return HTTPBoot{}, ErrDriverMissingHTTPBootURL
+	}
+	return HTTPBoot{URL: imageURL, Address: address}, nil
+}
+
+// usesICSP - false when the os is not installed by ICsp
+func (d *Driver) usesICSP() bool {
+	return d.Provisioning != ProvisioningHTTPBoot
+}
+
+// apply - set uefi boot mode, network boot first and the boot url on a raw
+// profile, returns true when something was changed
+func (h HTTPBoot) apply(profile map[string]interface{}) (bool, error) {
+	changed := false
+	bootMode, _ := profile["bootMode"].(map[string]interface{})
+	managed, _ := bootMode["manageMode"].(bool)
+	mode, _ := bootMode["mode"].(string)
+	switch {
+	case managed && mode == BootModeBIOS:
+		return false, fmt.Errorf("http boot needs a uefi boot mode, the profile boots %s, use --oneview-boot-mode UEFI", mode)
+	case !managed || mode == "":
+		if bootMode == nil {
+			bootMode = map[string]interface{}{}
+		}
+		bootMode["manageMode"] = true
+		bootMode["mode"] = BootModeUEFIOptimized
+		bootMode["pxeBootPolicy"] = "Auto"
+		profile["bootMode"] = bootMode
+		changed = true
+	}
+
+	boot, _ := profile["boot"].(map[string]interface{})
+	if boot == nil {
+		boot = map[string]interface{}{}
+		profile["boot"] = boot
+	}
+	order, _ := boot["order"].([]interface{})
+	if manage, _ := boot["manageBoot"].(bool); !manage || len(order) == 0 || order[0] != "PXE" {
+		newOrder := []interface{}{"PXE"}
+		for _, o := range order {
+			if o != "PXE" {
+				newOrder = append(newOrder, o)
+			}
+		}
+		boot["manageBoot"] = true
+		boot["order"] = newOrder
+		changed = true
+	}
+
+	changed = setBIOSSetting(profile, biosURLBootFile, h.URL) || changed
+	changed = setBIOSSetting(profile, biosDhcpv4, "Enabled") || changed
+	return changed, nil
+}
+
+// httpBootProvider - machines from OneView server profiles booting their os
+// from --oneview-http-boot-url, everything but the os is done as with ICsp
+type httpBootProvider struct {
+	oneviewProvider
+	address string
+}
+
+// Deploy - point the profile at the boot image, power on and wait for ssh
+func (p *httpBootProvider) Deploy() error {
+	d := p.d
+	if err := d.updateProfile(d.HTTPBoot.apply); err != nil {
+		return err
+	}
+	log.Infof("Booting %s from %s...", d.MachineName, d.HTTPBoot.URL)
+	if err := libraryCall(d.ClientOV, "PUT", d.Hardware.PowerOn); err != nil {
+		return err
+	}
+	ctx := interruptCtx
+	if d.OSTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.OSTimeout)
+		defer cancel()
+	}
+	address, err := waitForSSHPort(ctx, d.httpBootAddress, d.SSHPort)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%w after %s, %s never answered on ssh : %v", ErrOSDeploymentTimeout, d.OSTimeout, d.MachineName, err)
+		}
+		return err
+	}
+	p.address = address
+	return nil
+}
+
+// Address - the address ssh answered on
+func (p *httpBootProvider) Address() (string, error) {
+	if p.address != "" {
+		return p.address, nil
+	}
+	return p.d.httpBootAddress()
+}
+
+// PowerOn - power on the blade, there is no ICsp to ask about the os
+func (p *httpBootProvider) PowerOn() error {
+	return libraryCall(p.d.ClientOV, "PUT", p.d.Hardware.PowerOn)
+}
+
+// Release - delete the profile
+func (p *httpBootProvider) Release() error {
+	return p.d.releaseProfile()
+}
+
+// httpBootAddress - the configured address, or the machine name looked up
+func (d *Driver) httpBootAddress() (string, error) {
+	if d.HTTPBoot.Address != "" {
+		return d.HTTPBoot.Address, nil
+	}
+	addrs, err := net.LookupHost(d.MachineName)
+	if err != nil {
+		return "", err
+	}
+	return addrs[0], nil
+}
+
+// waitForSSHPort - wait until an address is known and takes connections on
+// the ssh port, returns the address
+func waitForSSHPort(ctx context.Context, address func() (string, error), port int) (string, error) {
+	var lastErr error
+	for {
+		ip, err := address()
+		if err == nil {
+			var conn net.Conn
+			conn, err = net.DialTimeout("tcp", net.JoinHostPort(ip, strconv.Itoa(port)), httpBootPollInterval)
+			if err == nil {
+				conn.Close()
+				return ip, nil
+			}
+		}
+		lastErr = err
+		log.Debugf("waiting for ssh : %s", err)
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.Canceled {
+				return "", ErrCancelled
+			}
+			return "", lastErr
+		case <-time.After(httpBootPollInterval):
+		}
+	}
+}
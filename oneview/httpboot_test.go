@@ -0,0 +1,79 @@
+package oneview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPBoot(t *testing.T) {
+	h, err := newHTTPBoot("", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, HTTPBoot{}, h)
+
+	h, err = newHTTPBoot(ProvisioningHTTPBoot, "https://images.example.com/docker.efi", "10.0.0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, HTTPBoot{URL: "https://images.example.com/docker.efi", Address: "10.0.0.5"}, h)
+
+	_, err = newHTTPBoot(ProvisioningHTTPBoot, "tftp://images/docker.efi", "")
+	assert.Equal(t, ErrDriverMissingHTTPBootURL, err)
+	_, err = newHTTPBoot("ipxe", "", "")
+	assert.Equal(t, ErrDriverInvalidProvisioning, err)
+}
+
+func TestHTTPBootApply(t *testing.T) {
+	var profile map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"boot": {"manageBoot": true, "order": ["HardDisk", "PXE"]},
+		"bios": {"manageBios": true, "overriddenSettings": [{"id": "UrlBootFile", "value": "http://old"}]}}`), &profile))
+	h := HTTPBoot{URL: "http://images/docker.efi"}
+
+	changed, err := h.apply(profile)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]interface{}{"manageMode": true, "mode": BootModeUEFIOptimized, "pxeBootPolicy": "Auto"}, profile["bootMode"])
+	assert.Equal(t, []interface{}{"PXE", "HardDisk"}, profile["boot"].(map[string]interface{})["order"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "UrlBootFile", "value": "http://images/docker.efi"},
+		map[string]interface{}{"id": "Dhcpv4", "value": "Enabled"},
+	}, profile["bios"].(map[string]interface{})["overriddenSettings"])
+
+	changed, err = h.apply(profile)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	profile["bootMode"] = map[string]interface{}{"manageMode": true, "mode": BootModeBIOS}
+	_, err = h.apply(profile)
+	assert.Error(t, err)
+}
+
+func TestWaitForSSHPort(t *testing.T) {
+	defer func(i time.Duration) { httpBootPollInterval = i }(httpBootPollInterval)
+	httpBootPollInterval = 10 * time.Millisecond
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	tries := 0
+	address := func() (string, error) {
+		if tries++; tries < 3 {
+			return "", errors.New("not in dns yet")
+		}
+		return "127.0.0.1", nil
+	}
+	ip, err := waitForSSHPort(context.Background(), address, port)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = waitForSSHPort(ctx, func() (string, error) { return "", errors.New("not in dns") }, port)
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package oneview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	icspJobsURI    = "/rest/os-deployment-jobs"
+	icspServersURI = "/rest/os-deployment-servers"
+	// icspCancelGrace - how long we give a cancelled job to stop
+	icspCancelGrace = 2 * time.Minute
+)
+
+// ErrOSDeploymentTimeout - the os build plans did not finish in time
+var ErrOSDeploymentTimeout = errors.New("OS deployment timed out, see --oneview-os-timeout")
+
+// icspJob - the parts of an icsp os deployment job we look at
+type icspJob struct {
+	URI           string `json:"uri,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Running       string `json:"running,omitempty"`
+	State         string `json:"state,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Created       string `json:"created,omitempty"`
+	Modified      string `json:"modified,omitempty"`
+	JobServerInfo []struct {
+		JobServerURI string `json:"jobServerUri,omitempty"`
+		ServerName   string `json:"serverName,omitempty"`
+	} `json:"jobServerInfo,omitempty"`
+	JobProgress []struct {
+		CurrentStepName   string `json:"currentStepName,omitempty"`
+		JobCompletedSteps int    `json:"jobCompletedSteps,omitempty"`
+		JobTotalSteps     int    `json:"jobTotalSteps,omitempty"`
+	} `json:"jobProgress,omitempty"`
+	JobResult []struct {
+		JobMessage            string `json:"jobMessage,omitempty"`
+		JobResultErrorDetails string `json:"jobResultErrorDetails,omitempty"`
+		JobResultLogDetails   string `json:"jobResultLogDetails,omitempty"`
+	} `json:"jobResult,omitempty"`
+}
+
+// isRunning - icsp reports running as a string
+func (j icspJob) isRunning() bool {
+	return strings.EqualFold(j.Running, "true")
+}
+
+// forServer - true when the job targets the server uri
+func (j icspJob) forServer(serverURI string) bool {
+	for _, s := range j.JobServerInfo {
+		if s.JobServerURI == serverURI {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnostics - what the job got to and what it reported
+func (j icspJob) diagnostics() string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("job %s (%s) state %s status %s", j.Name, j.URI, j.State, j.Status))
+	for _, p := range j.JobProgress {
+		lines = append(lines, fmt.Sprintf("  step %d of %d : %s", p.JobCompletedSteps, p.JobTotalSteps, p.CurrentStepName))
+	}
+	for _, r := range j.JobResult {
+		for _, s := range []string{r.JobMessage, r.JobResultErrorDetails, r.JobResultLogDetails} {
+			if s = strings.TrimSpace(s); s != "" {
+				lines = append(lines, "  "+s)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// listICSPJobs - every os deployment job icsp knows about
+func listICSPJobs(c *icsp.ICSPClient) ([]icspJob, error) {
+	var jobs []icspJob
+	uri := icspJobsURI
+	for uri != "" {
+		data, err := icspCall(c, rest.GET, uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Members     []icspJob `json:"members"`
+			NextPageURI string    `json:"nextPageUri"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page.Members...)
+		if page.NextPageURI == uri {
+			break
+		}
+		uri = page.NextPageURI
+	}
+	return jobs, nil
+}
+
+// runningJobsForServer - jobs still running against an icsp server
+func runningJobsForServer(c *icsp.ICSPClient, serverURI string) ([]icspJob, error) {
+	jobs, err := listICSPJobs(c)
+	if err != nil {
+		return nil, err
+	}
+	var running []icspJob
+	for _, j := range jobs {
+		if j.isRunning() && j.forServer(serverURI) {
+			running = append(running, j)
+		}
+	}
+	return running, nil
+}
+
+// CancelICSPJob - ask icsp to stop a running os deployment job
+func CancelICSPJob(c *icsp.ICSPClient, jobURI string) error {
+	_, err := icspCall(c, rest.PUT, jobURI+"/cancel", nil)
+	return err
+}
+
+// customizeServerWithTimeout - run the os build plans, giving up after the
+// os timeout or an interrupt by cancelling the icsp jobs and powering the
+// blade off
+func (d *Driver) customizeServerWithTimeout() error {
+	stopStream := d.streamInstall()
+	defer stopStream()
+	done := make(chan error, 1)
+	go func() {
+		done <- d.customizeServerWithRetries()
+	}()
+	ctx := interruptCtx
+	if d.OSTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.OSTimeout)
+		defer cancel()
+	}
+	var reason error
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			log.Errorf("OS deployment for %s did not finish within %s, cancelling", d.MachineName, d.OSTimeout)
+			reason = fmt.Errorf("%w after %s", ErrOSDeploymentTimeout, d.OSTimeout)
+		} else {
+			reason = ErrCancelled
+		}
+	}
+	diag := d.abortOSDeployment()
+
+	// let the build plan wait notice the cancel before we move on
+	select {
+	case <-done:
+	case <-time.After(icspCancelGrace):
+		log.Warnf("OS deployment for %s still running after cancel", d.MachineName)
+	}
+	if reason == ErrCancelled || diag == "" {
+		return reason
+	}
+	return fmt.Errorf("%w\n%s", reason, diag)
+}
+
+// icspServer - the icsp server of the machine blade, by its serial number
+func (d *Driver) icspServer() (server icsp.Server, err error) {
+	err = libraryCall(d.ClientICSP, "GET", func() error {
+		server, err = d.ClientICSP.GetServerBySerialNumber(d.Profile.SerialNumber.String())
+		return err
+	})
+	return server, err
+}
+
+// abortOSDeployment - cancel every running job for the machine and power
+// the blade off, returns diagnostics for the cancelled jobs
+func (d *Driver) abortOSDeployment() string {
+	var diags []string
+	server, err := d.icspServer()
+	if err != nil || server.MID == "" {
+		log.Warnf("unable to find %s in icsp to cancel its jobs : %v", d.MachineName, err)
+	} else {
+		jobs, err := runningJobsForServer(d.ClientICSP, icspServersURI+"/"+server.MID)
+		if err != nil {
+			log.Warnf("unable to list icsp jobs for %s : %s", d.MachineName, err)
+		}
+		for _, j := range jobs {
+			diags = append(diags, j.diagnostics())
+			if err := CancelICSPJob(d.ClientICSP, j.URI); err != nil {
+				log.Warnf("unable to cancel icsp job %s : %s", j.URI, err)
+			}
+		}
+	}
+	if err := libraryCall(d.ClientOV, "PUT", d.Hardware.PowerOff); err != nil {
+		log.Warnf("unable to power off %s : %s", d.MachineName, err)
+	}
+	return strings.Join(diags, "\n")
+}
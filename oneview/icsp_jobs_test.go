@@ -0,0 +1,79 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testICSPJob = `{
+	"uri": "/rest/os-deployment-jobs/1240001",
+	"name": "RHEL71_DOCKER_1.8",
+	"running": "true",
+	"state": "STATUS_ACTIVE",
+	"status": "ok",
+	"jobServerInfo": [{"jobServerUri": "/rest/os-deployment-servers/10001", "serverName": "docker-1"}],
+	"jobProgress": [{"currentStepName": "Wait for HP SA Agent", "jobCompletedSteps": 12, "jobTotalSteps": 25}],
+	"jobResult": [{"jobMessage": "waiting on agent", "jobResultErrorDetails": ""}]
+}`
+
+func TestICSPJob(t *testing.T) {
+	var j icspJob
+	assert.NoError(t, json.Unmarshal([]byte(testICSPJob), &j))
+	assert.True(t, j.isRunning())
+	assert.True(t, j.forServer("/rest/os-deployment-servers/10001"))
+	assert.False(t, j.forServer("/rest/os-deployment-servers/10002"))
+
+	diag := j.diagnostics()
+	assert.Contains(t, diag, "step 12 of 25 : Wait for HP SA Agent")
+	assert.Contains(t, diag, "waiting on agent")
+}
+
+func TestClassifyICSPJob(t *testing.T) {
+	var j icspJob
+	assert.NoError(t, json.Unmarshal([]byte(testICSPJob), &j))
+	assert.False(t, j.isFailed())
+
+	j.Running, j.State = "false", "STATUS_FAILURE"
+	assert.True(t, j.isFailed())
+
+	j.JobResult[0].JobResultErrorDetails = "Media server timed out serving the install image"
+	e := classifyICSPJob(j)
+	assert.Equal(t, JobErrorMediaTimeout, e.Reason)
+	assert.True(t, e.Retryable())
+	assert.Equal(t, CategoryTransient, CategoryOf(e))
+	assert.True(t, errors.Is(e, ErrOSDeploymentFailed))
+
+	j.JobResult[0].JobResultErrorDetails = "Insufficient disk space on the target, connection reset"
+	e = classifyICSPJob(j)
+	assert.Equal(t, JobErrorDiskTooSmall, e.Reason)
+	assert.False(t, e.Retryable())
+
+	j.JobResult[0].JobResultErrorDetails = "something else"
+	e = classifyICSPJob(j)
+	assert.Equal(t, JobErrorUnknown, e.Reason)
+	assert.False(t, e.Retryable())
+}
+
+func TestLastFailedJob(t *testing.T) {
+	server := "/rest/os-deployment-servers/10001"
+	job := func(uri, created, modified string) icspJob {
+		var j icspJob
+		assert.NoError(t, json.Unmarshal([]byte(testICSPJob), &j))
+		j.URI, j.Running, j.State, j.Created, j.Modified = uri, "false", "STATUS_FAILURE", created, modified
+		return j
+	}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	jobs := []icspJob{
+		job("/rest/os-deployment-jobs/1", "2026-03-01T09:00:00.000Z", "2026-03-01T09:40:00.000Z"),
+		job("/rest/os-deployment-jobs/2", "2026-03-01T10:05:00.000Z", "2026-03-01T10:30:00.000Z"),
+		job("/rest/os-deployment-jobs/3", "", "2026-03-01T11:00:00.000Z"),
+	}
+	assert.Equal(t, "/rest/os-deployment-jobs/2", lastFailedJob(jobs, server, start).URI)
+	// a failure left from an earlier create is not this attempt's
+	assert.Nil(t, lastFailedJob(jobs[:1], server, start))
+	assert.Nil(t, lastFailedJob(jobs, server, start.Add(time.Hour)))
+}
@@ -0,0 +1,149 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// ErrOSDeploymentFailed - an icsp os deployment job failed
+var ErrOSDeploymentFailed = errors.New("OS deployment failed")
+
+// icsp job failure reasons
+const (
+	JobErrorMediaTimeout = "media-timeout"
+	JobErrorNetwork      = "network"
+	JobErrorInvalidPlan  = "invalid-plan"
+	JobErrorDiskTooSmall = "disk-too-small"
+	JobErrorUnknown      = "unknown"
+)
+
+// jobErrorKind - a reason an icsp job fails, the fragments are icsp error
+// codes and messages, matched case insensitively
+type jobErrorKind struct {
+	Reason    string
+	Category  ErrorCategory
+	Fragments []string
+}
+
+// jobErrorKinds - checked in order, fatal reasons first so a job failing
+// for a bad plan after a network blip is not retried
+var jobErrorKinds = []jobErrorKind{
+	{JobErrorInvalidPlan, CategoryUser, []string{"invalid_build_plan", "invalid build plan", "build plan not found", "invalid os build plan", "unsupported os"}},
+	{JobErrorDiskTooSmall, CategoryResourceExhausted, []string{"disk_too_small", "disk too small", "insufficient disk", "not enough disk space", "no disks found"}},
+	{JobErrorMediaTimeout, CategoryTransient, []string{"media_timeout", "media server timed out", "media timeout", "timed out mounting", "unable to mount media"}},
+	{JobErrorNetwork, CategoryTransient, []string{"network_error", "network is unreachable", "connection timed out", "connection reset", "no route to host", "unable to contact the server", "pxe timeout"}},
+}
+
+// ICSPJobError - why an os deployment job failed and whether running the
+// build plans again may work
+type ICSPJobError struct {
+	Job         string
+	Reason      string
+	Category    ErrorCategory
+	Diagnostics string
+}
+
+func (e *ICSPJobError) Error() string {
+	return fmt.Sprintf("%s, %s : %s\n%s", ErrOSDeploymentFailed, e.Reason, e.Job, e.Diagnostics)
+}
+
+// Unwrap - ErrOSDeploymentFailed
+func (e *ICSPJobError) Unwrap() error {
+	return ErrOSDeploymentFailed
+}
+
+// Retryable - true for media and network trouble, false for failures
+// running the plans again will repeat
+func (e *ICSPJobError) Retryable() bool {
+	return e.Category == CategoryTransient
+}
+
+// isFailed - icsp reports a failed job in the state or the status
+func (j icspJob) isFailed() bool {
+	s := strings.ToLower(j.State + " " + j.Status)
+	return strings.Contains(s, "fail") || strings.Contains(s, "error")
+}
+
+// classifyICSPJob - the error for a failed job, unknown failures are fatal
+func classifyICSPJob(j icspJob) *ICSPJobError {
+	var details []string
+	for _, r := range j.JobResult {
+		details = append(details, r.JobMessage, r.JobResultErrorDetails)
+	}
+	msg := strings.ToLower(strings.Join(details, " "))
+	e := &ICSPJobError{Job: j.Name, Reason: JobErrorUnknown, Category: CategoryApplianceFault, Diagnostics: j.diagnostics()}
+	for _, k := range jobErrorKinds {
+		if containsAny(msg, k.Fragments) {
+			e.Reason, e.Category = k.Reason, k.Category
+			break
+		}
+	}
+	return e
+}
+
+// createdSince - true when icsp created the job at or after since, jobs
+// without a creation time are not
+func (j icspJob) createdSince(since time.Time) bool {
+	created, err := time.Parse(time.RFC3339, j.Created)
+	return err == nil && !created.Before(since)
+}
+
+// lastFailedJob - the most recent failed job of the server created since,
+// failures of earlier attempts and creates are not this attempt's
+func lastFailedJob(jobs []icspJob, serverURI string, since time.Time) *icspJob {
+	var last *icspJob
+	for i, j := range jobs {
+		if j.isRunning() || !j.isFailed() || !j.forServer(serverURI) || !j.createdSince(since) {
+			continue
+		}
+		if last == nil || j.Modified > last.Modified {
+			last = &jobs[i]
+		}
+	}
+	return last
+}
+
+// lastJobError - the error of the most recent failed job for the machine
+// created since, nil when icsp has no such job
+func (d *Driver) lastJobError(since time.Time) *ICSPJobError {
+	server, err := d.icspServer()
+	if err != nil || server.MID == "" {
+		log.Debugf("unable to find %s in icsp to check its jobs : %v", d.MachineName, err)
+		return nil
+	}
+	jobs, err := listICSPJobs(d.ClientICSP)
+	if err != nil {
+		log.Debugf("unable to list icsp jobs for %s : %s", d.MachineName, err)
+		return nil
+	}
+	last := lastFailedJob(jobs, icspServersURI+"/"+server.MID, since)
+	if last == nil {
+		return nil
+	}
+	return classifyICSPJob(*last)
+}
+
+// customizeServerWithRetries - run the os build plans, running them again
+// up to OSRetries times when a job fails for a retryable reason
+func (d *Driver) customizeServerWithRetries() error {
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err := d.customizeServer()
+		if err == nil {
+			return nil
+		}
+		jobErr := d.lastJobError(start)
+		if jobErr == nil {
+			return err
+		}
+		if !jobErr.Retryable() || attempt >= d.OSRetries || interruptCtx.Err() != nil {
+			return jobErr
+		}
+		log.Warnf("OS deployment for %s failed with %s, retrying %d of %d", d.MachineName, jobErr.Reason, attempt+1, d.OSRetries)
+		recordRetry(d.ClientICSP)
+	}
+}
@@ -0,0 +1,109 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+const icspHealthStatusURI = "/rest/appliance/health-status"
+
+// icspLowResourcePercent - free share of an appliance resource below which
+// pre-flight checks warn, ICsp deployments fail in odd ways when its disk
+// fills up
+const icspLowResourcePercent = 10
+
+// ErrICSPAPIVersion - the ICsp appliance does not speak the api version the
+// driver was given
+var ErrICSPAPIVersion = errors.New("ICSP API version not supported by the appliance")
+
+// ICSPVersion - api versions and software build of the ICsp appliance,
+// they follow their own numbering rather than OneView's
+type ICSPVersion struct {
+	API   icsp.APIVersion  `json:"api"`
+	Build ApplianceVersion `json:"build"`
+}
+
+// String - ie; Insight Control server provisioning 7.6.0 (api 108 to 200)
+func (v ICSPVersion) String() string {
+	b := v.Build
+	if b.Family == "" {
+		b.Family = "ICsp"
+	}
+	return fmt.Sprintf("%s (api %d to %d)", b, v.API.MinimumVersion, v.API.CurrentVersion)
+}
+
+// Supports - true when the appliance takes calls at the api version
+func (v ICSPVersion) Supports(apiVersion int) bool {
+	return apiVersion >= v.API.MinimumVersion && apiVersion <= v.API.CurrentVersion
+}
+
+// GetIcspVersion - the api versions and software build of the ICsp appliance
+func GetIcspVersion(ic *icsp.ICSPClient) (ICSPVersion, error) {
+	var v ICSPVersion
+	var err error
+	if v.API, err = ic.GetAPIVersion(); err != nil {
+		return v, err
+	}
+	data, err := icspCall(ic, rest.GET, applianceVersionURI, nil)
+	if err != nil {
+		return v, err
+	}
+	err = json.Unmarshal(data, &v.Build)
+	return v, err
+}
+
+// ApplianceResource - use of one appliance resource, ie; disk or memory
+type ApplianceResource struct {
+	ResourceType  string `json:"resourceType"`
+	Available     int64  `json:"available"`
+	Capacity      int64  `json:"capacity"`
+	StatusMessage string `json:"statusMessage,omitempty"`
+}
+
+// Low - true when less than icspLowResourcePercent of the resource is free
+func (r ApplianceResource) Low() bool {
+	return r.Capacity > 0 && r.Available*100 < r.Capacity*icspLowResourcePercent
+}
+
+// ICSPStatus - health of the ICsp appliance resources
+type ICSPStatus struct {
+	Resources []ApplianceResource `json:"members"`
+}
+
+// Problems - one line for each resource running low
+func (s ICSPStatus) Problems() []string {
+	var p []string
+	for _, r := range s.Resources {
+		if r.Low() {
+			p = append(p, fmt.Sprintf("%s is low, %d of %d free: %s", r.ResourceType, r.Available, r.Capacity, r.StatusMessage))
+		}
+	}
+	return p
+}
+
+// GetIcspStatus - health of the ICsp appliance
+func GetIcspStatus(ic *icsp.ICSPClient) (ICSPStatus, error) {
+	var s ICSPStatus
+	data, err := icspCall(ic, rest.GET, icspHealthStatusURI, nil)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// checkICSPVersion - fail when the appliance does not take the api version
+// the driver calls it with
+func checkICSPVersion(v ICSPVersion, apiVersion int) error {
+	if v.API.CurrentVersion <= 0 {
+		return fmt.Errorf("unable to get a valid version from icsp: %+v", v.API)
+	}
+	if !v.Supports(apiVersion) {
+		return fmt.Errorf("%w: --oneview-icsp-apiversion %d, the appliance is %s", ErrICSPAPIVersion, apiVersion, v)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckICSPVersion(t *testing.T) {
+	v := ICSPVersion{
+		API:   icsp.APIVersion{MinimumVersion: 1, CurrentVersion: 108},
+		Build: ApplianceVersion{SoftwareVersion: "7.5.0-1234"},
+	}
+	assert.NoError(t, checkICSPVersion(v, 108))
+	err := checkICSPVersion(v, 200)
+	assert.True(t, errors.Is(err, ErrICSPAPIVersion))
+	assert.Contains(t, err.Error(), "the appliance is ICsp 7.5.0-1234 (api 1 to 108)")
+	assert.Equal(t, CategoryUser, CategoryOf(err))
+
+	assert.Error(t, checkICSPVersion(ICSPVersion{}, 200))
+}
+
+func TestICSPStatusProblems(t *testing.T) {
+	s := ICSPStatus{Resources: []ApplianceResource{
+		{ResourceType: "Memory", Available: 4000, Capacity: 16000},
+		{ResourceType: "Disk", Available: 50, Capacity: 1000, StatusMessage: "Disk space is low."},
+		{ResourceType: "CPU", Capacity: 0},
+	}}
+	assert.Equal(t, []string{"Disk is low, 50 of 1000 free: Disk space is low."}, s.Problems())
+}
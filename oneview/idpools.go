@@ -0,0 +1,220 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const idPoolsURI = "/rest/id-pools"
+
+// virtual identity pools
+const (
+	PoolVMAC = "vmac"
+	PoolVWWN = "vwwn"
+	PoolVSN  = "vsn"
+)
+
+// identityVirtual - the profile type value for identities from the pools
+const identityVirtual = "Virtual"
+
+// ErrIdentityPoolExhausted - a pool does not have enough free identities
+var ErrIdentityPoolExhausted = errors.New("Virtual identity pool exhausted")
+
+// PoolUtilization - how much of a virtual identity pool is in use, summed
+// over its enabled ranges
+type PoolUtilization struct {
+	Pool      string `json:"pool"`
+	Enabled   bool   `json:"enabled"`
+	Total     int    `json:"total"`
+	Allocated int    `json:"allocated"`
+	Free      int    `json:"free"`
+}
+
+// String - ie; vmac 1010 of 1024 allocated, 14 free
+func (p PoolUtilization) String() string {
+	return fmt.Sprintf("%s %d of %d allocated, %d free", p.Pool, p.Allocated, p.Total, p.Free)
+}
+
+// PoolExhaustedError - the pool and how much the create needed from it
+type PoolExhaustedError struct {
+	Pool   PoolUtilization
+	Needed int
+}
+
+func (e *PoolExhaustedError) Error() string {
+	return fmt.Sprintf("%s: need %d from %s", ErrIdentityPoolExhausted, e.Needed, e.Pool)
+}
+
+// Unwrap - ErrIdentityPoolExhausted
+func (e *PoolExhaustedError) Unwrap() error {
+	return ErrIdentityPoolExhausted
+}
+
+// GetPoolUtilization - usage of a virtual identity pool
+func GetPoolUtilization(c *ov.OVClient, pool string) (PoolUtilization, error) {
+	u := PoolUtilization{Pool: pool}
+	data, err := ovCall(c, rest.GET, idPoolsURI+"/"+pool, nil)
+	if err != nil {
+		return u, err
+	}
+	var p struct {
+		Enabled   bool     `json:"enabled"`
+		RangeURIs []string `json:"rangeUris"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return u, err
+	}
+	u.Enabled = p.Enabled
+	for _, uri := range p.RangeURIs {
+		data, err := ovCall(c, rest.GET, uri, nil)
+		if err != nil {
+			return u, err
+		}
+		var r struct {
+			Enabled          bool `json:"enabled"`
+			TotalCount       int  `json:"totalCount"`
+			AllocatedIDCount int  `json:"allocatedIdCount"`
+			FreeIDCount      int  `json:"freeIdCount"`
+		}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return u, err
+		}
+		if !r.Enabled {
+			continue
+		}
+		u.Total += r.TotalCount
+		u.Allocated += r.AllocatedIDCount
+		u.Free += r.FreeIDCount
+	}
+	return u, nil
+}
+
+// profileIdentities - the virtual identities a profile body holds, by pool
+func profileIdentities(profile map[string]interface{}) map[string][]string {
+	ids := map[string][]string{}
+	add := func(pool string, v interface{}) {
+		if s, _ := v.(string); s != "" {
+			ids[pool] = append(ids[pool], s)
+		}
+	}
+	if profile["serialNumberType"] == identityVirtual {
+		add(PoolVSN, profile["serialNumber"])
+	}
+	for _, conn := range profileConnections(profile) {
+		if profile["macType"] == identityVirtual {
+			add(PoolVMAC, conn["mac"])
+		}
+		if profile["wwnType"] == identityVirtual {
+			add(PoolVWWN, conn["wwpn"])
+			add(PoolVWWN, conn["wwnn"])
+		}
+	}
+	return ids
+}
+
+// profileIdentityNeeds - how many identities of each pool a profile body
+// will take when it is created, every connection gets a mac and fibre
+// channel connections a port and node wwn
+func profileIdentityNeeds(profile map[string]interface{}) map[string]int {
+	needs := map[string]int{}
+	if profile["serialNumberType"] == identityVirtual {
+		needs[PoolVSN]++
+	}
+	for _, conn := range profileConnections(profile) {
+		if profile["macType"] == identityVirtual {
+			needs[PoolVMAC]++
+		}
+		if profile["wwnType"] == identityVirtual && conn["functionType"] == "FibreChannel" {
+			needs[PoolVWWN] += 2
+		}
+	}
+	return needs
+}
+
+// checkPoolCapacity - fail with a PoolExhaustedError before creating a
+// profile the pools can not hold
+func checkPoolCapacity(c *ov.OVClient, needs map[string]int) error {
+	var pools []string
+	for pool := range needs {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	for _, pool := range pools {
+		u, err := GetPoolUtilization(c, pool)
+		if err != nil {
+			return err
+		}
+		log.Debugf("identity pool %s", u)
+		if !u.Enabled || u.Free < needs[pool] {
+			return &PoolExhaustedError{Pool: u, Needed: needs[pool]}
+		}
+	}
+	return nil
+}
+
+// allocatedIdentities - the ids that are still allocated in a pool
+func allocatedIdentities(c *ov.OVClient, pool string, ids []string) ([]string, error) {
+	query := map[string]interface{}{"idList": ids}
+	data, err := ovQueryCall(c, rest.GET, idPoolsURI+"/"+pool+"/checkrangeavailability", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	var available struct {
+		IDList []string `json:"idList"`
+	}
+	if err := json.Unmarshal(data, &available); err != nil {
+		return nil, err
+	}
+	free := map[string]bool{}
+	for _, id := range available.IDList {
+		free[strings.ToUpper(id)] = true
+	}
+	var allocated []string
+	for _, id := range ids {
+		if !free[strings.ToUpper(id)] {
+			allocated = append(allocated, id)
+		}
+	}
+	return allocated, nil
+}
+
+// collectIdentities - force ids back into their pool
+func collectIdentities(c *ov.OVClient, pool string, ids []string) error {
+	_, err := ovCall(c, rest.PUT, idPoolsURI+"/"+pool+"/collector", map[string]interface{}{"idList": ids})
+	return err
+}
+
+// ReclaimIdentities - check the identities of a deleted profile went back
+// to their pools, with force the ones that did not are collected.  Returns
+// the identities still allocated, by pool.
+func ReclaimIdentities(c *ov.OVClient, ids map[string][]string, force bool) (map[string][]string, error) {
+	left := map[string][]string{}
+	for pool, list := range ids {
+		if len(list) == 0 {
+			continue
+		}
+		allocated, err := allocatedIdentities(c, pool, list)
+		if err != nil {
+			return left, err
+		}
+		if len(allocated) == 0 {
+			continue
+		}
+		if !force {
+			left[pool] = allocated
+			continue
+		}
+		log.Infof("Returning %d %s identities to the pool : %s", len(allocated), pool, strings.Join(allocated, ", "))
+		if err := collectIdentities(c, pool, allocated); err != nil {
+			return left, err
+		}
+	}
+	return left, nil
+}
@@ -0,0 +1,40 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var identityProfile = map[string]interface{}{
+	"serialNumberType": "Virtual",
+	"serialNumber":     "VCGE9KB041",
+	"macType":          "Virtual",
+	"wwnType":          "Virtual",
+	"connections": []interface{}{
+		map[string]interface{}{"id": 1.0, "functionType": "Ethernet", "mac": "A2:00:00:00:00:01"},
+		map[string]interface{}{"id": 2.0, "functionType": "FibreChannel", "mac": "A2:00:00:00:00:02",
+			"wwpn": "10:00:00:00:00:00:00:01", "wwnn": "10:00:00:00:00:00:00:02"},
+	},
+}
+
+func TestProfileIdentities(t *testing.T) {
+	assert.Equal(t, map[string][]string{
+		PoolVSN:  {"VCGE9KB041"},
+		PoolVMAC: {"A2:00:00:00:00:01", "A2:00:00:00:00:02"},
+		PoolVWWN: {"10:00:00:00:00:00:00:01", "10:00:00:00:00:00:00:02"},
+	}, profileIdentities(identityProfile))
+	assert.Equal(t, map[string]int{PoolVSN: 1, PoolVMAC: 2, PoolVWWN: 2}, profileIdentityNeeds(identityProfile))
+
+	physical := map[string]interface{}{"macType": "Physical", "connections": identityProfile["connections"]}
+	assert.Empty(t, profileIdentities(physical))
+	assert.Empty(t, profileIdentityNeeds(physical))
+}
+
+func TestPoolExhaustedError(t *testing.T) {
+	err := classifyError(&PoolExhaustedError{Pool: PoolUtilization{Pool: PoolVMAC, Enabled: true, Total: 1024, Allocated: 1024}, Needed: 2})
+	assert.True(t, errors.Is(err, ErrIdentityPoolExhausted))
+	assert.Equal(t, CategoryResourceExhausted, CategoryOf(err))
+	assert.Contains(t, err.Error(), "need 2 from vmac 1024 of 1024 allocated, 0 free")
+}
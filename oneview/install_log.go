@@ -0,0 +1,289 @@
+package oneview
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// installLogInterval - how often the icsp jobs are read for new output
+	installLogInterval = 10 * time.Second
+	// iloSSHPort - the iLO ssh service the virtual serial port is on
+	iloSSHPort = 22
+	// vspCommand - iLO command attaching to the virtual serial port
+	vspCommand = "vsp"
+)
+
+// ErrDriverMissingILOKnownHosts - the serial console would send the iLO
+// password to any host key
+var ErrDriverMissingILOKnownHosts = errors.New("Missing option --oneview-ilo-known-hosts, --oneview-install-console only logs in to an iLO whose ssh host key is known")
+
+// ErrILOHostKeyUnknown - the iLO answered with a host key the known hosts
+// file does not have for it
+var ErrILOHostKeyUnknown = errors.New("iLO ssh host key is not in --oneview-ilo-known-hosts")
+
+// consoleEscapes - terminal control sequences a serial console sends
+var consoleEscapes = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]|\x1b[()][0-9A-Za-z]|\x1b[=>]`)
+
+// cleanConsoleLine - a serial console line without control sequences and
+// carriage returns, empty when nothing printable is left
+func cleanConsoleLine(line string) string {
+	line = consoleEscapes.ReplaceAllString(line, "")
+	if i := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); i >= 0 {
+		// a line redrawn in place, keep what is shown last
+		line = line[i+1:]
+	}
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < ' ' && r != '\t' {
+			return -1
+		}
+		return r
+	}, line))
+}
+
+// jobTail - how much of an icsp job has been printed
+type jobTail struct {
+	step    string
+	logSeen int
+}
+
+// installTail - the output of the icsp jobs of a machine printed so far
+type installTail struct {
+	jobs map[string]*jobTail
+}
+
+func newInstallTail() *installTail {
+	return &installTail{jobs: map[string]*jobTail{}}
+}
+
+// update - the lines of the job not printed yet, the step it moved to and
+// log output added since it was last read
+func (t *installTail) update(j icspJob) []string {
+	seen, ok := t.jobs[j.URI]
+	if !ok {
+		seen = &jobTail{}
+		t.jobs[j.URI] = seen
+	}
+	var lines []string
+	for _, p := range j.JobProgress {
+		step := fmt.Sprintf("step %d of %d : %s", p.JobCompletedSteps, p.JobTotalSteps, p.CurrentStepName)
+		if p.CurrentStepName != "" && step != seen.step {
+			seen.step = step
+			lines = append(lines, step)
+		}
+	}
+	var logs []string
+	for _, r := range j.JobResult {
+		if s := strings.TrimSpace(r.JobResultLogDetails); s != "" {
+			logs = append(logs, s)
+		}
+	}
+	all := strings.Join(logs, "\n")
+	if len(all) > seen.logSeen {
+		for _, l := range strings.Split(all[seen.logSeen:], "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				lines = append(lines, l)
+			}
+		}
+		seen.logSeen = len(all)
+	}
+	return lines
+}
+
+// pollingICSPClient - a copy of c for polling while the library uses c from
+// another goroutine, the rest client keeps the headers and query string of
+// a call in itself
+func pollingICSPClient(c *icsp.ICSPClient) *icsp.ICSPClient {
+	poller := *c
+	poller.Option = rest.Options{}
+	return &poller
+}
+
+// tailInstallJobs - print new output of the machine's icsp jobs until ctx
+// is done, polling with c.  The server only shows up in icsp once the build
+// plans start, so failing to find it is not an error.
+func (d *Driver) tailInstallJobs(ctx context.Context, c *icsp.ICSPClient) {
+	tail := newInstallTail()
+	for {
+		var server icsp.Server
+		err := libraryCall(d.ClientICSP, "GET", func() (err error) {
+			server, err = c.GetServerBySerialNumber(d.Profile.SerialNumber.String())
+			return err
+		})
+		if err == nil && server.MID != "" {
+			jobs, err := listICSPJobs(c)
+			if err != nil {
+				log.Debugf("unable to read icsp jobs for %s : %s", d.MachineName, err)
+			}
+			for _, j := range jobs {
+				if !j.forServer(icspServersURI + "/" + server.MID) {
+					continue
+				}
+				for _, line := range tail.update(j) {
+					log.Infof("[%s install] %s", d.MachineName, line)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(installLogInterval):
+		}
+	}
+}
+
+// knownHostKey - check key is a host key known_hosts data has for host,
+// written by ssh-keyscan or ssh without hashed host names.  A revoked key
+// is refused wherever it is in the file.
+func knownHostKey(data []byte, host string, key ssh.PublicKey) error {
+	names := map[string]bool{host: true, "[" + host + "]:" + strconv.Itoa(iloSSHPort): true}
+	known := false
+	for len(data) > 0 {
+		marker, hosts, k, _, rest, err := ssh.ParseKnownHosts(data)
+		if err != nil {
+			break
+		}
+		data = rest
+		if marker == "cert-authority" || !bytes.Equal(k.Marshal(), key.Marshal()) {
+			continue
+		}
+		for _, h := range hosts {
+			if !names[h] {
+				continue
+			}
+			if marker == "revoked" {
+				return fmt.Errorf("%w: the key of %s is revoked", ErrILOHostKeyUnknown, host)
+			}
+			known = true
+		}
+	}
+	if known {
+		return nil
+	}
+	sum := sha256.Sum256(key.Marshal())
+	return fmt.Errorf("%w: %s SHA256:%s", ErrILOHostKeyUnknown, host, base64.RawStdEncoding.EncodeToString(sum[:]))
+}
+
+// iloHostKeyCallback - accept the iLO only with a host key from the
+// knownHosts file, the iLO password is sent once the key is accepted
+func iloHostKeyCallback(knownHosts string) (func(string, net.Addr, ssh.PublicKey) error, error) {
+	if knownHosts == "" {
+		return nil, ErrDriverMissingILOKnownHosts
+	}
+	data, err := ioutil.ReadFile(knownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read --oneview-ilo-known-hosts : %w", err)
+	}
+	return func(address string, _ net.Addr, key ssh.PublicKey) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		return knownHostKey(data, host, key)
+	}, nil
+}
+
+// captureSerialConsole - print the iLO virtual serial port, where the os
+// installer writes its console, until ctx is done.  The iLO host key must
+// be in the knownHosts file.
+func captureSerialConsole(ctx context.Context, host, user, password, knownHosts string, print func(string)) error {
+	hostKey, err := iloHostKeyCallback(knownHosts)
+	if err != nil {
+		return err
+	}
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: hostKey,
+		Timeout:         30 * time.Second,
+	}
+	conn, err := ssh.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(iloSSHPort)), config)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the iLO at %s : %w", host, err)
+	}
+	defer conn.Close()
+	session, err := conn.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	if err := session.RequestPty("vt100", 25, 200, ssh.TerminalModes{ssh.ECHO: 0}); err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := session.Start(vspCommand); err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		session.Close()
+		conn.Close()
+	}()
+	return scanConsole(stdout, print)
+}
+
+// scanConsole - hand each printable line of a serial console to print
+func scanConsole(r io.Reader, print func(string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := cleanConsoleLine(scanner.Text()); line != "" {
+			print(line)
+		}
+	}
+	return scanner.Err()
+}
+
+// streamInstall - with --oneview-verbose-install print the icsp job output,
+// and the iLO serial console with --oneview-install-console, while the os
+// is installed.  The returned func stops the streaming.
+func (d *Driver) streamInstall() func() {
+	if !d.VerboseInstall {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(interruptCtx)
+	poller := pollingICSPClient(d.ClientICSP)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.tailInstallJobs(ctx, poller)
+	}()
+	if d.InstallConsole {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			host := d.Hardware.GetIloIPAddress()
+			err := captureSerialConsole(ctx, host, d.IloUser, d.IloPassword, d.IloKnownHosts, func(line string) {
+				log.Infof("[%s console] %s", d.MachineName, line)
+			})
+			if err != nil && ctx.Err() == nil {
+				log.Warnf("Stopped capturing the serial console of %s : %s", d.MachineName, err)
+			}
+		}()
+	}
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
@@ -0,0 +1,82 @@
+package oneview
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestCleanConsoleLine(t *testing.T) {
+	assert.Equal(t, "Installing packages", cleanConsoleLine("\x1b[1;32mInstalling packages\x1b[0m\r"))
+	assert.Equal(t, "50%", cleanConsoleLine("10%\r20%\r50%\r"))
+	assert.Equal(t, "", cleanConsoleLine("\x1b[2J\x1b[H"))
+}
+
+func TestInstallTail(t *testing.T) {
+	var j icspJob
+	assert.NoError(t, json.Unmarshal([]byte(`{"uri": "/rest/os-deployment-jobs/1",
+		"jobProgress": [{"currentStepName": "Deploy", "jobCompletedSteps": 1, "jobTotalSteps": 4}]}`), &j))
+	tail := newInstallTail()
+	assert.Equal(t, []string{"step 1 of 4 : Deploy"}, tail.update(j))
+	assert.Empty(t, tail.update(j))
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"uri": "/rest/os-deployment-jobs/1",
+		"jobProgress": [{"currentStepName": "Reboot", "jobCompletedSteps": 2, "jobTotalSteps": 4}],
+		"jobResult": [{"jobResultLogDetails": "partitioned sda\nformatted sda1"}]}`), &j))
+	assert.Equal(t, []string{"step 2 of 4 : Reboot", "partitioned sda", "formatted sda1"}, tail.update(j))
+
+	j.JobResult[0].JobResultLogDetails += "\ncopied image"
+	assert.Equal(t, []string{"copied image"}, tail.update(j))
+}
+
+func TestScanConsole(t *testing.T) {
+	var lines []string
+	err := scanConsole(strings.NewReader("\x1b[H\r\nanaconda started\r\n\r\n"), func(l string) { lines = append(lines, l) })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"anaconda started"}, lines)
+}
+
+func TestKnownHostKey(t *testing.T) {
+	newKey := func() ssh.PublicKey {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		pub, err := ssh.NewPublicKey(&k.PublicKey)
+		assert.NoError(t, err)
+		return pub
+	}
+	ilo, other := newKey(), newKey()
+	line := func(hosts string, key ssh.PublicKey) string {
+		return hosts + " " + string(ssh.MarshalAuthorizedKey(key))
+	}
+	data := []byte("# ssh-keyscan\n" + line("10.0.0.5,ilo1", ilo) + line("[10.0.0.6]:22", ilo) + line("10.0.0.7", ilo) + "@revoked " + line("10.0.0.7", ilo))
+
+	assert.NoError(t, knownHostKey(data, "10.0.0.5", ilo))
+	assert.NoError(t, knownHostKey(data, "ilo1", ilo))
+	assert.NoError(t, knownHostKey(data, "10.0.0.6", ilo))
+	for _, host := range []string{"10.0.0.7", "10.0.0.8"} {
+		assert.True(t, errors.Is(knownHostKey(data, host, ilo), ErrILOHostKeyUnknown), host)
+	}
+	assert.True(t, errors.Is(knownHostKey(data, "10.0.0.5", other), ErrILOHostKeyUnknown))
+
+	_, err := iloHostKeyCallback("")
+	assert.Equal(t, ErrDriverMissingILOKnownHosts, err)
+	assert.Equal(t, CategoryUser, CategoryOf(err))
+}
+
+func TestPollingICSPClient(t *testing.T) {
+	c := &icsp.ICSPClient{}
+	c.Endpoint, c.APIKey = "https://icsp", "session"
+	c.SetQueryString(map[string]interface{}{"filter": "a"})
+	p := pollingICSPClient(c)
+	assert.False(t, p == c)
+	assert.Equal(t, "session", p.APIKey)
+	assert.Nil(t, p.Option.Query)
+}
@@ -0,0 +1,203 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// ipv4 address pools, OneView hands these out to Synergy compute modules
+// and the driver to machines
+const (
+	ipv4SubnetsURI = idPoolsURI + "/ipv4/subnets"
+	ipv4RangesURI  = idPoolsURI + "/ipv4/ranges"
+)
+
+// ErrIPv4RangeNotFound - no ipv4 range has the name
+var ErrIPv4RangeNotFound = errors.New("IPv4 range not found")
+
+// IPv4Subnet - a subnet of the appliance ipv4 pool
+type IPv4Subnet struct {
+	Type       string   `json:"type,omitempty"`
+	URI        string   `json:"uri,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	NetworkID  string   `json:"networkId"`
+	SubnetMask string   `json:"subnetmask"`
+	Gateway    string   `json:"gateway,omitempty"`
+	Domain     string   `json:"domain,omitempty"`
+	DNSServers []string `json:"dnsServers,omitempty"`
+	RangeURIs  []string `json:"rangeUris,omitempty"`
+}
+
+// IPv4Fragment - a run of addresses of a range
+type IPv4Fragment struct {
+	StartAddress string `json:"startAddress"`
+	EndAddress   string `json:"endAddress"`
+}
+
+// IPv4Range - addresses of a subnet the appliance allocates from
+type IPv4Range struct {
+	Type               string         `json:"type,omitempty"`
+	URI                string         `json:"uri,omitempty"`
+	Name               string         `json:"name"`
+	SubnetURI          string         `json:"subnetUri"`
+	Enabled            bool           `json:"enabled"`
+	StartStopFragments []IPv4Fragment `json:"startStopFragments"`
+	TotalCount         int            `json:"totalCount,omitempty"`
+	AllocatedIDCount   int            `json:"allocatedIdCount,omitempty"`
+	FreeIDCount        int            `json:"freeIdCount,omitempty"`
+}
+
+// CreateIPv4Subnet - add a subnet to the ipv4 pool
+func CreateIPv4Subnet(c *ov.OVClient, s IPv4Subnet) (IPv4Subnet, error) {
+	s.Type = "Subnet"
+	var created IPv4Subnet
+	data, err := ovCall(c, rest.POST, ipv4SubnetsURI, s)
+	if err != nil {
+		return created, err
+	}
+	err = json.Unmarshal(data, &created)
+	return created, err
+}
+
+// GetIPv4Subnet - the subnet at uri
+func GetIPv4Subnet(c *ov.OVClient, uri string) (IPv4Subnet, error) {
+	var s IPv4Subnet
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// CreateIPv4Range - add a range of addresses to a subnet, enabled so
+// addresses can be allocated from it
+func CreateIPv4Range(c *ov.OVClient, r IPv4Range) (IPv4Range, error) {
+	r.Type, r.Enabled = "Range", true
+	var created IPv4Range
+	data, err := ovCall(c, rest.POST, ipv4RangesURI, r)
+	if err != nil {
+		return created, err
+	}
+	err = json.Unmarshal(data, &created)
+	return created, err
+}
+
+// GetIPv4Range - the range at uri
+func GetIPv4Range(c *ov.OVClient, uri string) (IPv4Range, error) {
+	var r IPv4Range
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(data, &r)
+	return r, err
+}
+
+// GetIPv4RangeByName - the named range, ErrIPv4RangeNotFound when there is
+// none
+func GetIPv4RangeByName(c *ov.OVClient, name string) (IPv4Range, error) {
+	uri, err := findURIByName(c, ipv4RangesURI, name)
+	if err != nil {
+		return IPv4Range{}, err
+	}
+	if uri == "" {
+		return IPv4Range{}, fmt.Errorf("%w: %s", ErrIPv4RangeNotFound, name)
+	}
+	return GetIPv4Range(c, uri)
+}
+
+// AllocateIPv4Addresses - take count free addresses from the range
+func AllocateIPv4Addresses(c *ov.OVClient, rangeURI string, count int) ([]string, error) {
+	data, err := ovCall(c, rest.PUT, rangeURI+"/allocator", map[string]interface{}{"count": count})
+	if err != nil {
+		return nil, err
+	}
+	var allocated struct {
+		IDList []string `json:"idList"`
+	}
+	if err := json.Unmarshal(data, &allocated); err != nil {
+		return nil, err
+	}
+	if len(allocated.IDList) < count {
+		return allocated.IDList, fmt.Errorf("%w: ipv4 range %s gave %d of %d addresses", ErrIdentityPoolExhausted, rangeURI, len(allocated.IDList), count)
+	}
+	return allocated.IDList, nil
+}
+
+// CollectIPv4Addresses - give addresses back to the range
+func CollectIPv4Addresses(c *ov.OVClient, rangeURI string, addresses []string) error {
+	_, err := ovCall(c, rest.PUT, rangeURI+"/collector", map[string]interface{}{"idList": addresses})
+	return err
+}
+
+// IPv4Allocation - the address a machine got from OneView IPAM, kept with
+// the machine so remove gives it back
+type IPv4Allocation struct {
+	RangeURI   string   `json:",omitempty"`
+	Address    string   `json:",omitempty"`
+	SubnetMask string   `json:",omitempty"`
+	Gateway    string   `json:",omitempty"`
+	DNSServers []string `json:",omitempty"`
+}
+
+// attributes - ICsp custom attributes for the build plans, ie; @ipv4_address@
+func (a IPv4Allocation) attributes() map[string]string {
+	if a.Address == "" {
+		return nil
+	}
+	return map[string]string{
+		"ipv4_address": a.Address,
+		"ipv4_netmask": a.SubnetMask,
+		"ipv4_gateway": a.Gateway,
+		"ipv4_dns":     strings.Join(a.DNSServers, ","),
+	}
+}
+
+// allocateIPv4 - take the machine address from the --oneview-ipv4-range
+// range, once
+func (d *Driver) allocateIPv4() error {
+	if d.IPv4Range == "" || d.IPv4.Address != "" {
+		return nil
+	}
+	r, err := GetIPv4RangeByName(d.ClientOV, d.IPv4Range)
+	if err != nil {
+		return err
+	}
+	subnet, err := GetIPv4Subnet(d.ClientOV, r.SubnetURI)
+	if err != nil {
+		return err
+	}
+	addresses, err := AllocateIPv4Addresses(d.ClientOV, r.URI, 1)
+	if err != nil {
+		return err
+	}
+	d.IPv4 = IPv4Allocation{
+		RangeURI:   r.URI,
+		Address:    addresses[0],
+		SubnetMask: subnet.SubnetMask,
+		Gateway:    subnet.Gateway,
+		DNSServers: subnet.DNSServers,
+	}
+	log.Infof("Allocated %s from ipv4 range %s for %s", d.IPv4.Address, d.IPv4Range, d.MachineName)
+	return nil
+}
+
+// releaseIPv4 - give the machine address back to its range, failures only
+// warn as the machine is gone either way
+func (d *Driver) releaseIPv4() {
+	if d.IPv4.Address == "" {
+		return
+	}
+	if err := CollectIPv4Addresses(d.ClientOV, d.IPv4.RangeURI, []string{d.IPv4.Address}); err != nil {
+		log.Warnf("Unable to return %s of %s to its ipv4 range : %s", d.IPv4.Address, d.MachineName, err)
+		return
+	}
+	d.IPv4 = IPv4Allocation{}
+}
@@ -0,0 +1,48 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPv4AllocationAttributes(t *testing.T) {
+	assert.Nil(t, IPv4Allocation{}.attributes())
+
+	a := IPv4Allocation{Address: "10.1.0.20", SubnetMask: "255.255.255.0", Gateway: "10.1.0.1", DNSServers: []string{"10.1.0.2", "10.1.0.3"}}
+	assert.Equal(t, map[string]string{
+		"ipv4_address": "10.1.0.20",
+		"ipv4_netmask": "255.255.255.0",
+		"ipv4_gateway": "10.1.0.1",
+		"ipv4_dns":     "10.1.0.2,10.1.0.3",
+	}, a.attributes())
+}
+
+func TestIPv4RangeBody(t *testing.T) {
+	r := IPv4Range{Type: "Range", Name: "docker", SubnetURI: "/rest/id-pools/ipv4/subnets/s1", Enabled: true,
+		StartStopFragments: []IPv4Fragment{{StartAddress: "10.1.0.20", EndAddress: "10.1.0.99"}}}
+	data, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "Range", "name": "docker", "subnetUri": "/rest/id-pools/ipv4/subnets/s1", "enabled": true,
+		"startStopFragments": [{"startAddress": "10.1.0.20", "endAddress": "10.1.0.99"}]}`, string(data))
+}
+
+func TestFailedCreateCollectsIPv4(t *testing.T) {
+	rangeURI := ipv4RangesURI + "/r1"
+	c, done := fakeOV(t,
+		fakeCall{method: "GET", uri: applianceVersionURI, err: errors.New("Response Status: 404 Not Found")},
+		fakeCall{method: "PUT", uri: rangeURI + "/collector", body: func(b interface{}) {
+			assert.Equal(t, map[string]interface{}{"idList": []string{"10.1.0.20"}}, b)
+		}})
+	defer done()
+	// an endpoint no other test has cached the appliance version of
+	c.Endpoint = "https://ipam-ov"
+	d := &Driver{BaseDriver: &drivers.BaseDriver{MachineName: "docker1"}, ClientOV: c,
+		IPv4: IPv4Allocation{RangeURI: rangeURI, Address: "10.1.0.20"}}
+	d.provider = &fakeProvider{fail: "plan"}
+	assert.EqualError(t, d.create(), "plan failed")
+	assert.Equal(t, IPv4Allocation{}, d.IPv4)
+}
@@ -0,0 +1,160 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// normalizeEndpoint - appliance endpoint as a base url, adding https:// when
+// there is no scheme and brackets around a bare ipv6 literal, ie; fd00::10
+// becomes https://[fd00::10]
+func normalizeEndpoint(endpoint string) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return "", nil
+	}
+	if ip := net.ParseIP(strings.Trim(endpoint, "[]")); ip != nil && ip.To4() == nil {
+		endpoint = "[" + ip.String() + "]"
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("Invalid endpoint %s : %w", endpoint, err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("Invalid endpoint %s, missing host", endpoint)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// IPv6Settings - ipv6 configuration for the machine os and how the driver
+// reaches the machine
+type IPv6Settings struct {
+	// Address - static address with prefix, ie; fd00::20/64, empty leaves the
+	// os to autoconfigure
+	Address string
+	// Gateway - static default gateway
+	Gateway string
+	// Prefer - connect to the machine over ipv6
+	Prefer bool
+}
+
+// newIPv6Settings - build the settings from flag values
+func newIPv6Settings(address, gateway string, prefer bool) (IPv6Settings, error) {
+	s := IPv6Settings{Address: strings.TrimSpace(address), Gateway: strings.TrimSpace(gateway), Prefer: prefer}
+	if s.Address != "" {
+		ip, _, err := net.ParseCIDR(s.Address)
+		if err != nil || ip.To4() != nil {
+			return s, fmt.Errorf("Invalid option --oneview-ipv6-address %q, must be an ipv6 address with prefix, ie; fd00::20/64", s.Address)
+		}
+	}
+	if s.Gateway != "" {
+		if ip := net.ParseIP(s.Gateway); ip == nil || ip.To4() != nil {
+			return s, fmt.Errorf("Invalid option --oneview-ipv6-gateway %q, must be an ipv6 address", s.Gateway)
+		}
+		if s.Address == "" {
+			return s, fmt.Errorf("Invalid option --oneview-ipv6-gateway, needs --oneview-ipv6-address")
+		}
+	}
+	return s, nil
+}
+
+// isSet - true when the os gets any ipv6 configuration
+func (s IPv6Settings) isSet() bool {
+	return s.Address != "" || s.Prefer
+}
+
+// staticIP - the static address without its prefix
+func (s IPv6Settings) staticIP() string {
+	ip, _, err := net.ParseCIDR(s.Address)
+	if err != nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// attributes - ICsp custom attributes for the build plans, ie; @ipv6_address@
+func (s IPv6Settings) attributes() map[string]string {
+	if !s.isSet() {
+		return nil
+	}
+	attrs := map[string]string{"ipv6_enable": "true", "ipv6_address": "", "ipv6_prefix": "", "ipv6_gateway": s.Gateway}
+	if ip, ipnet, err := net.ParseCIDR(s.Address); err == nil {
+		ones, _ := ipnet.Mask.Size()
+		attrs["ipv6_address"] = ip.String()
+		attrs["ipv6_prefix"] = fmt.Sprint(ones)
+	}
+	return attrs
+}
+
+// icspInterface - a nic of an ICsp server
+type icspInterface struct {
+	MACAddr  string `json:"macAddr"`
+	IPv4Addr string `json:"ipv4Addr"`
+	IPv6Addr string `json:"ipv6Addr"`
+}
+
+// globalIPv6 - first global unicast ipv6 address in s, which may hold several
+// separated by commas or spaces
+func globalIPv6(s string) string {
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		ip := net.ParseIP(strings.Split(f, "/")[0])
+		if ip != nil && ip.To4() == nil && ip.IsGlobalUnicast() {
+			return ip.String()
+		}
+	}
+	return ""
+}
+
+// pickIPv6 - the global ipv6 address of the interface with mac, or of the
+// first interface that has one when mac is empty
+func pickIPv6(interfaces []icspInterface, mac string) string {
+	for _, i := range interfaces {
+		if mac != "" && !strings.EqualFold(i.MACAddr, mac) {
+			continue
+		}
+		if ip := globalIPv6(i.IPv6Addr); ip != "" {
+			return ip
+		}
+	}
+	return ""
+}
+
+// icspServerIPv6 - ipv6 address ICsp discovered for the server
+func icspServerIPv6(c *icsp.ICSPClient, mid, mac string) (string, error) {
+	data, err := icspCall(c, rest.GET, icspServersURI+"/"+mid, nil)
+	if err != nil {
+		return "", err
+	}
+	var server struct {
+		Interfaces []icspInterface `json:"interfaces"`
+	}
+	if err := json.Unmarshal(data, &server); err != nil {
+		return "", err
+	}
+	return pickIPv6(server.Interfaces, mac), nil
+}
+
+// getIPv6 - the machine ipv6 address, the static one when configured
+func (d *Driver) getIPv6() (string, error) {
+	if ip := d.IPv6.staticIP(); ip != "" {
+		return ip, nil
+	}
+	mac := ""
+	if d.PublicConnectionName != "" {
+		conn, err := d.Profile.GetConnectionByName(d.PublicConnectionName)
+		if err != nil {
+			return "", err
+		}
+		mac = conn.MAC.String()
+	}
+	return icspServerIPv6(d.ClientICSP, d.Server.MID, mac)
+}
@@ -0,0 +1,51 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://ov.example.com/": "https://ov.example.com",
+		"ov.example.com":          "https://ov.example.com",
+		"fd00::10":                "https://[fd00::10]",
+		"[fd00::10]":              "https://[fd00::10]",
+		"https://[fd00::10]:8443": "https://[fd00::10]:8443",
+		"10.0.0.5":                "https://10.0.0.5",
+		"":                        "",
+	} {
+		got, err := normalizeEndpoint(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := normalizeEndpoint("https://")
+	assert.Error(t, err)
+}
+
+func TestIPv6Settings(t *testing.T) {
+	s, err := newIPv6Settings("fd00::20/64", "fd00::1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "fd00::20", s.staticIP())
+	assert.Equal(t, map[string]string{"ipv6_enable": "true", "ipv6_address": "fd00::20", "ipv6_prefix": "64", "ipv6_gateway": "fd00::1"}, s.attributes())
+
+	_, err = newIPv6Settings("10.0.0.5/24", "", false)
+	assert.Error(t, err)
+	_, err = newIPv6Settings("", "fd00::1", true)
+	assert.Error(t, err)
+
+	s, _ = newIPv6Settings("", "", false)
+	assert.Nil(t, s.attributes())
+}
+
+func TestPickIPv6(t *testing.T) {
+	interfaces := []icspInterface{
+		{MACAddr: "AA:00:00:00:00:01", IPv6Addr: "fe80::1"},
+		{MACAddr: "AA:00:00:00:00:02", IPv6Addr: "fe80::2, 2001:db8::2/64"},
+		{MACAddr: "AA:00:00:00:00:03", IPv6Addr: "2001:db8::3"},
+	}
+	assert.Equal(t, "2001:db8::2", pickIPv6(interfaces, ""))
+	assert.Equal(t, "2001:db8::3", pickIPv6(interfaces, "aa:00:00:00:00:03"))
+	assert.Equal(t, "", pickIPv6(interfaces, "aa:00:00:00:00:01"))
+}
@@ -0,0 +1,135 @@
+package oneview
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// profilePatchAPIVersion - first api version taking json patch updates of
+// server profiles
+const profilePatchAPIVersion = 800
+
+// json patch operations
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+)
+
+// PatchOp - one json patch (rfc 6902) operation
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON - remove takes no value, every other op needs one even when
+// it is null
+func (o PatchOp) MarshalJSON() ([]byte, error) {
+	if o.Op == PatchRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type op PatchOp
+	return json.Marshal(op(o))
+}
+
+// DiffPatch - the json patch turning before into after.  Objects are
+// compared attribute by attribute, lists of the same length element by
+// element, other lists are replaced whole.
+func DiffPatch(before, after map[string]interface{}) []PatchOp {
+	return diffPatch(nil, "", before, after)
+}
+
+func diffPatch(ops []PatchOp, path string, before, after interface{}) []PatchOp {
+	switch a := after.(type) {
+	case map[string]interface{}:
+		b, ok := before.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(a)+len(b))
+		for k := range b {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := b[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := path + "/" + escapePointer(k)
+			bv, inBefore := b[k]
+			av, inAfter := a[k]
+			switch {
+			case !inAfter:
+				ops = append(ops, PatchOp{Op: PatchRemove, Path: p})
+			case !inBefore:
+				ops = append(ops, PatchOp{Op: PatchAdd, Path: p, Value: av})
+			default:
+				ops = diffPatch(ops, p, bv, av)
+			}
+		}
+		return ops
+	case []interface{}:
+		b, ok := before.([]interface{})
+		if !ok || len(a) != len(b) {
+			break
+		}
+		for i := range a {
+			ops = diffPatch(ops, path+"/"+strconv.Itoa(i), b[i], a[i])
+		}
+		return ops
+	}
+	if reflect.DeepEqual(before, after) {
+		return ops
+	}
+	// a number read with decodeJSON equals the same number set as a float
+	if _, ok := jsonFloat(before); ok {
+		if _, ok := jsonFloat(after); ok && compareNumbers(before, after) == 0 {
+			return ops
+		}
+	}
+	return append(ops, PatchOp{Op: PatchReplace, Path: path, Value: after})
+}
+
+// escapePointer - escape a key for a json pointer
+func escapePointer(k string) string {
+	return strings.Replace(strings.Replace(k, "~", "~0", -1), "/", "~1", -1)
+}
+
+// copyResourceMap - deep copy of a raw resource, to diff against later
+func copyResourceMap(resource map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(resource)
+	var out map[string]interface{}
+	decodeJSON(data, &out)
+	return out
+}
+
+// patchResource - send only what changed between before and after, as a
+// json patch.  Appliances before profilePatchAPIVersion, or ones refusing
+// the patch, get a full put of after instead.
+func patchResource(c *ov.OVClient, uri string, before, after map[string]interface{}) error {
+	ops := DiffPatch(before, after)
+	if len(ops) == 0 {
+		return nil
+	}
+	if c.APIVersion >= profilePatchAPIVersion {
+		data, err := ovCall(c, rest.PATCH, uri, ops)
+		if err == nil {
+			return waitForTaskResponse(c, data)
+		}
+		log.Debugf("patch of %s refused, updating it whole : %s", uri, err)
+	}
+	return putResourceMap(c, uri, after)
+}
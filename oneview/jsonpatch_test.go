@@ -0,0 +1,44 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPatch(t *testing.T) {
+	var before, after map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"name": "docker-1",
+		"eTag": "1",
+		"bios": {"manageBios": false},
+		"connections": [{"id": 1, "portId": "Flb 1:1-a"}, {"id": 2, "portId": "Flb 1:2-a"}],
+		"boot": {"order": ["PXE", "HardDisk"]},
+		"a/b": 1
+	}`), &before))
+	after = copyResourceMap(before)
+	after["bios"].(map[string]interface{})["manageBios"] = true
+	after["bios"].(map[string]interface{})["overriddenSettings"] = []interface{}{}
+	after["connections"].([]interface{})[1].(map[string]interface{})["portId"] = "Flb 1:2-b"
+	after["boot"].(map[string]interface{})["order"] = []interface{}{"HardDisk"}
+	delete(after, "a/b")
+
+	assert.Equal(t, []PatchOp{
+		{Op: PatchRemove, Path: "/a~1b"},
+		{Op: PatchReplace, Path: "/bios/manageBios", Value: true},
+		{Op: PatchAdd, Path: "/bios/overriddenSettings", Value: []interface{}{}},
+		{Op: PatchReplace, Path: "/boot/order", Value: []interface{}{"HardDisk"}},
+		{Op: PatchReplace, Path: "/connections/1/portId", Value: "Flb 1:2-b"},
+	}, DiffPatch(before, after))
+	assert.Empty(t, DiffPatch(before, copyResourceMap(before)))
+}
+
+func TestPatchOpJSON(t *testing.T) {
+	data, err := json.Marshal([]PatchOp{
+		{Op: PatchRemove, Path: "/a"},
+		{Op: PatchReplace, Path: "/b", Value: nil},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":null}]`, string(data))
+}
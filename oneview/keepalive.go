@@ -0,0 +1,101 @@
+package oneview
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// defaultKeepAlive - ping interval when the appliance idle timeout can not
+// be read, inside the 24 hour default with plenty to spare
+const defaultKeepAlive = 5 * time.Minute
+
+// keepAlive - the pinger of one client, shared by the drivers using it
+type keepAlive struct {
+	refs int
+	stop chan struct{}
+}
+
+var (
+	keepAlivesMu sync.Mutex
+	keepAlives   = map[*ov.OVClient]*keepAlive{}
+)
+
+// KeepAlive - ping the appliance every interval so the session of c does
+// not expire while nothing else uses it, ie; during an os build.  Drivers
+// sharing a client share one pinger, it stops once every caller has called
+// the returned stop.
+func KeepAlive(c *ov.OVClient, interval time.Duration) (stop func()) {
+	if c == nil || interval <= 0 {
+		return func() {}
+	}
+	keepAlivesMu.Lock()
+	defer keepAlivesMu.Unlock()
+	k, ok := keepAlives[c]
+	if !ok {
+		k = &keepAlive{stop: make(chan struct{})}
+		keepAlives[c] = k
+		go pingUntil(c, interval, k.stop)
+	}
+	k.refs++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			keepAlivesMu.Lock()
+			defer keepAlivesMu.Unlock()
+			if k.refs--; k.refs == 0 {
+				close(k.stop)
+				delete(keepAlives, c)
+			}
+		})
+	}
+}
+
+// pingUntil - read a small authenticated resource every interval, a failed
+// ping is only logged as the next real call logs in again anyway
+func pingUntil(c *ov.OVClient, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := ovCall(c, rest.GET, sessionIdleTimeoutURI, nil); err != nil {
+				log.Debugf("keep-alive ping of %s failed : %s", c.Endpoint, err)
+			}
+		}
+	}
+}
+
+// keepAliveInterval - the ping interval for --oneview-keepalive, seconds
+// when above 0, off when below and from the appliance idle timeout at 0
+func keepAliveInterval(seconds int, session func() (SessionSettings, error)) time.Duration {
+	switch {
+	case seconds > 0:
+		return time.Duration(seconds) * time.Second
+	case seconds < 0:
+		return 0
+	}
+	s, err := session()
+	if err != nil {
+		log.Debugf("unable to read the session idle timeout, pinging every %s : %s", defaultKeepAlive, err)
+		return defaultKeepAlive
+	}
+	if interval := s.RefreshInterval(); interval > 0 {
+		return interval
+	}
+	return defaultKeepAlive
+}
+
+// keepAlive - keep the OneView session of the driver while it waits on
+// something else, call the returned func when done
+func (d *Driver) keepAlive() func() {
+	interval := keepAliveInterval(d.KeepAlive, func() (SessionSettings, error) {
+		return GetSessionSettings(d.ClientOV)
+	})
+	return KeepAlive(d.ClientOV, interval)
+}
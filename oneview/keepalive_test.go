@@ -0,0 +1,35 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepAliveInterval(t *testing.T) {
+	session := func(d time.Duration, err error) func() (SessionSettings, error) {
+		return func() (SessionSettings, error) { return SessionSettings{IdleTimeout: d}, err }
+	}
+	assert.Equal(t, 90*time.Second, keepAliveInterval(90, session(0, nil)))
+	assert.Equal(t, time.Duration(0), keepAliveInterval(-1, session(0, nil)))
+	assert.Equal(t, 10*time.Minute, keepAliveInterval(0, session(20*time.Minute, nil)))
+	assert.Equal(t, defaultKeepAlive, keepAliveInterval(0, session(0, nil)))
+	assert.Equal(t, defaultKeepAlive, keepAliveInterval(0, session(0, errors.New("401"))))
+}
+
+func TestKeepAliveShared(t *testing.T) {
+	c := &ov.OVClient{}
+	stop1 := KeepAlive(c, time.Hour)
+	stop2 := KeepAlive(c, time.Hour)
+	assert.Equal(t, 2, keepAlives[c].refs)
+	stop1()
+	stop1()
+	assert.Equal(t, 1, keepAlives[c].refs)
+	stop2()
+	_, ok := keepAlives[c]
+	assert.False(t, ok)
+	KeepAlive(nil, time.Hour)()
+}
@@ -0,0 +1,238 @@
+package oneview
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// views the appliance can return list members in
+const (
+	// ViewExpand - members carry their associated resources
+	ViewExpand = "expand"
+)
+
+// sort orders, used as attribute:order in ListOptions.Sort
+const (
+	SortAscending  = "ascending"
+	SortDescending = "descending"
+	// defaultSort - lists are in name order unless asked otherwise
+	defaultSort = "name:" + SortAscending
+)
+
+// fieldsAPIVersion - first api version taking a fields query parameter to
+// return only some attributes of each member
+const fieldsAPIVersion = 500
+
+// InventoryFields - the profile attributes an inventory scan needs
+var InventoryFields = []string{"name", "serialNumber", "status", "state", "serverHardwareUri"}
+
+// ListOptions - query options for list calls
+type ListOptions struct {
+	// Filters - appliance filter expressions, all must match
+	Filters []string
+	// Sort - ie; name:ascending, defaults to name order.  Members are sorted
+	// again once every page is in, ties go by uri, so the same inventory
+	// always lists the same way whatever order the pages came in.
+	Sort string
+	// View - ie; expand, not every resource supports every view
+	View string
+	// Fields - only return these attributes of each member, the uri is
+	// always kept.  Appliances that can not project are asked for whole
+	// members, which are cut down once they are in.
+	Fields []string
+	// Scope - uri of a scope, only its members are listed
+	Scope string
+}
+
+// query - the options as a rest client query string
+func (o ListOptions) query() map[string]interface{} {
+	q := map[string]interface{}{}
+	if len(o.Filters) > 0 {
+		q["filter"] = o.Filters
+	}
+	if o.Sort != "" {
+		q["sort"] = o.Sort
+	}
+	if o.View != "" {
+		q["view"] = o.View
+	}
+	if o.Scope != "" {
+		q["scopeUris"] = o.Scope
+	}
+	return q
+}
+
+func (o ListOptions) sort() string {
+	if o.Sort == "" {
+		return defaultSort
+	}
+	return o.Sort
+}
+
+// orderBy - the attribute to sort on and if the order is descending
+func (o ListOptions) orderBy() (string, bool) {
+	s := o.sort()
+	attr, order := s, SortAscending
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		attr, order = s[:i], strings.ToLower(s[i+1:])
+	}
+	return attr, order == SortDescending || order == "desc"
+}
+
+// listOrdered - list every member of a collection, dropping members that
+// show up on more than one page and sorting them by opts.Sort.  add is
+// called once with all the members as a json array.
+func listOrdered(c *ov.OVClient, uri string, opts ListOptions, add func(members json.RawMessage) error) error {
+	if opts.Scope != "" && c.APIVersion < scopesAPIVersion {
+		return ErrScopesUnsupported
+	}
+	query := opts.query()
+	var fields []string
+	if len(opts.Fields) > 0 {
+		fields = opts.fields()
+		if c.APIVersion >= fieldsAPIVersion {
+			query["fields"] = strings.Join(fields, ",")
+		}
+	}
+	var members []json.RawMessage
+	err := listMembers(c, uri, query, func(page json.RawMessage) error {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(page, &raw); err != nil {
+			return err
+		}
+		for _, m := range raw {
+			m, err := projectMember(m, fields)
+			if err != nil {
+				return err
+			}
+			members = append(members, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	members, err = orderMembers(members, opts)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	return add(data)
+}
+
+// fields - the attributes to ask for, the uri and sort attribute are
+// needed to put the members in order
+func (o ListOptions) fields() []string {
+	attr, _ := o.orderBy()
+	fields := append([]string{}, o.Fields...)
+	for _, f := range []string{"uri", attr} {
+		found := false
+		for _, have := range fields {
+			found = found || have == f
+		}
+		if !found {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// projectMember - only the named attributes of a member, so a large list
+// does not hold every member whole.  No fields keeps it whole.
+func projectMember(member json.RawMessage, fields []string) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return member, nil
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(member, &all); err != nil {
+		return nil, err
+	}
+	kept := map[string]json.RawMessage{}
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			kept[f] = v
+		}
+	}
+	return json.Marshal(kept)
+}
+
+// orderMembers - members without duplicate uris, sorted by opts.Sort
+func orderMembers(members []json.RawMessage, opts ListOptions) ([]json.RawMessage, error) {
+	attr, desc := opts.orderBy()
+	sorter := membersByKey{desc: desc}
+	seen := map[string]bool{}
+	for _, m := range members {
+		var fields map[string]interface{}
+		if err := decodeJSON(m, &fields); err != nil {
+			return nil, err
+		}
+		uri, _ := fields["uri"].(string)
+		if uri != "" {
+			if seen[uri] {
+				continue
+			}
+			seen[uri] = true
+		}
+		sorter.members = append(sorter.members, m)
+		sorter.keys = append(sorter.keys, fields[attr])
+		sorter.uris = append(sorter.uris, uri)
+	}
+	sort.Stable(sorter)
+	return sorter.members, nil
+}
+
+// membersByKey - raw members sorted on one attribute, then uri
+type membersByKey struct {
+	members []json.RawMessage
+	keys    []interface{}
+	uris    []string
+	desc    bool
+}
+
+func (m membersByKey) Len() int { return len(m.members) }
+func (m membersByKey) Swap(i, j int) {
+	m.members[i], m.members[j] = m.members[j], m.members[i]
+	m.keys[i], m.keys[j] = m.keys[j], m.keys[i]
+	m.uris[i], m.uris[j] = m.uris[j], m.uris[i]
+}
+func (m membersByKey) Less(i, j int) bool {
+	if c := compareValues(m.keys[i], m.keys[j]); c != 0 {
+		if m.desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	return m.uris[i] < m.uris[j]
+}
+
+// compareValues - order json values, missing values first, then numbers,
+// then anything else by its text
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	_, aNum := jsonFloat(a)
+	_, bNum := jsonFloat(b)
+	switch {
+	case aNum && bNum:
+		return compareNumbers(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	sa, _ := json.Marshal(a)
+	sb, _ := json.Marshal(b)
+	return strings.Compare(string(sa), string(sb))
+}
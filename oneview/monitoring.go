@@ -0,0 +1,234 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	trapDestinationsURI = "/rest/appliance/trap-destinations"
+	remoteSyslogURI     = "/rest/remote-syslog"
+)
+
+// snmp trap formats
+const (
+	TrapFormatSNMPv1 = "SNMPv1"
+	TrapFormatSNMPv2 = "SNMPv2"
+)
+
+// TrapDestination - where the appliance sends snmp traps
+type TrapDestination struct {
+	URI             string `json:"uri,omitempty"`
+	Destination     string `json:"destination"`
+	CommunityString string `json:"communityString,omitempty"`
+	TrapFormat      string `json:"trapFormat,omitempty"`
+	Port            int    `json:"port,omitempty"`
+}
+
+// Validate - check the destination before sending it to the appliance
+func (t TrapDestination) Validate() error {
+	if strings.TrimSpace(t.Destination) == "" {
+		return fmt.Errorf("trap destination needs a host name or address")
+	}
+	switch t.TrapFormat {
+	case "", TrapFormatSNMPv1, TrapFormatSNMPv2:
+	default:
+		return fmt.Errorf("trap format %s must be %s or %s", t.TrapFormat, TrapFormatSNMPv1, TrapFormatSNMPv2)
+	}
+	if t.Port < 0 || t.Port > 65535 {
+		return fmt.Errorf("trap port %d is out of range", t.Port)
+	}
+	return nil
+}
+
+// ListTrapDestinations - the configured trap destinations
+func ListTrapDestinations(c *ov.OVClient) ([]TrapDestination, error) {
+	var list []TrapDestination
+	err := listOrdered(c, trapDestinationsURI, ListOptions{Sort: "uri:" + SortAscending}, func(members json.RawMessage) error {
+		var page []TrapDestination
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// nextTrapDestinationID - trap destinations are created at an id the client
+// picks, use one past the highest in use
+func nextTrapDestinationID(existing []TrapDestination) int {
+	next := 1
+	for _, t := range existing {
+		if id, err := strconv.Atoi(path.Base(t.URI)); err == nil && id >= next {
+			next = id + 1
+		}
+	}
+	return next
+}
+
+// EnsureTrapDestination - add the trap destination unless one for the same
+// destination exists, returns the destination as the appliance has it
+func EnsureTrapDestination(c *ov.OVClient, t TrapDestination) (TrapDestination, error) {
+	if err := t.Validate(); err != nil {
+		return t, err
+	}
+	if t.TrapFormat == "" {
+		t.TrapFormat = TrapFormatSNMPv1
+	}
+	existing, err := ListTrapDestinations(c)
+	if err != nil {
+		return t, err
+	}
+	for _, e := range existing {
+		if strings.EqualFold(e.Destination, t.Destination) {
+			log.Debugf("trap destination %s already configured at %s", t.Destination, e.URI)
+			return e, nil
+		}
+	}
+	uri := fmt.Sprintf("%s/%d", trapDestinationsURI, nextTrapDestinationID(existing))
+	data, err := ovCall(c, rest.POST, uri, t)
+	if err != nil {
+		return t, err
+	}
+	var created TrapDestination
+	if err := json.Unmarshal(data, &created); err != nil || created.URI == "" {
+		created = t
+		created.URI = uri
+	}
+	return created, nil
+}
+
+// DeleteTrapDestination - remove a trap destination
+func DeleteTrapDestination(c *ov.OVClient, uri string) error {
+	return deleteResource(c, uri)
+}
+
+// RemoteSyslog - forwarding of appliance and managed hardware logs
+type RemoteSyslog struct {
+	Destination string `json:"remoteSyslogDestination"`
+	Port        string `json:"remoteSyslogPort,omitempty"`
+	Enabled     bool   `json:"enableRemoteSyslog"`
+	// SendTestLog - ask the appliance to send a test message when saving
+	SendTestLog bool `json:"sendTestLog,omitempty"`
+}
+
+// Validate - check the settings before sending them to the appliance
+func (r RemoteSyslog) Validate() error {
+	if r.Enabled && strings.TrimSpace(r.Destination) == "" {
+		return fmt.Errorf("remote syslog needs a destination when enabled")
+	}
+	if r.Destination != "" && net.ParseIP(r.Destination) == nil && strings.ContainsAny(r.Destination, " /:") {
+		return fmt.Errorf("remote syslog destination %q is not a host name or address", r.Destination)
+	}
+	if r.Port != "" {
+		if p, err := strconv.Atoi(r.Port); err != nil || p <= 0 || p > 65535 {
+			return fmt.Errorf("remote syslog port %q is out of range", r.Port)
+		}
+	}
+	return nil
+}
+
+// GetRemoteSyslog - current remote syslog settings
+func GetRemoteSyslog(c *ov.OVClient) (RemoteSyslog, error) {
+	var r RemoteSyslog
+	data, err := ovCall(c, rest.GET, remoteSyslogURI, nil)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(data, &r)
+	return r, err
+}
+
+// SetRemoteSyslog - save remote syslog settings and wait for the appliance
+// to push them to the managed hardware
+func SetRemoteSyslog(c *ov.OVClient, r RemoteSyslog) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	data, err := ovCall(c, rest.PUT, remoteSyslogURI, r)
+	if err != nil {
+		return err
+	}
+	var t Task
+	if err := json.Unmarshal(data, &t); err == nil && isTaskType(t.Type) {
+		return waitForTask(c, t.URI)
+	}
+	return nil
+}
+
+// MonitoringCheck - the outcome of validating one monitoring destination
+type MonitoringCheck struct {
+	Destination string `json:"destination"`
+	Kind        string `json:"kind"`
+	Error       string `json:"error,omitempty"`
+}
+
+// OK - true when the appliance accepted the destination
+func (m MonitoringCheck) OK() bool {
+	return m.Error == ""
+}
+
+// ValidateTrapDestination - have the appliance check it can reach a trap
+// destination, the appliance has no call that sends a test trap
+func ValidateTrapDestination(c *ov.OVClient, t TrapDestination) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	_, err := ovCall(c, rest.POST, trapDestinationsURI+"/validation", t)
+	return err
+}
+
+// SendTestSyslog - have the appliance send a test message to the configured
+// remote syslog destination, fails when remote syslog is not enabled
+func SendTestSyslog(c *ov.OVClient) error {
+	r, err := GetRemoteSyslog(c)
+	if err != nil {
+		return err
+	}
+	if !r.Enabled {
+		return fmt.Errorf("remote syslog is not enabled, there is nowhere to send a test message")
+	}
+	r.SendTestLog = true
+	return SetRemoteSyslog(c, r)
+}
+
+// CheckMonitoringDestinations - have the appliance validate every trap
+// destination, no test trap is sent as the appliance has no call for it, and
+// send a test syslog message, so bootstrap automation can check the
+// monitoring configuration.  A failing destination does not stop the others
+// being checked.
+func CheckMonitoringDestinations(c *ov.OVClient) ([]MonitoringCheck, error) {
+	traps, err := ListTrapDestinations(c)
+	if err != nil {
+		return nil, err
+	}
+	var checks []MonitoringCheck
+	for _, t := range traps {
+		check := MonitoringCheck{Destination: t.Destination, Kind: "snmp-trap"}
+		if err := ValidateTrapDestination(c, t); err != nil {
+			check.Error = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	r, err := GetRemoteSyslog(c)
+	if err != nil {
+		return checks, err
+	}
+	if r.Enabled {
+		check := MonitoringCheck{Destination: r.Destination, Kind: "syslog"}
+		if err := SendTestSyslog(c); err != nil {
+			check.Error = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
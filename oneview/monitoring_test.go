@@ -0,0 +1,31 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextTrapDestinationID(t *testing.T) {
+	assert.Equal(t, 1, nextTrapDestinationID(nil))
+	assert.Equal(t, 8, nextTrapDestinationID([]TrapDestination{
+		{URI: "/rest/appliance/trap-destinations/2"},
+		{URI: "/rest/appliance/trap-destinations/7"},
+	}))
+}
+
+func TestMonitoringValidate(t *testing.T) {
+	assert.NoError(t, TrapDestination{Destination: "10.0.0.5", TrapFormat: TrapFormatSNMPv2}.Validate())
+	assert.Error(t, TrapDestination{}.Validate())
+	assert.Error(t, TrapDestination{Destination: "nms", TrapFormat: "SNMPv3"}.Validate())
+
+	assert.NoError(t, RemoteSyslog{Destination: "syslog.example.com", Port: "514", Enabled: true}.Validate())
+	assert.NoError(t, RemoteSyslog{Destination: "fd00::5", Enabled: true}.Validate())
+	assert.Error(t, RemoteSyslog{Enabled: true}.Validate())
+	assert.Error(t, RemoteSyslog{Destination: "syslog", Port: "70000"}.Validate())
+}
+
+func TestMonitoringCheck(t *testing.T) {
+	assert.True(t, MonitoringCheck{Destination: "nms", Kind: "snmp-trap"}.OK())
+	assert.False(t, MonitoringCheck{Destination: "nms", Kind: "snmp-trap", Error: "unreachable"}.OK())
+}
@@ -0,0 +1,171 @@
+package oneview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// osNICsCommand - name and mac of every network interface the os sees
+const osNICsCommand = `for i in /sys/class/net/*; do [ -e "$i/device" ] && echo "$(basename "$i") $(cat "$i/address")"; done`
+
+// OSNIC - a physical network interface as the os names it
+type OSNIC struct {
+	Name string
+	MAC  string
+}
+
+// ProfileNIC - an ethernet connection of the profile
+type ProfileNIC struct {
+	ID   int
+	Name string
+	MAC  string
+}
+
+// NICReport - how the interfaces the os sees line up with the profile
+type NICReport struct {
+	// Missing - profile connections the os does not see
+	Missing []ProfileNIC
+	// Unexpected - os interfaces no profile connection has the mac of, ie;
+	// unused flexnics that are not hidden
+	Unexpected []OSNIC
+	// Order - os interfaces, in name order, whose connection is not the one
+	// at the same position in connection id order
+	Order []string
+}
+
+// OK - true when the os sees exactly the profile connections in order
+func (r NICReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Unexpected) == 0 && len(r.Order) == 0
+}
+
+// Problems - one line for each thing that does not match
+func (r NICReport) Problems() []string {
+	var p []string
+	for _, n := range r.Missing {
+		p = append(p, fmt.Sprintf("connection %d %s (%s) is not seen by the os", n.ID, n.Name, n.MAC))
+	}
+	for _, n := range r.Unexpected {
+		p = append(p, fmt.Sprintf("os interface %s (%s) is not a profile connection, check --oneview-hide-unused-flexnics", n.Name, n.MAC))
+	}
+	p = append(p, r.Order...)
+	return p
+}
+
+// parseOSNICs - the output of osNICsCommand
+func parseOSNICs(out string) []OSNIC {
+	var nics []OSNIC
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.Fields(line)
+		if len(f) != 2 {
+			continue
+		}
+		nics = append(nics, OSNIC{Name: f[0], MAC: strings.ToLower(f[1])})
+	}
+	return nics
+}
+
+// profileNICs - the ethernet connections of a raw profile, in id order
+func profileNICs(profile map[string]interface{}) []ProfileNIC {
+	var nics []ProfileNIC
+	for _, conn := range profileConnections(profile) {
+		if ft, _ := conn["functionType"].(string); ft != "" && ft != "Ethernet" {
+			continue
+		}
+		mac, _ := conn["mac"].(string)
+		if mac == "" {
+			continue
+		}
+		id, _ := jsonInt(conn["id"])
+		name, _ := conn["name"].(string)
+		nics = append(nics, ProfileNIC{ID: int(id), Name: name, MAC: strings.ToLower(mac)})
+	}
+	sort.Sort(profileNICsByID(nics))
+	return nics
+}
+
+type profileNICsByID []ProfileNIC
+
+func (n profileNICsByID) Len() int           { return len(n) }
+func (n profileNICsByID) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n profileNICsByID) Less(i, j int) bool { return n[i].ID < n[j].ID }
+
+// osNICsByName - interface names sort as the os enumerated them, eth2
+// before eth10
+type osNICsByName []OSNIC
+
+func (n osNICsByName) Len() int      { return len(n) }
+func (n osNICsByName) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+func (n osNICsByName) Less(i, j int) bool {
+	if len(n[i].Name) != len(n[j].Name) {
+		return len(n[i].Name) < len(n[j].Name)
+	}
+	return n[i].Name < n[j].Name
+}
+
+// CheckNICs - compare the os interfaces with the profile connections
+func CheckNICs(osNICs []OSNIC, connections []ProfileNIC) NICReport {
+	var r NICReport
+	seen := map[string]bool{}
+	for _, n := range osNICs {
+		seen[n.MAC] = true
+	}
+	inProfile := map[string]ProfileNIC{}
+	for _, c := range connections {
+		inProfile[c.MAC] = c
+		if !seen[c.MAC] {
+			r.Missing = append(r.Missing, c)
+		}
+	}
+	sorted := append([]OSNIC(nil), osNICs...)
+	sort.Sort(osNICsByName(sorted))
+	var matched []OSNIC
+	for _, n := range sorted {
+		if _, ok := inProfile[n.MAC]; ok {
+			matched = append(matched, n)
+		} else {
+			r.Unexpected = append(r.Unexpected, n)
+		}
+	}
+	// order only means something when every connection is there
+	if len(r.Missing) > 0 {
+		return r
+	}
+	for i, n := range matched {
+		if c := inProfile[n.MAC]; c.ID != connections[i].ID {
+			r.Order = append(r.Order, fmt.Sprintf("os interface %s is connection %d %s, expected connection %d %s",
+				n.Name, c.ID, c.Name, connections[i].ID, connections[i].Name))
+		}
+	}
+	return r
+}
+
+// checkNICs - after provisioning, warn about os interfaces that do not
+// line up with the profile connections
+func (d *Driver) checkNICs() {
+	profile, err := getResourceMap(d.ClientOV, d.Profile.URI.String())
+	if err != nil {
+		log.Warnf("Unable to read the profile connections of %s to check the os nics : %s", d.MachineName, err)
+		return
+	}
+	sshClient, err := d.getLocalSSHClient()
+	if err != nil {
+		log.Warnf("Unable to check the os nics of %s : %s", d.MachineName, err)
+		return
+	}
+	out, err := sshClient.Output(osNICsCommand)
+	if err != nil {
+		log.Warnf("Unable to list the os nics of %s : %s", d.MachineName, err)
+		return
+	}
+	report := CheckNICs(parseOSNICs(out), profileNICs(profile))
+	if report.OK() {
+		log.Debugf("os nics of %s match the profile connections", d.MachineName)
+		return
+	}
+	for _, p := range report.Problems() {
+		log.Warnf("%s: %s", d.MachineName, p)
+	}
+}
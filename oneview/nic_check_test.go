@@ -0,0 +1,39 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOSNICs(t *testing.T) {
+	assert.Equal(t, []OSNIC{{"eth0", "aa:bb:cc:00:00:01"}, {"eth1", "aa:bb:cc:00:00:02"}},
+		parseOSNICs("eth0 AA:BB:CC:00:00:01\neth1 aa:bb:cc:00:00:02\n\n"))
+}
+
+func TestProfileNICs(t *testing.T) {
+	profile := map[string]interface{}{"connections": []interface{}{
+		map[string]interface{}{"id": 2.0, "name": "data", "functionType": "Ethernet", "mac": "AA:BB:CC:00:00:02"},
+		map[string]interface{}{"id": 3.0, "name": "san", "functionType": "FibreChannel", "mac": "AA:BB:CC:00:00:03"},
+		map[string]interface{}{"id": 1.0, "name": "public", "functionType": "Ethernet", "mac": "AA:BB:CC:00:00:01"},
+	}}
+	assert.Equal(t, []ProfileNIC{{1, "public", "aa:bb:cc:00:00:01"}, {2, "data", "aa:bb:cc:00:00:02"}}, profileNICs(profile))
+}
+
+func TestCheckNICs(t *testing.T) {
+	conns := []ProfileNIC{{1, "public", "aa:00:00:00:00:01"}, {2, "data", "aa:00:00:00:00:02"}}
+
+	r := CheckNICs([]OSNIC{{"eth1", "aa:00:00:00:00:02"}, {"eth0", "aa:00:00:00:00:01"}}, conns)
+	assert.True(t, r.OK())
+
+	r = CheckNICs([]OSNIC{{"eth0", "aa:00:00:00:00:02"}, {"eth1", "aa:00:00:00:00:01"}, {"eth2", "aa:00:00:00:00:09"}}, conns)
+	assert.False(t, r.OK())
+	assert.Equal(t, []OSNIC{{"eth2", "aa:00:00:00:00:09"}}, r.Unexpected)
+	assert.Equal(t, []string{
+		"os interface eth0 is connection 2 data, expected connection 1 public",
+		"os interface eth1 is connection 1 public, expected connection 2 data",
+	}, r.Order)
+
+	r = CheckNICs([]OSNIC{{"eth0", "aa:00:00:00:00:01"}}, conns)
+	assert.Equal(t, []string{"connection 2 data (aa:00:00:00:00:02) is not seen by the os"}, r.Problems())
+}
@@ -0,0 +1,95 @@
+package oneview
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// decodeJSON - json.Unmarshal keeping numbers as json.Number.  Raw
+// resources are put back to the appliance, and a 64 bit value, ie; a
+// capacity in bytes or a generated id, decoded as float64 comes back rounded
+// once it is above 2^53.  Read numbers with jsonInt or jsonFloat.
+func decodeJSON(data []byte, out interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(out); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level json value")
+	}
+	return nil
+}
+
+// jsonInt - an integer attribute of a raw resource, decoded with decodeJSON
+// or json.Unmarshal, false when it is not a number
+func jsonInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i, true
+		}
+		f, err := n.Float64()
+		return int64(f), err == nil
+	case float64:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	}
+	return 0, false
+}
+
+// jsonFloat - a number attribute of a raw resource, false when it is not a
+// number
+func jsonFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// compareNumbers - order two numbers, exactly when both are integers
+func compareNumbers(a, b interface{}) int {
+	ia, aErr := intOnly(a)
+	ib, bErr := intOnly(b)
+	if aErr && bErr {
+		switch {
+		case ia < ib:
+			return -1
+		case ia > ib:
+			return 1
+		}
+		return 0
+	}
+	fa, _ := jsonFloat(a)
+	fb, _ := jsonFloat(b)
+	switch {
+	case fa < fb:
+		return -1
+	case fa > fb:
+		return 1
+	}
+	return 0
+}
+
+// intOnly - the value of an integer json.Number, false for anything else
+func intOnly(v interface{}) (int64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := n.Int64()
+	return i, err == nil
+}
@@ -0,0 +1,42 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeJSONKeeps64BitValues(t *testing.T) {
+	var raw map[string]interface{}
+	assert.NoError(t, decodeJSON([]byte(`{"capacity": 9007199254740993, "ratio": 0.5}`), &raw))
+	data, err := json.Marshal(raw)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"capacity": 9007199254740993, "ratio": 0.5}`, string(data))
+	assert.Contains(t, string(data), "9007199254740993")
+
+	n, ok := jsonInt(raw["capacity"])
+	assert.True(t, ok)
+	assert.Equal(t, int64(9007199254740993), n)
+	f, ok := jsonFloat(raw["ratio"])
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, f)
+
+	assert.Error(t, decodeJSON([]byte(`{} {}`), &raw))
+}
+
+func TestJSONInt(t *testing.T) {
+	for _, v := range []interface{}{json.Number("3"), 3.0, 3, int64(3), json.Number("3.0")} {
+		n, ok := jsonInt(v)
+		assert.True(t, ok, "%#v", v)
+		assert.Equal(t, int64(3), n)
+	}
+	_, ok := jsonInt("3")
+	assert.False(t, ok)
+}
+
+func TestCompareNumbers(t *testing.T) {
+	assert.Equal(t, -1, compareNumbers(json.Number("9007199254740992"), json.Number("9007199254740993")))
+	assert.Equal(t, 0, compareNumbers(json.Number("2"), 2.0))
+	assert.Equal(t, 1, compareNumbers(2.5, json.Number("2")))
+}
@@ -1,9 +1,16 @@
+// Package oneview - docker machine driver for HPE OneView, installing the
+// os with ICsp or http boot.  The OneView and ICsp clients come from
+// oneview-golang, which is not part of this repository, so the calls and
+// resources it lacks are functions and types here taking the client rather
+// than client methods.
 package oneview
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -20,10 +27,13 @@ import (
 // Driver OneView driver structure
 type Driver struct {
 	*drivers.BaseDriver
-	ClientICSP           *icsp.ICSPClient
-	ClientOV             *ov.OVClient
-	IloUser              string
-	IloPassword          string
+	ClientICSP       *icsp.ICSPClient
+	ClientOV         *ov.OVClient
+	IloUser          string
+	IloPassword      string
+	RedfishTelemetry bool
+	// Health - last telemetry read from the iLO, see --oneview-redfish-telemetry
+	Health               *HardwareHealth `json:",omitempty"`
 	IloPort              int
 	OSBuildPlans         []string
 	SSHUser              string
@@ -32,9 +42,51 @@ type Driver struct {
 	ServerTemplate       string
 	PublicSlotID         int
 	PublicConnectionName string
-	Profile              ov.ServerProfile
-	Hardware             ov.ServerHardware
-	Server               icsp.Server
+	NetworkSettings      NetworkSettings
+	BootVlan             BootVlan
+	HardwareRequirements HardwareRequirements
+	GenerationSettings   GenerationSettings
+	UpdateFirmware       bool
+	FirmwareBaseline     string
+	OSTimeout            time.Duration
+	Provisioning         string
+	HTTPBoot             HTTPBoot
+	OSRetries            int
+	VerboseInstall       bool
+	InstallConsole       bool
+	IloKnownHosts        string
+	KeepAlive            int
+	CustomAttributes     map[string]string
+	StorageVolumes       []string
+	DataVolumes          []VolumeRequest
+	DisablePowerCapping  bool
+	ExtraHeaders         map[string]string
+	DisableCompression   bool
+	Resolve              map[string]string
+	SSLFingerprints      []string
+	ErrorBodyLimit       int
+	RedactFields         []string
+	PlanPath             string
+	PlanOnly             bool
+	PolicyWebhook        string
+	CallBudget           int
+	Events               string
+	ProfileForce         []ForceOption
+	// Spec - the machine spec loaded with --oneview-spec, policy hooks see it
+	Spec              *MachineSpec `json:",omitempty"`
+	IPv6              IPv6Settings
+	IPv4Range         string
+	IPv4              IPv4Allocation
+	Personalization   NetworkPersonalization
+	ReservationTTL    time.Duration
+	ReclaimIdentities bool
+	DrainScript       string
+	DrainTimeout      time.Duration
+	Profile           ov.ServerProfile
+	Hardware          ov.ServerHardware
+	Server            icsp.Server
+	// provider - the backend, OneView unless a test sets one
+	provider BareMetalProvider
 }
 
 const (
@@ -62,7 +114,6 @@ func NewDriver(machineName string, storePath string) drivers.Driver {
 
 // GetCreateFlags registers the flags this driver adds to
 // "docker hosts create"
-//
 func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 	return []mcnflag.Flag{
 		mcnflag.StringFlag{
@@ -130,6 +181,24 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Usage:  "SSH private key path",
 			EnvVar: "ONEVIEW_SSLVERIFY",
 		},
+		mcnflag.StringSliceFlag{
+			Name:   "oneview-ssl-fingerprint",
+			Usage:  "Optional sha-256 fingerprint the appliance certificate must also have, ie; AB:CD:..., checked after the certificate authorities, so it needs --oneview-sslverify.  Repeat for the ICsp certificate.",
+			Value:  []string{},
+			EnvVar: "ONEVIEW_SSL_FINGERPRINT",
+		},
+		mcnflag.StringSliceFlag{
+			Name:   "oneview-header",
+			Usage:  "Optional extra header, Name: value, sent on the requests the driver makes itself, ie; for an api gateway in front of OneView.  The login, profile and icsp calls of the oneview library go without it, so create does not work through a gateway that requires it.  Repeat for more headers.",
+			Value:  []string{},
+			EnvVar: "ONEVIEW_HEADER",
+		},
+		mcnflag.StringSliceFlag{
+			Name:   "oneview-resolve",
+			Usage:  "Optional host:address, connect to the address for the appliance host name instead of what dns says, like curl --resolve, on the requests the driver makes itself.  The login, profile and icsp calls of the oneview library still look the name up in dns.  Repeat for more hosts.",
+			Value:  []string{},
+			EnvVar: "ONEVIEW_RESOLVE",
+		},
 		mcnflag.StringFlag{
 			Name:   "oneview-ssh-user",
 			Usage:  "OneView build plan ssh user account",
@@ -154,6 +223,24 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Value:  "RHEL71_DOCKER_1.8",
 			EnvVar: "ONEVIEW_OS_PLANS",
 		},
+		mcnflag.StringFlag{
+			Name:   "oneview-provisioning",
+			Usage:  "How the os gets on the server, icsp applies the os plans, http-boot boots the image at --oneview-http-boot-url over uefi http boot with no ICsp.",
+			Value:  ProvisioningICSP,
+			EnvVar: "ONEVIEW_PROVISIONING",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-http-boot-url",
+			Usage:  "Boot image url, http or https, for http-boot provisioning.",
+			Value:  "",
+			EnvVar: "ONEVIEW_HTTP_BOOT_URL",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-http-boot-address",
+			Usage:  "Optional address the http booted os is reached on, by default the machine name is looked up in dns.",
+			Value:  "",
+			EnvVar: "ONEVIEW_HTTP_BOOT_ADDRESS",
+		},
 		mcnflag.StringFlag{
 			Name:   "oneview-ilo-user",
 			Usage:  "ILO User id that is used during ICsp server creation.",
@@ -172,6 +259,11 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Value:  443,
 			EnvVar: "ONEVIEW_ILO_PORT",
 		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-redfish-telemetry",
+			Usage:  "Optional, read fan and power supply health from the iLO over redfish after create and start, shown by docker-machine inspect.",
+			EnvVar: "ONEVIEW_REDFISH_TELEMETRY",
+		},
 		mcnflag.IntFlag{
 			Name:   "oneview-public-slotid",
 			Usage:  "Optional slot id of the public interface to use for connecting with docker.",
@@ -184,9 +276,271 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Value:  "",
 			EnvVar: "ONEVIEW_PUBLIC_CONNECTION_NAME",
 		},
+		mcnflag.StringFlag{
+			Name:   "oneview-hide-unused-flexnics",
+			Usage:  "Optional true or false to hide unused FlexNICs from the OS, by default the server template setting is kept.",
+			Value:  "",
+			EnvVar: "ONEVIEW_HIDE_UNUSED_FLEXNICS",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-boot-vlan",
+			Usage:  "Optional vlan the primary boot connection pxe boots on, untagged for the network the server sees untagged, the native network of a network set, or a vlan id for pxe tagged on a network set.  Connections that cannot carry it fail the create.  By default the server template setting is kept.",
+			Value:  "",
+			EnvVar: "ONEVIEW_BOOT_VLAN",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-port-allocation",
+			Usage:  "Optional port allocation for profile connections, auto lets OneView choose the ports, explicit uses physical ports, by default the server template ports are kept.",
+			Value:  "",
+			EnvVar: "ONEVIEW_PORT_ALLOCATION",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-connection-ports",
+			Usage:  "Optional comma separated list of physical port ids, ie; Flb 1:1-a, assigned to connections in id order when port allocation is explicit.",
+			Value:  "",
+			EnvVar: "ONEVIEW_CONNECTION_PORTS",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-min-memory-gb",
+			Usage:  "Optional minimum memory in GB the server hardware needs to be chosen for the machine.",
+			Value:  0,
+			EnvVar: "ONEVIEW_MIN_MEMORY_GB",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-min-cores",
+			Usage:  "Optional minimum number of processor cores the server hardware needs to be chosen for the machine.",
+			Value:  0,
+			EnvVar: "ONEVIEW_MIN_CORES",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-server-hardware-type",
+			Usage:  "Optional name of the server hardware type to create the machine on, ie; BL460c Gen9 1, for a server template without one.  A template made for another type is refused.",
+			Value:  "",
+			EnvVar: "ONEVIEW_SERVER_HARDWARE_TYPE",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-scope",
+			Usage:  "Optional name of the OneView scope to choose the server hardware from, the profile is created in the scope.",
+			Value:  "",
+			EnvVar: "ONEVIEW_SCOPE",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-allow-unhealthy-hardware",
+			Usage:  "Optional, also choose server hardware with critical status or active critical alerts, by default it is left out.",
+			EnvVar: "ONEVIEW_ALLOW_UNHEALTHY_HARDWARE",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-hardware-generation",
+			Usage:  "Optional hardware generation, gen8, gen9 or synergy, used to pick boot, firmware and port defaults.  Detected from the server hardware when not set.",
+			Value:  "",
+			EnvVar: "ONEVIEW_HARDWARE_GENERATION",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-boot-mode",
+			Usage:  "Optional boot mode, BIOS, UEFI or UEFIOptimized, overrides the server template and hardware generation default.",
+			Value:  "",
+			EnvVar: "ONEVIEW_BOOT_MODE",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-update-firmware",
+			Usage:  "Optional, update the server firmware to the baseline offline before the OS install.",
+			EnvVar: "ONEVIEW_UPDATE_FIRMWARE",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-firmware-baseline",
+			Usage:  "Optional firmware baseline name for --oneview-update-firmware, defaults to the server template baseline.",
+			Value:  "",
+			EnvVar: "ONEVIEW_FIRMWARE_BASELINE",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-os-timeout",
+			Usage:  "Optional minutes to wait for the ICsp OS build plans, the jobs are cancelled and the server powered off when exceeded.  0 waits forever.",
+			Value:  0,
+			EnvVar: "ONEVIEW_OS_TIMEOUT",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-keepalive",
+			Usage:  "Optional seconds between pings keeping the OneView session during long waits, 0 derives it from the appliance session idle timeout and -1 turns it off",
+			Value:  0,
+			EnvVar: "ONEVIEW_KEEPALIVE",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-os-retries",
+			Usage:  "Optional times to run the ICsp OS build plans again after a media timeout or network failure, other failures are not retried.",
+			Value:  1,
+			EnvVar: "ONEVIEW_OS_RETRIES",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-verbose-install",
+			Usage:  "Optional, print the ICsp OS build plan steps and logs while the OS is installed.",
+			EnvVar: "ONEVIEW_VERBOSE_INSTALL",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-install-console",
+			Usage:  "Optional, with --oneview-verbose-install also print the iLO virtual serial port while the OS is installed, the iLO user needs ssh access.  Needs --oneview-ilo-known-hosts.",
+			EnvVar: "ONEVIEW_INSTALL_CONSOLE",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-ilo-known-hosts",
+			Usage:  "Optional known_hosts file with the iLO ssh host keys, ie; from ssh-keyscan, --oneview-install-console only logs in to an iLO whose key is in it.",
+			Value:  "",
+			EnvVar: "ONEVIEW_ILO_KNOWN_HOSTS",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-disable-power-capping",
+			Usage:  "Optional, turn off dynamic power capping and use static high performance power regulation in the profile bios settings.",
+			EnvVar: "ONEVIEW_DISABLE_POWER_CAPPING",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-ipv6-address",
+			Usage:  "Optional static ipv6 address with prefix for the public interface, ie; fd00::20/64, passed to the build plans as @ipv6_address@ and @ipv6_prefix@.",
+			Value:  "",
+			EnvVar: "ONEVIEW_IPV6_ADDRESS",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-ipv6-gateway",
+			Usage:  "Optional static ipv6 default gateway, passed to the build plans as @ipv6_gateway@.",
+			Value:  "",
+			EnvVar: "ONEVIEW_IPV6_GATEWAY",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-ipv4-range",
+			Usage:  "Optional OneView IPAM ipv4 range to allocate the machine address from, passed to the build plans as @ipv4_address@, @ipv4_netmask@, @ipv4_gateway@ and @ipv4_dns@ and given back on remove.",
+			Value:  "",
+			EnvVar: "ONEVIEW_IPV4_RANGE",
+		},
+		mcnflag.StringSliceFlag{
+			Name:   "oneview-static-route",
+			Usage:  "Optional destination=gateway route for the os on top of its default gateway, ie; 10.20.0.0/16=10.1.0.1, passed to the build plans as @static_routes@.  Repeat for more routes.",
+			Value:  []string{},
+			EnvVar: "ONEVIEW_STATIC_ROUTE",
+		},
+		mcnflag.StringSliceFlag{
+			Name:   "oneview-secondary-interface",
+			Usage:  "Optional connection=address/prefix, or connection=dhcp, for a profile connection the os configures besides the public one, passed to the build plans as @secondary_interfaces@.  Repeat for more interfaces.",
+			Value:  []string{},
+			EnvVar: "ONEVIEW_SECONDARY_INTERFACE",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-prefer-ipv6",
+			Usage:  "Optional, connect to the machine over ipv6, for ipv6 only management networks.",
+			EnvVar: "ONEVIEW_PREFER_IPV6",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-reservation-ttl",
+			Usage:  "Optional minutes to reserve the chosen server hardware for while the profile is created, so creates from other workstations pick other hardware.  0 turns reservations off.",
+			Value:  int(defaultReservationTTL / time.Minute),
+			EnvVar: "ONEVIEW_RESERVATION_TTL",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-reclaim-identities",
+			Usage:  "Optional, when the machine is removed force virtual mac, wwn and serial numbers the appliance did not free back into their pools.",
+			EnvVar: "ONEVIEW_RECLAIM_IDENTITIES",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-drain-script",
+			Usage:  "Optional local shell script run as root on the machine over ssh before it is stopped, the profile is labeled cordoned while it drains.",
+			Value:  "",
+			EnvVar: "ONEVIEW_DRAIN_SCRIPT",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-drain-timeout",
+			Usage:  "Optional minutes to wait for the drain script, the machine is left running when exceeded.  0 waits forever.",
+			Value:  10,
+			EnvVar: "ONEVIEW_DRAIN_TIMEOUT",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-disable-compression",
+			Usage:  "Optional, ask the appliances for uncompressed responses on the requests the driver makes itself, for appliances or proxies with broken gzip.  The calls of the oneview library, ie; the login and profile lookups, still ask for gzip.",
+			EnvVar: "ONEVIEW_DISABLE_COMPRESSION",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-error-body-limit",
+			Usage:  "Optional bytes of an appliance error response kept in errors and logs, 0 leaves response bodies out, -1 keeps all of it.",
+			Value:  defaultErrorBodyLimit,
+			EnvVar: "ONEVIEW_ERROR_BODY_LIMIT",
+		},
+		mcnflag.StringSliceFlag{
+			Name:   "oneview-redact-field",
+			Usage:  "Optional regular expression for field names whose values are masked in appliance errors, on top of password, token, community, secret, sessionid and auth.  Repeat for more fields.",
+			Value:  []string{},
+			EnvVar: "ONEVIEW_REDACT_FIELD",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-plan",
+			Usage:  "Optional file to write the create plan to as json before creating the machine, - writes to stdout.",
+			Value:  "",
+			EnvVar: "ONEVIEW_PLAN",
+		},
+		mcnflag.BoolFlag{
+			Name:   "oneview-plan-only",
+			Usage:  "Optional, stop after writing the create plan without creating anything.",
+			EnvVar: "ONEVIEW_PLAN_ONLY",
+		},
+		mcnflag.StringSliceFlag{
+			Name:   "oneview-profile-force",
+			Usage:  "Optional profile warnings to go ahead despite when creating or updating the profile, one of ignoreSANWarnings, ignoreServerHealth, ignoreLSWarnings or all.  Repeat for more.",
+			Value:  []string{},
+			EnvVar: "ONEVIEW_PROFILE_FORCE",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-policy-webhook",
+			Usage:  "Optional url the create plan and machine spec are posted to before anything is changed, it answers {\"allowed\": false, \"reason\": \"...\"} to refuse the create.",
+			Value:  "",
+			EnvVar: "ONEVIEW_POLICY_WEBHOOK",
+		},
+		mcnflag.IntFlag{
+			Name:   "oneview-api-call-budget",
+			Usage:  "Optional number of appliance calls one create, start, stop or remove should need at most, more are logged as a warning with a summary of the calls.  0 turns the warning off.",
+			Value:  0,
+			EnvVar: "ONEVIEW_API_CALL_BUDGET",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-events",
+			Usage:  "Optional file to write stage, progress and error events to as json lines alongside the log.",
+			Value:  "",
+			EnvVar: "ONEVIEW_EVENTS",
+		},
+		mcnflag.StringFlag{
+			Name:   "oneview-spec",
+			Usage:  "Optional path to a yaml or json machine spec, flags that are set to something other than their default override the spec.",
+			Value:  "",
+			EnvVar: "ONEVIEW_SPEC",
+		},
 	}
 }
 
+// UnmarshalJSON - load the saved driver, settings kept outside the driver
+// are put back so every command uses them, not only create
+func (d *Driver) UnmarshalJSON(data []byte) error {
+	type driver Driver
+	// machines saved before --oneview-error-body-limit keep the default
+	d.ErrorBodyLimit = defaultErrorBodyLimit
+	if err := json.Unmarshal(data, (*driver)(d)); err != nil {
+		return err
+	}
+	// without the pins every later command would skip the fingerprint check
+	if err := SetSSLFingerprints(d.SSLFingerprints); err != nil {
+		return err
+	}
+	if d.ClientOV != nil {
+		if err := checkPinningAllowed(d.ClientOV.SSLVerify); err != nil {
+			return err
+		}
+	}
+	setDriverHeaders(d.ExtraHeaders)
+	SetCompression(!d.DisableCompression)
+	policy, err := NewErrorBodyPolicy(d.ErrorBodyLimit, d.RedactFields)
+	if err != nil {
+		return err
+	}
+	SetErrorBodyPolicy(policy)
+	if err := OpenEvents(d.Events); err != nil {
+		return err
+	}
+	return SetResolveOverrides(d.Resolve)
+}
+
 // DriverName - get the name of the driver
 func (d *Driver) DriverName() string {
 	log.Debug("DriverName...%s", driverName)
@@ -207,22 +561,93 @@ func (d *Driver) GetSSHUsername() string {
 
 // SetConfigFromFlags - gets the mcnflag configuration flags
 func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
+	return classifyError(d.setConfigFromFlags(flags))
+}
+
+// setConfigFromFlags - implements SetConfigFromFlags
+func (d *Driver) setConfigFromFlags(flags drivers.DriverOptions) error {
 	log.Debug("SetConfigFromFlags...")
 
+	if path := flags.String("oneview-spec"); path != "" {
+		spec, err := LoadMachineSpec(path)
+		if err != nil {
+			return err
+		}
+		flags = newSpecOptions(flags, spec, d.GetCreateFlags())
+		d.Spec = spec
+		d.CustomAttributes = spec.Attributes
+		for _, v := range spec.Storage {
+			d.StorageVolumes = append(d.StorageVolumes, v.Volume)
+			if v.Template != "" {
+				d.DataVolumes = append(d.DataVolumes, v.volumeRequest())
+			}
+		}
+	}
+
+	resolve, err := parseResolve(flags.StringSlice("oneview-resolve"))
+	if err != nil {
+		return err
+	}
+	d.Resolve = resolve
+	if err := SetResolveOverrides(d.Resolve); err != nil {
+		return err
+	}
+
+	d.SSLFingerprints = flags.StringSlice("oneview-ssl-fingerprint")
+	if err := SetSSLFingerprints(d.SSLFingerprints); err != nil {
+		return err
+	}
+	sslVerify := flags.Bool("oneview-sslverify")
+	if err := checkPinningAllowed(sslVerify); err != nil {
+		return err
+	}
+
+	icspEndpoint, err := normalizeEndpoint(flags.String("oneview-icsp-endpoint"))
+	if err != nil {
+		return err
+	}
+	ovEndpoint, err := normalizeEndpoint(flags.String("oneview-ov-endpoint"))
+	if err != nil {
+		return err
+	}
+
 	d.ClientICSP = d.ClientICSP.NewICSPClient(flags.String("oneview-icsp-user"),
 		flags.String("oneview-icsp-password"),
 		flags.String("oneview-icsp-domain"),
-		flags.String("oneview-icsp-endpoint"),
-		flags.Bool("oneview-sslverify"),
+		icspEndpoint,
+		sslVerify,
 		flags.Int("oneview-icsp-apiversion"))
 
 	d.ClientOV = d.ClientOV.NewOVClient(flags.String("oneview-ov-user"),
 		flags.String("oneview-ov-password"),
 		flags.String("oneview-ov-domain"),
-		flags.String("oneview-ov-endpoint"),
-		flags.Bool("oneview-sslverify"),
+		ovEndpoint,
+		sslVerify,
 		flags.Int("oneview-ov-apiversion"))
 
+	d.DisableCompression = flags.Bool("oneview-disable-compression")
+	SetCompression(!d.DisableCompression)
+
+	d.ErrorBodyLimit = flags.Int("oneview-error-body-limit")
+	d.RedactFields = flags.StringSlice("oneview-redact-field")
+	policy, err := NewErrorBodyPolicy(d.ErrorBodyLimit, d.RedactFields)
+	if err != nil {
+		return err
+	}
+	SetErrorBodyPolicy(policy)
+
+	headers, err := parseHeaders(flags.StringSlice("oneview-header"))
+	if err != nil {
+		return err
+	}
+	if len(headers) > 0 {
+		d.ExtraHeaders = headers
+	}
+	setDriverHeaders(d.ExtraHeaders)
+
+	if err := d.checkPinned(); err != nil {
+		return err
+	}
 	// we only get the version from /version if it's not setup becuse 1 is not a real version
 	if flags.Int("oneview-icsp-apiversion") == 1 {
 		d.ClientICSP.RefreshVersion()
@@ -233,11 +658,91 @@ func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
 
 	d.IloUser = flags.String("oneview-ilo-user")
 	d.IloPassword = flags.String("oneview-ilo-password")
+	d.RedfishTelemetry = flags.Bool("oneview-redfish-telemetry")
 	d.IloPort = flags.Int("oneview-ilo-port")
 
 	d.PublicSlotID = flags.Int("oneview-public-slotid")
 	d.PublicConnectionName = flags.String("oneview-public-connection-name")
 
+	ns, err := newNetworkSettings(flags.String("oneview-hide-unused-flexnics"),
+		flags.String("oneview-port-allocation"),
+		flags.String("oneview-connection-ports"))
+	if err != nil {
+		return err
+	}
+	d.NetworkSettings = ns
+
+	if d.BootVlan, err = parseBootVlan(flags.String("oneview-boot-vlan")); err != nil {
+		return err
+	}
+
+	d.HardwareRequirements = HardwareRequirements{
+		MinMemoryGb:        flags.Int("oneview-min-memory-gb"),
+		MinCores:           flags.Int("oneview-min-cores"),
+		AllowUnhealthy:     flags.Bool("oneview-allow-unhealthy-hardware"),
+		ServerHardwareType: flags.String("oneview-server-hardware-type"),
+		Scope:              flags.String("oneview-scope"),
+	}
+
+	gen, err := ParseGeneration(flags.String("oneview-hardware-generation"))
+	if err != nil {
+		return err
+	}
+	bootMode, err := normalizeBootMode(flags.String("oneview-boot-mode"))
+	if err != nil {
+		return err
+	}
+	d.GenerationSettings = GenerationSettings{Generation: gen, BootMode: bootMode}
+	d.UpdateFirmware = flags.Bool("oneview-update-firmware")
+	d.FirmwareBaseline = flags.String("oneview-firmware-baseline")
+
+	d.OSTimeout = time.Duration(flags.Int("oneview-os-timeout")) * time.Minute
+	d.Provisioning = flags.String("oneview-provisioning")
+	if d.HTTPBoot, err = newHTTPBoot(d.Provisioning,
+		flags.String("oneview-http-boot-url"),
+		flags.String("oneview-http-boot-address")); err != nil {
+		return err
+	}
+	d.OSRetries = flags.Int("oneview-os-retries")
+	d.VerboseInstall = flags.Bool("oneview-verbose-install")
+	d.InstallConsole = flags.Bool("oneview-install-console")
+	d.IloKnownHosts = flags.String("oneview-ilo-known-hosts")
+	if d.InstallConsole && d.IloKnownHosts == "" {
+		return ErrDriverMissingILOKnownHosts
+	}
+	d.KeepAlive = flags.Int("oneview-keepalive")
+	d.DisablePowerCapping = flags.Bool("oneview-disable-power-capping")
+	if d.IPv6, err = newIPv6Settings(flags.String("oneview-ipv6-address"),
+		flags.String("oneview-ipv6-gateway"),
+		flags.Bool("oneview-prefer-ipv6")); err != nil {
+		return err
+	}
+	d.IPv4Range = flags.String("oneview-ipv4-range")
+	if d.Personalization, err = newNetworkPersonalization(flags.StringSlice("oneview-static-route"),
+		flags.StringSlice("oneview-secondary-interface")); err != nil {
+		return err
+	}
+
+	d.ReservationTTL = time.Duration(flags.Int("oneview-reservation-ttl")) * time.Minute
+	d.ReclaimIdentities = flags.Bool("oneview-reclaim-identities")
+	d.DrainScript = flags.String("oneview-drain-script")
+	d.DrainTimeout = time.Duration(flags.Int("oneview-drain-timeout")) * time.Minute
+
+	d.PlanPath = flags.String("oneview-plan")
+	d.PlanOnly = flags.Bool("oneview-plan-only")
+	d.PolicyWebhook = flags.String("oneview-policy-webhook")
+	d.Events = flags.String("oneview-events")
+	d.CallBudget = flags.Int("oneview-api-call-budget")
+	if err := OpenEvents(d.Events); err != nil {
+		return err
+	}
+	if d.ProfileForce, err = ParseForceOptions(flags.StringSlice("oneview-profile-force")); err != nil {
+		return err
+	}
+	if d.PlanOnly && d.PlanPath == "" {
+		d.PlanPath = "-"
+	}
+
 	d.SSHUser = flags.String("oneview-ssh-user")
 	d.SSHPort = flags.Int("oneview-ssh-port")
 
@@ -255,7 +760,7 @@ func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
 		return ErrDriverMissingEndPointOptionOV
 	}
 	// check for the icsp endpoint
-	if d.ClientICSP.Endpoint == "" {
+	if d.usesICSP() && d.ClientICSP.Endpoint == "" {
 		return ErrDriverMissingEndPointOptionICSP
 	}
 	// check for the template name
@@ -274,8 +779,17 @@ func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
 }
 
 // PreCreateCheck - pre create check
-func (d *Driver) PreCreateCheck() (err error) {
+func (d *Driver) PreCreateCheck() error {
+	return classifyError(d.preCreateCheck())
+}
+
+// preCreateCheck - implements PreCreateCheck
+func (d *Driver) preCreateCheck() (err error) {
 	log.Debug("PreCreateCheck...")
+	if err := d.checkPinned(); err != nil {
+		return err
+	}
+	d.logApplianceVersion("PreCreateCheck")
 	// verify you can connect to ov
 	ovVersion, err := d.ClientOV.GetAPIVersion()
 	if err != nil {
@@ -284,133 +798,65 @@ func (d *Driver) PreCreateCheck() (err error) {
 	if ovVersion.CurrentVersion <= 0 {
 		return fmt.Errorf("unable to get a valid version from oneview: %+v", ovVersion)
 	}
-	// verify you can connect to icsp
-	icspVersion, err := d.ClientICSP.GetAPIVersion()
-	if err != nil {
-		return err
-	}
-	if icspVersion.CurrentVersion <= 0 {
-		return fmt.Errorf("unable to get a valid version from icsp: %+v", icspVersion)
-	}
-	return nil
-}
-
-// Create - create server for docker
-func (d *Driver) Create() error {
-	log.Infof("Generating SSH keys...")
-	if err := d.createKeyPair(); err != nil {
-		return fmt.Errorf("unable to create key pair: %s", err)
+	if !d.usesICSP() {
+		return nil
 	}
-
-	log.Debugf("ICSP Endpoint is: %s", d.ClientICSP.Endpoint)
-	log.Debugf("OV Endpoint is: %s", d.ClientOV.Endpoint)
-	// create the server profile in oneview, we need a hostname and a template name
-
-	log.Debugf("***> CreateMachine")
-	// create d.Hardware and d.Profile
-	if err := d.ClientOV.CreateMachine(d.MachineName, d.ServerTemplate); err != nil {
-		return err
-	}
-
-	if err := d.getBlade(); err != nil {
-		return err
-	}
-
-	// power off let customization bring the server online
-	if err := d.Hardware.PowerOff(); err != nil {
+	// verify you can connect to icsp and it speaks our api version
+	icspVersion, err := GetIcspVersion(d.ClientICSP)
+	if err != nil {
 		return err
 	}
-
-	// add the server to icsp, TestCreateServer
-	// apply a build plan, TestApplyDeploymentJobs
-	var sp *icsp.CustomServerAttributes
-	sp = sp.New()
-	sp.Set("docker_user", d.SSHUser)
-	sp.Set("public_key", d.SSHPublicKey)
-	// TODO: make a util for this
-	if len(os.Getenv("proxy_enable")) > 0 {
-		sp.Set("proxy_enable", os.Getenv("proxy_enable"))
-	} else {
-		sp.Set("proxy_enable", "false")
-	}
-
-	strProxy := os.Getenv("proxy_config")
-	sp.Set("proxy_config", strProxy)
-
-	sp.Set("docker_hostname", d.MachineName+"-@server_name@")
-
-	sp.Set("interface", "@interface@") // this is populated later
-
-	// Get the mac address for public Connection on server profile
-	var publicmac string
-	if d.PublicConnectionName != "" {
-		conn, err := d.Profile.GetConnectionByName(d.PublicConnectionName)
-		if err != nil {
-			return err
-		}
-		publicmac = conn.MAC.String()
-	} else {
-		publicmac = ""
-	}
-
-	// arguments for customize server
-	cs := icsp.CustomizeServer{
-		HostName:         d.MachineName,                   // machine-rack-enclosure-bay
-		SerialNumber:     d.Profile.SerialNumber.String(), // get it
-		ILoUser:          d.IloUser,
-		IloPassword:      d.IloPassword,
-		IloIPAddress:     d.Hardware.GetIloIPAddress(), // MpIpAddress for v1
-		IloPort:          d.IloPort,
-		OSBuildPlans:     d.OSBuildPlans, // array of OS Build Plans to apply
-		PublicSlotID:     d.PublicSlotID, // this is the slot id of the public interface
-		PublicMAC:        publicmac,      // Server profile mac address, overrides slotid
-		ServerProperties: sp,
-	}
-	// create d.Server and apply a build plan and configure the custom attributes
-	if err := d.ClientICSP.CustomizeServer(cs); err != nil {
+	if err := checkICSPVersion(icspVersion, d.ClientICSP.APIVersion); err != nil {
 		return err
 	}
-
-	ip, err := d.GetIP()
+	log.Debugf("ICSP at %s is %s", d.ClientICSP.Endpoint, icspVersion)
+	status, err := GetIcspStatus(d.ClientICSP)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to get the icsp appliance health: %w", err)
 	}
-	d.IPAddress = ip
-
-	// use ssh to set keys, and test ssh
-	sshClient, err := d.getLocalSSHClient()
-	if err != nil {
-		return err
+	for _, p := range status.Problems() {
+		log.Warnf("ICSP at %s : %s", d.ClientICSP.Endpoint, p)
 	}
+	return nil
+}
 
-	pubKey, err := ioutil.ReadFile(d.publicSSHKeyPath())
-	if err != nil {
-		return err
-	}
+// Create - create server for docker
+func (d *Driver) Create() error {
+	return d.operation("create", d.create)
+}
 
-	if out, err := sshClient.Output(fmt.Sprintf(
-		"printf '%%s' '%s' | tee /home/%s/.ssh/authorized_keys",
-		string(pubKey),
-		d.GetSSHUsername(),
-	)); err != nil {
-		log.Error(out)
+// create - implements Create
+func (d *Driver) create() error {
+	d.logApplianceVersion("Create")
+	// ctrl-c stops the appliance work we started rather than leaving it running
+	watchInterrupts()
+
+	p := d.backend()
+	if err := runCreate(p, d); err != nil {
+		// docker-machine does not save the driver after a failed create, so
+		// rm would never know to give the address back
+		d.releaseIPv4()
 		return err
 	}
 	log.Infof("%s, Completed all create steps, docker provisioning will continue.", d.DriverName())
 
-	defer closeAll(d)
+	defer p.Close()
 	return nil
 }
 
-// closeAll - cleanup sessions on the OV and ICSP appliances
+// closeAll - cleanup sessions on the OV and ICSP appliances, sessions of
+// the batch commands other drivers still share are left open
 func closeAll(d *Driver) {
-	err := d.ClientOV.SessionLogout()
-	if err != nil {
-		log.Warnf("OV Session Logout : %s", err)
+	// token clients share a session someone else logs out
+	if last, held := SharedClients.releaseOV(d.ClientOV); (last || !held) && d.ClientOV != nil && !isTokenClient(d.ClientOV) {
+		if err := d.ClientOV.SessionLogout(); err != nil {
+			log.Warnf("OV Session Logout : %s", err)
+		}
 	}
-	err = d.ClientICSP.SessionLogout()
-	if err != nil {
-		log.Warnf("ICsp Session Logout : %s", err)
+	if last, held := SharedClients.releaseICSP(d.ClientICSP); (last || !held) && d.ClientICSP != nil && d.ClientICSP.Endpoint != "" {
+		if err := d.ClientICSP.SessionLogout(); err != nil {
+			log.Warnf("ICsp Session Logout : %s", err)
+		}
 	}
 }
 
@@ -421,7 +867,7 @@ func (d *Driver) GetURL() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("tcp://%s:2376", ip), nil
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, "2376")), nil
 }
 
 // GetIP - get server host or ip address
@@ -433,6 +879,22 @@ func (d *Driver) GetIP() (string, error) {
 	if err := d.getBlade(); err != nil {
 		return "", err
 	}
+	if !d.usesICSP() {
+		if d.IPAddress != "" {
+			return d.IPAddress, nil
+		}
+		return d.httpBootAddress()
+	}
+	if d.IPv6.Prefer {
+		ip, err := d.getIPv6()
+		if err != nil {
+			return "", err
+		}
+		if ip != "" {
+			return ip, nil
+		}
+		log.Debugf("no ipv6 address found for %s, trying ipv4", d.MachineName)
+	}
 	sPublicIPv4, err := d.Server.GetPublicIPV4()
 	if err != nil {
 		return "", err
@@ -464,8 +926,25 @@ func (d *Driver) GetState() (state.State, error) {
 	if icsp.ProvisionedFailed.Equal(d.Server.OpswLifecycle) {
 		return state.Error, nil
 	}
+	// a profile being changed or removed decides the state over power
+	profileState, err := getProfileState(d.ClientOV, d.Profile.URI.String())
+	if err != nil {
+		return state.Error, err
+	}
+	switch {
+	case profileState == ProfileStateDeleting:
+		return state.Stopping, nil
+	case profileState.IsApplying():
+		return state.Starting, nil
+	case profileState.IsFailed():
+		return state.Error, nil
+	}
 	// use power state to determine status
-	ps, err := d.Hardware.GetPowerState()
+	var ps ov.PowerState
+	err = libraryCall(d.ClientOV, "GET", func() (err error) {
+		ps, err = d.Hardware.GetPowerState()
+		return err
+	})
 	if err != nil {
 		return state.Error, err
 	}
@@ -484,83 +963,96 @@ func (d *Driver) GetState() (state.State, error) {
 
 // Start - start the docker machine target
 func (d *Driver) Start() error {
+	return d.operation("start", d.start)
+}
+
+// start - implements Start
+func (d *Driver) start() error {
 	log.Infof("Starting ... %s", d.MachineName)
+	d.logApplianceVersion("Start")
 
+	p := d.backend()
 	// get the blade for this driver
-	if err := d.getBlade(); err != nil {
+	if err := p.Locate(); err != nil {
 		return err
 	}
 
 	// power on the server, and leave it in that state
-	if err := d.Hardware.PowerOn(); err != nil {
+	if err := p.PowerOn(); err != nil {
 		return err
 	}
-	// implement icsp check for is in maintenance mode or started
-	isManaged, err := d.ClientICSP.IsServerManaged(d.Hardware.SerialNumber.String())
-	if err != nil {
-		return err
-	}
-	if !isManaged {
-		return errors.New("Server was started but not ready, check icsp status")
+	if err := d.uncordon(); err != nil {
+		log.Warnf("Unable to remove the %s label from %s : %s", CordonLabel, d.MachineName, err)
 	}
+	d.readHealth()
 	return nil
 }
 
 // Stop - stop the docker machine target
 func (d *Driver) Stop() error {
-	log.Debug("Stop...")
-	log.Infof("Stop ... %s", d.MachineName)
-	// gracefully attempt to stop the os
+	return d.operation("stop", d.stop)
+}
 
-	if _, err := drivers.RunSSHCommandFromDriver(d, "sudo shutdown -P now"); err != nil {
-		log.Warnf("Problem shutting down gracefully : %s", err)
+// stop - implements Stop
+func (d *Driver) stop() error {
+	p := d.backend()
+	if err := d.powerDown(p); err != nil {
+		return err
 	}
+	// cleanup
+	defer p.Close()
+	return nil
+}
+
+// powerDown - stop the os and power the blade off, the sessions are left
+// open so remove can go on with them and close them once
+func (d *Driver) powerDown(p BareMetalProvider) error {
+	log.Debug("Stop...")
+	log.Infof("Stop ... %s", d.MachineName)
+	d.logApplianceVersion("Stop")
+	watchInterrupts()
 
 	// get the blade for this driver
-	if err := d.getBlade(); err != nil {
+	if err := p.Locate(); err != nil {
 		return err
 	}
 
-	// power on the server, and leave it in that state
-	if err := d.Hardware.PowerOff(); err != nil {
+	// let workloads move off before the os goes away
+	if err := d.cordon(); err != nil {
 		return err
 	}
-	// cleanup
-	defer closeAll(d)
-	return nil
+
+	// gracefully attempt to stop the os
+	if _, err := drivers.RunSSHCommandFromDriver(d, "sudo shutdown -P now"); err != nil {
+		log.Warnf("Problem shutting down gracefully : %s", err)
+	}
+
+	// power on the server, and leave it in that state
+	return p.PowerOff()
 }
 
 // Remove - remove the docker machine target
-//    Should remove the ICSP provisioned plan and the Server Profile from OV
+//
+//	Should remove the ICSP provisioned plan and the Server Profile from OV
 func (d *Driver) Remove() error {
+	return d.operation("remove", d.remove)
+}
+
+// remove - implements Remove
+func (d *Driver) remove() error {
 	log.Debug("Remove...")
-	// remove the ssh keys
-	if err := d.deleteKeyPair(); err != nil {
-		return err
-	}
-	if err := d.Stop(); err != nil {
-		return err
-	}
-	if err := d.getBlade(); err != nil {
+	d.logApplianceVersion("Remove")
+	p := d.backend()
+	defer p.Close()
+	// the drain script and shutdown still need the ssh keys
+	if err := d.powerDown(p); err != nil {
 		return err
 	}
-	// destroy the server in icsp
-	isDeleted, err := d.ClientICSP.DeleteServer(d.Server.MID)
-	if err != nil {
+	if err := p.Release(); err != nil {
 		return err
 	}
-	if !isDeleted {
-		return fmt.Errorf("Unable to delete the server from icsp : %s, %s", d.MachineName, d.Server.MID)
-	}
-	// delete the server profile in ov : TestDeleteProfile
-	t, err := d.ClientOV.SubmitDeleteProfile(d.Profile)
-	err = t.Wait()
-	if err != nil {
-		return err
-	}
-	// cleanup
-	defer closeAll(d)
-	return nil
+	// remove the ssh keys last, so a failed remove can be run again
+	return d.deleteKeyPair()
 }
 
 // Restart - restart the target machine
@@ -574,9 +1066,18 @@ func (d *Driver) Restart() error {
 
 // Kill - kill the docker machine
 func (d *Driver) Kill() error {
+	return d.operation("kill", d.kill)
+}
+
+// kill - implements Kill, hold the power button without waiting for the os
+func (d *Driver) kill() error {
 	log.Debug("Killing...")
-	//TODO: implement power off , is there a force?
-	return nil
+	p := d.backend()
+	if err := p.Locate(); err != nil {
+		return err
+	}
+	defer p.Close()
+	return setServerHardwarePower(d.ClientOV, d.Hardware.URI.String(), PowerStateOff, PowerControlPressAndHold)
 }
 
 // publicSSHKeyPath - get the path to public ssh key
@@ -589,8 +1090,14 @@ func (d *Driver) publicSSHKeyPath() string {
 
 func (d *Driver) getBlade() (err error) {
 	log.Debug("In getBlade()")
+	if err := d.checkPinned(); err != nil {
+		return err
+	}
 
-	d.Profile, err = d.ClientOV.GetProfileByName(d.MachineName)
+	err = libraryCall(d.ClientOV, "GET", func() (err error) {
+		d.Profile, err = d.ClientOV.GetProfileByName(d.MachineName)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -604,25 +1111,128 @@ func (d *Driver) getBlade() (err error) {
 	// power on the server
 	// get the server hardware associated with that test profile
 	log.Debugf("***> GetServerHardware")
-	d.Hardware, err = d.ClientOV.GetServerHardware(d.Profile.ServerHardwareURI)
+	err = libraryCall(d.ClientOV, "GET", func() (err error) {
+		d.Hardware, err = d.ClientOV.GetServerHardware(d.Profile.ServerHardwareURI)
+		return err
+	})
 	if d.Hardware.URI.IsNil() {
 		err = fmt.Errorf("Attempting to get machine blade information, unable to find machine: %s", d.MachineName)
 		return err
 	}
+	if !d.usesICSP() {
+		return err
+	}
 	// get an icsp server
-	if d.Hardware.VirtualSerialNumber.IsNil() {
-		// get the server profile with SerialNumber
-		d.Server, err = d.ClientICSP.GetServerBySerialNumber(d.Hardware.SerialNumber.String())
-	} else {
+	serial := d.Hardware.SerialNumber
+	if !d.Hardware.VirtualSerialNumber.IsNil() {
 		// get the server profile with the VirtualSerialNumber
-		d.Server, err = d.ClientICSP.GetServerBySerialNumber(d.Hardware.VirtualSerialNumber.String())
+		serial = d.Hardware.VirtualSerialNumber
 	}
+	err = libraryCall(d.ClientICSP, "GET", func() (err error) {
+		d.Server, err = d.ClientICSP.GetServerBySerialNumber(serial.String())
+		return err
+	})
 	if err != nil {
 		return err
 	}
 	return err
 }
 
+// customizeServer - add the server to icsp, apply the os build plans and
+// configure the custom attributes, this creates d.Server
+func (d *Driver) customizeServer() error {
+	var sp *icsp.CustomServerAttributes
+	sp = sp.New()
+	sp.Set("docker_user", d.SSHUser)
+	sp.Set("public_key", d.SSHPublicKey)
+	// TODO: make a util for this
+	if len(os.Getenv("proxy_enable")) > 0 {
+		sp.Set("proxy_enable", os.Getenv("proxy_enable"))
+	} else {
+		sp.Set("proxy_enable", "false")
+	}
+
+	strProxy := os.Getenv("proxy_config")
+	sp.Set("proxy_config", strProxy)
+
+	sp.Set("docker_hostname", d.MachineName+"-@server_name@")
+
+	sp.Set("interface", "@interface@") // this is populated later
+
+	for k, v := range d.IPv6.attributes() {
+		sp.Set(k, v)
+	}
+	for k, v := range d.IPv4.attributes() {
+		sp.Set(k, v)
+	}
+	routes, err := d.Personalization.attributes(func(name string) (string, error) {
+		conn, err := d.Profile.GetConnectionByName(name)
+		return conn.MAC.String(), err
+	})
+	if err != nil {
+		return err
+	}
+	for k, v := range routes {
+		sp.Set(k, v)
+	}
+	for k, v := range d.CustomAttributes {
+		sp.Set(k, v)
+	}
+
+	// Get the mac address for public Connection on server profile
+	var publicmac string
+	if d.PublicConnectionName != "" {
+		conn, err := d.Profile.GetConnectionByName(d.PublicConnectionName)
+		if err != nil {
+			return err
+		}
+		publicmac = conn.MAC.String()
+	} else {
+		publicmac = ""
+	}
+
+	// arguments for customize server
+	cs := icsp.CustomizeServer{
+		HostName:         d.MachineName,                   // machine-rack-enclosure-bay
+		SerialNumber:     d.Profile.SerialNumber.String(), // get it
+		ILoUser:          d.IloUser,
+		IloPassword:      d.IloPassword,
+		IloIPAddress:     d.Hardware.GetIloIPAddress(), // MpIpAddress for v1
+		IloPort:          d.IloPort,
+		OSBuildPlans:     d.OSBuildPlans, // array of OS Build Plans to apply
+		PublicSlotID:     d.PublicSlotID, // this is the slot id of the public interface
+		PublicMAC:        publicmac,      // Server profile mac address, overrides slotid
+		ServerProperties: sp,
+	}
+	// create d.Server and apply a build plan and configure the custom attributes
+	return libraryCall(d.ClientICSP, "POST", func() error {
+		return d.ClientICSP.CustomizeServer(cs)
+	})
+}
+
+// installSSHKey - use ssh to set the machine keys for the docker user
+func (d *Driver) installSSHKey() error {
+	sshClient, err := d.getLocalSSHClient()
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := ioutil.ReadFile(d.publicSSHKeyPath())
+	if err != nil {
+		return err
+	}
+
+	if out, err := sshClient.Output(fmt.Sprintf(
+		"printf '%%s' '%s' | tee /home/%s/.ssh/authorized_keys",
+		string(pubKey),
+		d.GetSSHUsername(),
+	)); err != nil {
+		log.Error(out)
+		return err
+	}
+	return nil
+}
+
 // createKeyPair - generate key files needed
 func (d *Driver) createKeyPair() error {
 
@@ -642,11 +1252,10 @@ func (d *Driver) createKeyPair() error {
 
 // deleteKeyPair
 func (d *Driver) deleteKeyPair() error {
-	if err := os.Remove(d.GetSSHKeyPath()); err != nil {
-		return err
-	}
-	if err := os.Remove(d.GetSSHKeyPath() + ".pub"); err != nil {
-		return err
+	for _, path := range []string{d.GetSSHKeyPath(), d.GetSSHKeyPath() + ".pub"} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
 	return nil
 }
@@ -656,7 +1265,12 @@ func (d *Driver) getLocalSSHClient() (ssh.Client, error) {
 		Passwords: []string{"docker"},
 		Keys:      []string{d.GetSSHKeyPath()},
 	}
-	sshClient, err := ssh.NewNativeClient(d.GetSSHUsername(), d.IPAddress, d.SSHPort, sshAuth)
+	// the native client joins host and port itself, ipv6 needs the brackets
+	host := d.IPAddress
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		host = "[" + host + "]"
+	}
+	sshClient, err := ssh.NewNativeClient(d.GetSSHUsername(), host, d.SSHPort, sshAuth)
 	if err != nil {
 		return nil, err
 	}
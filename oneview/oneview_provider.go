@@ -0,0 +1,195 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// oneviewProvider - machines from OneView server profiles with the os
+// installed by ICsp
+type oneviewProvider struct {
+	d    *Driver
+	plan *CreatePlan
+}
+
+// Plan - choose the template and hardware, written out with --oneview-plan
+func (p *oneviewProvider) Plan() error {
+	d := p.d
+	if d.usesICSP() {
+		log.Debugf("ICSP Endpoint is: %s", d.ClientICSP.Endpoint)
+	}
+	log.Debugf("OV Endpoint is: %s", d.ClientOV.Endpoint)
+	plan, err := d.planCreate()
+	if err != nil {
+		return err
+	}
+	if d.PlanPath != "" {
+		if err := writePlan(plan, d.PlanPath); err != nil {
+			return fmt.Errorf("unable to write create plan: %w", err)
+		}
+	}
+	// policy sees the plan whether or not it goes ahead
+	if err := d.checkCreatePolicy(plan); err != nil {
+		return err
+	}
+	if d.PlanOnly {
+		return ErrPlanOnly
+	}
+	p.plan = plan
+	return nil
+}
+
+// Allocate - create the server profile, the chosen blade is held so other
+// creates leave it alone until the profile is on it
+func (p *oneviewProvider) Allocate() error {
+	d := p.d
+	plan, lease, err := d.reserveHardware(p.plan)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lease.Release(d.ClientOV); err != nil {
+			log.Warnf("Unable to release the reservation on %s, it expires %s : %s", lease.HardwareURI, lease.Expires, err)
+		}
+	}()
+
+	if plan.Hardware != nil {
+		if err := refreshStaleHardware(d.ClientOV, plan.Hardware.URI); err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("***> CreateMachine")
+	// create d.Hardware and d.Profile
+	if err := d.createMachine(plan); err != nil {
+		return err
+	}
+	if err := d.allocateIPv4(); err != nil {
+		return err
+	}
+	return d.getBlade()
+}
+
+// Configure - volumes, profile settings and firmware
+func (p *oneviewProvider) Configure() error {
+	d := p.d
+	// a firmware update can run for an hour
+	defer d.keepAlive()()
+
+	// power off let customization bring the server online
+	if err := libraryCall(d.ClientOV, "PUT", d.Hardware.PowerOff); err != nil {
+		return err
+	}
+
+	if err := d.createDataVolumes(); err != nil {
+		return err
+	}
+
+	// flexnic visibility and port choice change how the os enumerates nics,
+	// the hardware generation decides boot mode and port naming
+	if err := d.updateProfile(d.NetworkSettings.apply, d.bootVlanChange(), d.generationChange(), d.storageChange(), d.powerCappingChange(), d.metadataChange()); err != nil {
+		return err
+	}
+
+	// firmware goes on before the os, so the os drivers match it
+	return d.updateFirmware()
+}
+
+// Deploy - add the server to icsp and apply the build plans
+func (p *oneviewProvider) Deploy() error {
+	// only icsp is polled during the os build
+	defer p.d.keepAlive()()
+	return p.d.customizeServerWithTimeout()
+}
+
+// Address - the public address icsp reports
+func (p *oneviewProvider) Address() (string, error) {
+	return p.d.GetIP()
+}
+
+// Verify - os nics against the profile connections, and iLO telemetry
+func (p *oneviewProvider) Verify() {
+	p.d.checkNICs()
+	p.d.readHealth()
+}
+
+// Locate - the profile, blade and icsp server of the machine
+func (p *oneviewProvider) Locate() error {
+	return p.d.getBlade()
+}
+
+// PowerOn - power on the blade, the os is ready once icsp manages it
+func (p *oneviewProvider) PowerOn() error {
+	d := p.d
+	if err := libraryCall(d.ClientOV, "PUT", d.Hardware.PowerOn); err != nil {
+		return err
+	}
+	var isManaged bool
+	err := libraryCall(d.ClientICSP, "GET", func() (err error) {
+		isManaged, err = d.ClientICSP.IsServerManaged(d.Hardware.SerialNumber.String())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !isManaged {
+		return errors.New("Server was started but not ready, check icsp status")
+	}
+	return nil
+}
+
+// PowerOff - power off the blade
+func (p *oneviewProvider) PowerOff() error {
+	return libraryCall(p.d.ClientOV, "PUT", p.d.Hardware.PowerOff)
+}
+
+// Release - delete the icsp server and the profile
+func (p *oneviewProvider) Release() error {
+	d := p.d
+	// destroy the server in icsp
+	var isDeleted bool
+	err := libraryCall(d.ClientICSP, "DELETE", func() (err error) {
+		isDeleted, err = d.ClientICSP.DeleteServer(d.Server.MID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !isDeleted {
+		return fmt.Errorf("Unable to delete the server from icsp : %s, %s", d.MachineName, d.Server.MID)
+	}
+	return d.releaseProfile()
+}
+
+// releaseProfile - delete the profile, and check the profile identities went
+// back to their pools
+func (d *Driver) releaseProfile() error {
+	// keep the identities to check they go back to the pools
+	identities := map[string][]string{}
+	if profile, err := getResourceMap(d.ClientOV, d.Profile.URI.String()); err == nil {
+		identities = profileIdentities(profile)
+	} else {
+		log.Warnf("Unable to read the identities of %s, they will not be checked : %s", d.MachineName, err)
+	}
+	// delete the server profile in ov : TestDeleteProfile
+	if err := DeleteProfile(d.ClientOV, d.MachineName); err != nil {
+		return err
+	}
+	left, err := ReclaimIdentities(d.ClientOV, identities, d.ReclaimIdentities)
+	if err != nil {
+		log.Warnf("Unable to check the identities of %s went back to their pools : %s", d.MachineName, err)
+	}
+	for pool, ids := range left {
+		log.Warnf("%s identities of %s are still allocated, use --oneview-reclaim-identities to return them : %s", pool, d.MachineName, strings.Join(ids, ", "))
+	}
+	d.releaseIPv4()
+	return nil
+}
+
+// Close - log out of the appliances
+func (p *oneviewProvider) Close() {
+	closeAll(p.d)
+}
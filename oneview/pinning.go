@@ -0,0 +1,179 @@
+package oneview
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSSLFingerprintMismatch - the appliance certificate is not one of the
+// pinned ones
+var ErrSSLFingerprintMismatch = errors.New("Appliance certificate does not match --oneview-ssl-fingerprint")
+
+// ErrPinningWithoutSSLVerify - pins were given with the certificate
+// authority check turned off
+var ErrPinningWithoutSSLVerify = errors.New("--oneview-ssl-fingerprint needs --oneview-sslverify, the OneView rest library connections can not be pinned and would not be checked at all")
+
+// pinned certificate fingerprints, and the endpoints already checked
+var (
+	pinMu       sync.RWMutex
+	pinnedCerts [][]byte
+	pinnedOK    = map[string]bool{}
+)
+
+// parseFingerprint - a sha-256 certificate fingerprint in hex, with or
+// without colons and an optional sha256: prefix, as openssl x509
+// -fingerprint -sha256 prints it
+func parseFingerprint(s string) ([]byte, error) {
+	hexString := strings.TrimSpace(s)
+	if i := strings.Index(hexString, ":"); i > 0 && strings.EqualFold(hexString[:i], "sha256") {
+		hexString = hexString[i+1:]
+	}
+	if i := strings.Index(hexString, "="); i >= 0 {
+		hexString = hexString[i+1:]
+	}
+	hexString = strings.Replace(hexString, ":", "", -1)
+	fp, err := hex.DecodeString(hexString)
+	if err != nil || len(fp) != sha256.Size {
+		return nil, fmt.Errorf("Invalid option --oneview-ssl-fingerprint %q, must be a sha-256 fingerprint, ie; AB:CD:...", s)
+	}
+	return fp, nil
+}
+
+// SetSSLFingerprints - also require the sha-256 fingerprint of the appliance
+// certificate, on top of the certificate authority check.  Any of the
+// fingerprints is accepted, so the OneView and ICsp certificates can both be
+// given.  None turns pinning off.
+func SetSSLFingerprints(fingerprints []string) error {
+	var pins [][]byte
+	for _, s := range fingerprints {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		fp, err := parseFingerprint(s)
+		if err != nil {
+			return err
+		}
+		pins = append(pins, fp)
+	}
+	pinMu.Lock()
+	defer pinMu.Unlock()
+	pinnedCerts = pins
+	pinnedOK = map[string]bool{}
+	return nil
+}
+
+// pinningEnabled - true when certificates are checked by fingerprint
+func pinningEnabled() bool {
+	pinMu.RLock()
+	defer pinMu.RUnlock()
+	return len(pinnedCerts) > 0
+}
+
+// verifyPinnedCertificate - VerifyPeerCertificate accepting a server whose
+// leaf certificate has a pinned fingerprint
+func verifyPinnedCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("%w: no certificate", ErrSSLFingerprintMismatch)
+	}
+	sum := sha256.Sum256(rawCerts[0])
+	pinMu.RLock()
+	defer pinMu.RUnlock()
+	for _, pin := range pinnedCerts {
+		if bytes.Equal(pin, sum[:]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the certificate is %s", ErrSSLFingerprintMismatch, formatFingerprint(sum[:]))
+}
+
+// formatFingerprint - AB:CD:... like openssl prints it
+func formatFingerprint(fp []byte) string {
+	parts := make([]string, len(fp))
+	for i, b := range fp {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// checkPinningAllowed - pins never stand in for the certificate authorities:
+// the rest library makes its own connections, which can not be pinned, so
+// turning the authority check off would leave those unchecked
+func checkPinningAllowed(sslVerify bool) error {
+	if pinningEnabled() && !sslVerify {
+		return ErrPinningWithoutSSLVerify
+	}
+	return nil
+}
+
+// applianceTLSConfig - tls settings of the driver's own appliance clients,
+// pinned fingerprints are checked after the certificate authorities
+func applianceTLSConfig(sslVerify bool) *tls.Config {
+	if pinningEnabled() {
+		return &tls.Config{VerifyPeerCertificate: verifyPinnedCertificate}
+	}
+	return &tls.Config{InsecureSkipVerify: !sslVerify}
+}
+
+// checkPinnedEndpoint - with pinning on, check the certificate of endpoint
+// once before the rest library talks to it.  The library's connections are
+// still checked against the certificate authorities, this also catches an
+// appliance serving another certificate the authorities trust.
+func checkPinnedEndpoint(endpoint string) error {
+	if !pinningEnabled() || endpoint == "" {
+		return nil
+	}
+	pinMu.RLock()
+	ok := pinnedOK[endpoint]
+	pinMu.RUnlock()
+	if ok {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, applianceTLSConfig(true))
+	if err != nil {
+		return fmt.Errorf("unable to check the certificate of %s : %w", endpoint, err)
+	}
+	conn.Close()
+	pinMu.Lock()
+	pinnedOK[endpoint] = true
+	pinMu.Unlock()
+	return nil
+}
+
+// checkPinned - check both appliance certificates before the rest library
+// makes calls of its own, the ones that do not go through ovRequest
+func (d *Driver) checkPinned() error {
+	if d.ClientOV != nil {
+		if err := checkPinningAllowed(d.ClientOV.SSLVerify); err != nil {
+			return err
+		}
+		if err := checkPinnedEndpoint(d.ClientOV.Endpoint); err != nil {
+			return err
+		}
+	}
+	if d.ClientICSP != nil {
+		if err := checkPinningAllowed(d.ClientICSP.SSLVerify); err != nil {
+			return err
+		}
+		return checkPinnedEndpoint(d.ClientICSP.Endpoint)
+	}
+	return nil
+}
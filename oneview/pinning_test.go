@@ -0,0 +1,43 @@
+package oneview
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFingerprint(t *testing.T) {
+	want := sha256.Sum256([]byte("cert"))
+	colons := formatFingerprint(want[:])
+	for _, s := range []string{colons, "sha256:" + colons, "SHA256 Fingerprint=" + colons} {
+		fp, err := parseFingerprint(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want[:], fp, s)
+	}
+	_, err := parseFingerprint("AB:CD")
+	assert.Error(t, err)
+	_, err = parseFingerprint("not hex")
+	assert.Error(t, err)
+}
+
+func TestVerifyPinnedCertificate(t *testing.T) {
+	sum := sha256.Sum256([]byte("appliance cert"))
+	assert.NoError(t, SetSSLFingerprints([]string{formatFingerprint(sum[:])}))
+	defer SetSSLFingerprints(nil)
+
+	assert.True(t, pinningEnabled())
+	assert.False(t, applianceTLSConfig(true).InsecureSkipVerify)
+	assert.NotNil(t, applianceTLSConfig(true).VerifyPeerCertificate)
+	assert.Equal(t, ErrPinningWithoutSSLVerify, checkPinningAllowed(false))
+	assert.NoError(t, checkPinningAllowed(true))
+	assert.NoError(t, verifyPinnedCertificate([][]byte{[]byte("appliance cert"), []byte("ca")}, nil))
+	err := verifyPinnedCertificate([][]byte{[]byte("other cert")}, nil)
+	assert.True(t, errors.Is(err, ErrSSLFingerprintMismatch))
+
+	assert.NoError(t, SetSSLFingerprints(nil))
+	assert.False(t, pinningEnabled())
+	assert.False(t, applianceTLSConfig(true).InsecureSkipVerify)
+	assert.NoError(t, checkPinningAllowed(false))
+}
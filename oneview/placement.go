@@ -0,0 +1,194 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// ErrNoEligibleHardware - no free server hardware meets the machine requirements
+var ErrNoEligibleHardware = errors.New("No available server hardware matches the server template and machine requirements")
+
+// HardwareRequirements - minimum capacity a blade needs to host the machine
+type HardwareRequirements struct {
+	MinMemoryGb int
+	MinCores    int
+	// AllowUnhealthy - also use hardware with critical status or alerts
+	AllowUnhealthy bool
+	// ServerHardwareType - name of the server hardware type to use in place
+	// of the template's, ie; BL460c Gen9 1
+	ServerHardwareType string
+	// Scope - name of the scope to choose hardware from, the profile is
+	// created in it
+	Scope string
+}
+
+// isSet - true when the driver has to choose the hardware, OneView only
+// knows about the server template when it picks
+func (r HardwareRequirements) isSet() bool {
+	return r.MinMemoryGb > 0 || r.MinCores > 0 || !r.AllowUnhealthy || r.ServerHardwareType != "" || r.Scope != ""
+}
+
+// Eligible - does the hardware meet the requirements, with the reason when not
+func (r HardwareRequirements) Eligible(h ServerHardwareInventory) (bool, string) {
+	if !r.AllowUnhealthy && h.Status.IsCritical() {
+		return false, "hardware status is Critical"
+	}
+	if r.MinMemoryGb > 0 && h.MemoryGb() < r.MinMemoryGb {
+		return false, fmt.Sprintf("%dGB memory is less than %dGB", h.MemoryGb(), r.MinMemoryGb)
+	}
+	if r.MinCores > 0 && h.TotalCores() < r.MinCores {
+		return false, fmt.Sprintf("%d cores is less than %d", h.TotalCores(), r.MinCores)
+	}
+	return true, ""
+}
+
+// hardwareByName - stable order so the same blade is picked every time
+type hardwareByName []ServerHardwareInventory
+
+func (h hardwareByName) Len() int      { return len(h) }
+func (h hardwareByName) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h hardwareByName) Less(i, j int) bool {
+	if h[i].Name != h[j].Name {
+		return h[i].Name < h[j].Name
+	}
+	return h[i].URI < h[j].URI
+}
+
+// eligibleHardware - filter candidates down to the ones meeting requirements,
+// sorted by name
+func eligibleHardware(candidates []ServerHardwareInventory, r HardwareRequirements) []ServerHardwareInventory {
+	var eligible []ServerHardwareInventory
+	for _, h := range candidates {
+		if ok, reason := r.Eligible(h); !ok {
+			log.Debugf("skipping %s : %s", h.Name, reason)
+			continue
+		}
+		eligible = append(eligible, h)
+	}
+	sort.Sort(hardwareByName(eligible))
+	return eligible
+}
+
+// templateHardwareFilters - filters for free hardware matching a template's
+// server hardware type and enclosure group
+func templateHardwareFilters(template map[string]interface{}) []string {
+	sht, _ := template["serverHardwareTypeUri"].(string)
+	eg, _ := template["enclosureGroupUri"].(string)
+	return freeHardwareFilters(sht, eg)
+}
+
+// freeHardwareFilters - filters for hardware with no profile of the server
+// hardware type and enclosure group, either can be empty
+func freeHardwareFilters(serverHardwareTypeURI, serverGroupURI string) []string {
+	filters := []string{applianceFilter("state", HardwareStateNoProfileApplied)}
+	if serverHardwareTypeURI != "" {
+		filters = append(filters, applianceFilter("serverHardwareTypeUri", serverHardwareTypeURI))
+	}
+	if serverGroupURI != "" {
+		filters = append(filters, applianceFilter("serverGroupUri", serverGroupURI))
+	}
+	return filters
+}
+
+// GetAvailableHardware - the first powered off blade by name with no profile
+// of the hardware type in the enclosure group, empty for rack servers,
+// ErrNoEligibleHardware when there is none
+func GetAvailableHardware(c *ov.OVClient, serverHardwareTypeURI, serverGroupURI string) (ServerHardwareInventory, error) {
+	filters := append(freeHardwareFilters(serverHardwareTypeURI, serverGroupURI), applianceFilter("powerState", "Off"))
+	candidates, err := listHardwareInventory(c, filters, "")
+	if err != nil {
+		return ServerHardwareInventory{}, err
+	}
+	if len(candidates) == 0 {
+		return ServerHardwareInventory{}, ErrNoEligibleHardware
+	}
+	sort.Sort(hardwareByName(candidates))
+	return candidates[0], nil
+}
+
+// withoutAlerts - drop hardware with alerts against it
+func withoutAlerts(candidates []ServerHardwareInventory, alerts map[string][]Alert) []ServerHardwareInventory {
+	var healthy []ServerHardwareInventory
+	for _, h := range candidates {
+		if a := alerts[h.URI]; len(a) > 0 {
+			log.Debugf("skipping %s : %d critical alerts, %s", h.Name, len(a), a[0].Description)
+			continue
+		}
+		healthy = append(healthy, h)
+	}
+	return healthy
+}
+
+// selectHardware - pick free hardware in the scope for the template meeting the
+// requirements, when owner is set hardware leased to anyone else is skipped
+func selectHardware(c *ov.OVClient, template map[string]interface{}, r HardwareRequirements, scopeURI, owner string) (ServerHardwareInventory, error) {
+	candidates, err := listHardwareInventory(c, templateHardwareFilters(template), scopeURI)
+	if err != nil {
+		return ServerHardwareInventory{}, err
+	}
+	// the appliance knows better than the filters where the template fits
+	if targets := templateTargets(c, template); targets != nil {
+		candidates = onlyTargets(candidates, targets)
+	}
+	if !r.AllowUnhealthy {
+		alerts, err := criticalAlertsByResource(c)
+		if err != nil {
+			return ServerHardwareInventory{}, err
+		}
+		candidates = withoutAlerts(candidates, alerts)
+	}
+	eligible := eligibleHardware(candidates, r)
+	if len(eligible) == 0 {
+		return ServerHardwareInventory{}, monitoredHardwareError(c, template, r, scopeURI)
+	}
+	for _, h := range eligible {
+		if owner == "" {
+			return h, nil
+		}
+		reserved, err := isReserved(c, h.URI, owner)
+		if err != nil {
+			return ServerHardwareInventory{}, err
+		}
+		if !reserved {
+			return h, nil
+		}
+	}
+	return ServerHardwareInventory{}, ErrNoEligibleHardware
+}
+
+// monitoredHardwareError - when nothing managed fits, say so if hardware
+// that would have fitted is only monitored, rather than that there is none.
+// Monitored enclosures have no enclosure group, so only the hardware type
+// of the template narrows the lookup down.
+func monitoredHardwareError(c *ov.OVClient, template map[string]interface{}, r HardwareRequirements, scopeURI string) error {
+	sht, _ := template["serverHardwareTypeUri"].(string)
+	filters := freeHardwareFilters(sht, "")
+	filters[0] = applianceFilter("state", HardwareStateMonitored)
+	monitored, err := listHardwareInventory(c, filters, scopeURI)
+	if err != nil {
+		log.Debugf("unable to look for monitored hardware : %s", err)
+		return ErrNoEligibleHardware
+	}
+	if matching := eligibleHardware(monitored, r); len(matching) > 0 {
+		return &MonitoredHardwareError{Hardware: matching}
+	}
+	return ErrNoEligibleHardware
+}
+
+// createMachine - create the machine profile planned by planCreate.  When
+// there are hardware requirements, including leaving out unhealthy hardware,
+// the plan has the profile for the blade we chose, otherwise OneView picks
+// any free blade for the template.
+func (d *Driver) createMachine(plan *CreatePlan) error {
+	if plan.Profile == nil {
+		return libraryCall(d.ClientOV, "POST", func() error {
+			return d.ClientOV.CreateMachine(d.MachineName, d.ServerTemplate)
+		})
+	}
+	log.Infof("Using server hardware %s", plan.Hardware)
+	return d.applyProfile(plan)
+}
@@ -0,0 +1,116 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEligibleHardware(t *testing.T) {
+	candidates := []ServerHardwareInventory{
+		{Name: "enc1, bay 3", URI: "/rest/server-hardware/3", MemoryMb: 262144, ProcessorCount: 2, ProcessorCoreCount: 12},
+		{Name: "enc1, bay 1", URI: "/rest/server-hardware/1", MemoryMb: 65536, ProcessorCount: 2, ProcessorCoreCount: 8},
+		{Name: "enc1, bay 2", URI: "/rest/server-hardware/2", MemoryMb: 131072, ProcessorCount: 2, ProcessorCoreCount: 10},
+	}
+
+	all := eligibleHardware(candidates, HardwareRequirements{})
+	assert.Len(t, all, 3)
+	assert.Equal(t, "enc1, bay 1", all[0].Name)
+
+	big := eligibleHardware(candidates, HardwareRequirements{MinMemoryGb: 128, MinCores: 20})
+	assert.Len(t, big, 2)
+	assert.Equal(t, "enc1, bay 2", big[0].Name)
+
+	assert.Len(t, eligibleHardware(candidates, HardwareRequirements{MinCores: 32}), 0)
+}
+
+func TestTemplateHardwareFilters(t *testing.T) {
+	filters := templateHardwareFilters(map[string]interface{}{
+		"serverHardwareTypeUri": "/rest/server-hardware-types/A",
+		"enclosureGroupUri":     "/rest/enclosure-groups/B",
+	})
+	assert.Equal(t, []string{
+		"state='NoProfileApplied'",
+		"serverHardwareTypeUri='/rest/server-hardware-types/A'",
+		"serverGroupUri='/rest/enclosure-groups/B'",
+	}, filters)
+}
+
+func TestFreeHardwareFilters(t *testing.T) {
+	assert.Equal(t, []string{
+		"state='NoProfileApplied'",
+		"serverHardwareTypeUri='/rest/server-hardware-types/A'",
+	}, freeHardwareFilters("/rest/server-hardware-types/A", ""))
+	assert.Equal(t, []string{"state='NoProfileApplied'"}, freeHardwareFilters("", ""))
+}
+
+func TestCopyProfileBody(t *testing.T) {
+	source := map[string]interface{}{
+		"name":         "template",
+		"uri":          "/rest/server-profiles/1",
+		"serialNumber": "VCGXXX",
+		"connections": []interface{}{
+			map[string]interface{}{"id": float64(1), "mac": "AA:BB", "networkUri": "/rest/ethernet-networks/1"},
+		},
+	}
+	p := copyProfileBody(source)
+	assert.Nil(t, p["uri"])
+	assert.Nil(t, p["serialNumber"])
+	conns := profileConnections(p)
+	assert.Nil(t, conns[0]["mac"])
+	assert.Equal(t, "/rest/ethernet-networks/1", conns[0]["networkUri"])
+	// the source is left alone
+	assert.Equal(t, "/rest/server-profiles/1", source["uri"])
+}
+
+func TestUnhealthyHardware(t *testing.T) {
+	candidates := []ServerHardwareInventory{
+		{Name: "enc1, bay 1", URI: "/rest/server-hardware/1", Status: "Critical"},
+		{Name: "enc1, bay 2", URI: "/rest/server-hardware/2", Status: "OK"},
+		{Name: "enc1, bay 3", URI: "/rest/server-hardware/3", Status: "Warning"},
+	}
+	assert.True(t, HardwareRequirements{}.isSet())
+	assert.False(t, HardwareRequirements{AllowUnhealthy: true}.isSet())
+	assert.True(t, HardwareRequirements{AllowUnhealthy: true, ServerHardwareType: "BL460c Gen9 1"}.isSet())
+
+	healthy := eligibleHardware(candidates, HardwareRequirements{})
+	assert.Len(t, healthy, 2)
+	assert.Equal(t, "enc1, bay 2", healthy[0].Name)
+	assert.Len(t, eligibleHardware(candidates, HardwareRequirements{AllowUnhealthy: true}), 3)
+
+	alerts := map[string][]Alert{"/rest/server-hardware/2": {{Severity: AlertSeverityCritical, Description: "DIMM failure"}}}
+	left := withoutAlerts(healthy, alerts)
+	assert.Len(t, left, 1)
+	assert.Equal(t, "enc1, bay 3", left[0].Name)
+}
+
+func TestMonitoredHardwareError(t *testing.T) {
+	err := classifyError(&MonitoredHardwareError{Hardware: []ServerHardwareInventory{
+		{Name: "enc1, bay 1", State: HardwareStateMonitored, LocationURI: "/rest/enclosures/1"},
+	}})
+	assert.True(t, errors.Is(err, ErrHardwareMonitoredOnly))
+	assert.Equal(t, CategoryResourceExhausted, CategoryOf(err))
+	assert.Contains(t, err.Error(), "enc1, bay 1. To use them add the enclosure again")
+	assert.NotContains(t, err.Error(), "rack servers")
+
+	rack := &MonitoredHardwareError{Hardware: []ServerHardwareInventory{{Name: "dl360-1", State: HardwareStateMonitored}}}
+	assert.Contains(t, rack.Error(), "rack servers again as managed")
+	assert.True(t, rack.Hardware[0].IsMonitoredOnly())
+}
+
+func TestMonitoredHardwareErrorIgnoresEnclosureGroup(t *testing.T) {
+	template := map[string]interface{}{
+		"serverHardwareTypeUri": "/rest/server-hardware-types/9",
+		"enclosureGroupUri":     "/rest/enclosure-groups/1",
+	}
+	c, done := fakeOV(t, fakeCall{method: "GET", uri: serverHardwareURI,
+		query: map[string]interface{}{"filter": []string{
+			applianceFilter("state", HardwareStateMonitored),
+			applianceFilter("serverHardwareTypeUri", "/rest/server-hardware-types/9"),
+		}},
+		data: `{"members":[{"name":"enc2, bay 1","uri":"/rest/server-hardware/21","state":"Monitored"}]}`})
+	defer done()
+	err := monitoredHardwareError(c, template, HardwareRequirements{AllowUnhealthy: true}, "")
+	assert.True(t, errors.Is(err, ErrHardwareMonitoredOnly))
+}
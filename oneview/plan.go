@@ -0,0 +1,145 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ErrPlanOnly - create stopped after writing the plan, see --oneview-plan-only
+var ErrPlanOnly = errors.New("Create plan written, not creating the machine because of --oneview-plan-only")
+
+// CreatePlan - what create is going to do, resolved against the appliance
+type CreatePlan struct {
+	Machine        string `json:"machine"`
+	ServerTemplate string `json:"serverTemplate"`
+	TemplateURI    string `json:"serverTemplateUri,omitempty"`
+	// Hardware - the chosen hardware, nil when OneView picks a free blade
+	// for the template
+	Hardware *ServerHardwareInventory `json:"serverHardware,omitempty"`
+	// Profile - the profile body that will be submitted, nil when OneView
+	// creates the profile from the template
+	Profile      map[string]interface{} `json:"serverProfile,omitempty"`
+	OSBuildPlans []string               `json:"osBuildPlans"`
+	Steps        []string               `json:"steps"`
+}
+
+// planCreate - resolve the server template and hardware and render the
+// profile the way create will submit it
+func (d *Driver) planCreate() (*CreatePlan, error) {
+	plan := &CreatePlan{
+		Machine:        d.MachineName,
+		ServerTemplate: d.ServerTemplate,
+		OSBuildPlans:   d.OSBuildPlans,
+	}
+	if d.HardwareRequirements.isSet() {
+		template, err := getServerTemplate(d.ClientOV, d.ServerTemplate)
+		if err != nil {
+			return nil, err
+		}
+		plan.TemplateURI, _ = template["uri"].(string)
+		sht, err := d.templateHardwareType(template)
+		if err != nil {
+			return nil, err
+		}
+		scope, err := scopeURIByName(d.ClientOV, d.HardwareRequirements.Scope)
+		if err != nil {
+			return nil, err
+		}
+		if sht != "" {
+			template["serverHardwareTypeUri"] = sht
+		}
+		hw, err := selectHardware(d.ClientOV, template, d.HardwareRequirements, scope, d.leaseOwner())
+		if err != nil {
+			return nil, err
+		}
+		plan.Hardware = &hw
+		profile, err := newProfileFromTemplate(d.ClientOV, template, d.MachineName, hw.URI)
+		if err != nil {
+			return nil, err
+		}
+		if sht != "" {
+			profile["serverHardwareTypeUri"] = sht
+		}
+		generation := func(profile map[string]interface{}) (bool, error) {
+			return d.GenerationSettings.apply(hw.Model, profile)
+		}
+		for _, change := range []profileChange{d.NetworkSettings.apply, generation, d.storageChange(), d.powerCappingChange(), scopeChange(d.ClientOV.APIVersion, scope)} {
+			if _, err := change(profile); err != nil {
+				return nil, err
+			}
+		}
+		// fail now rather than half way through creating the profile
+		if err := checkPoolCapacity(d.ClientOV, profileIdentityNeeds(profile)); err != nil {
+			return nil, err
+		}
+		if err := checkProfileBios(d.ClientOV, profile); err != nil {
+			return nil, err
+		}
+		plan.Profile = profile
+	}
+	plan.Steps = d.createSteps(plan)
+	return plan, nil
+}
+
+// createSteps - the steps create takes for the plan
+func (d *Driver) createSteps(plan *CreatePlan) []string {
+	steps := []string{"generate ssh keys"}
+	if plan.Hardware != nil && d.ReservationTTL > 0 {
+		steps = append(steps, fmt.Sprintf("reserve %s for %s", plan.Hardware.Name, d.ReservationTTL))
+	}
+	if plan.Hardware != nil {
+		steps = append(steps, fmt.Sprintf("create server profile %s from template %s on %s", d.MachineName, d.ServerTemplate, plan.Hardware.Name))
+	} else {
+		steps = append(steps, fmt.Sprintf("create server profile %s from template %s on free hardware chosen by OneView", d.MachineName, d.ServerTemplate))
+	}
+	steps = append(steps, "power off the server hardware")
+	for _, v := range d.DataVolumes {
+		steps = append(steps, fmt.Sprintf("create storage volume %s from template %s unless it exists", v.Name, v.Template))
+	}
+	if !d.NetworkSettings.isDefault() || len(d.StorageVolumes) > 0 || d.DisablePowerCapping ||
+		d.GenerationSettings != (GenerationSettings{}) || plan.Hardware == nil {
+		steps = append(steps, "update the profile network, boot, storage and power settings")
+	}
+	steps = append(steps, "record the machine owner in the profile description")
+	if d.UpdateFirmware {
+		baseline := d.FirmwareBaseline
+		if baseline == "" {
+			baseline = "the server template baseline"
+		}
+		steps = append(steps, fmt.Sprintf("update the firmware offline to %s", baseline))
+	}
+	step := fmt.Sprintf("add the server to ICsp and run os build plans %s", strings.Join(d.OSBuildPlans, ", "))
+	if d.OSTimeout > 0 {
+		step += fmt.Sprintf(", cancelled after %s", d.OSTimeout)
+	}
+	steps = append(steps, step)
+	steps = append(steps, fmt.Sprintf("install the ssh key for %s", d.SSHUser))
+	return steps
+}
+
+// writePlan - write the plan as json to a file, or stdout for -
+func writePlan(plan *CreatePlan, path string) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		return writeAll(os.Stdout, data)
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// writeAll - write data, failing on a short write
+func writeAll(w io.Writer, data []byte) error {
+	n, err := w.Write(data)
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	return err
+}
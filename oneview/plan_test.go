@@ -0,0 +1,72 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSteps(t *testing.T) {
+	d := &Driver{
+		BaseDriver:     &drivers.BaseDriver{MachineName: "docker1"},
+		ServerTemplate: "DOCKER_TEMPLATE",
+		OSBuildPlans:   []string{"RHEL71_DOCKER_1.8"},
+		OSTimeout:      45 * time.Minute,
+		SSHUser:        "docker",
+	}
+	plan := &CreatePlan{Hardware: &ServerHardwareInventory{Name: "enc1, bay 2"}}
+	assert.Equal(t, []string{
+		"generate ssh keys",
+		"create server profile docker1 from template DOCKER_TEMPLATE on enc1, bay 2",
+		"power off the server hardware",
+		"record the machine owner in the profile description",
+		"add the server to ICsp and run os build plans RHEL71_DOCKER_1.8, cancelled after 45m0s",
+		"install the ssh key for docker",
+	}, d.createSteps(plan))
+
+	d.DisablePowerCapping = true
+	assert.Contains(t, d.createSteps(plan), "update the profile network, boot, storage and power settings")
+}
+
+func TestWritePlan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plan")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "plan.json")
+	plan := &CreatePlan{Machine: "docker1", Profile: map[string]interface{}{"name": "docker1"}}
+	assert.NoError(t, writePlan(plan, path))
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	var read CreatePlan
+	assert.NoError(t, json.Unmarshal(data, &read))
+	assert.Equal(t, "docker1", read.Profile["name"])
+}
+
+func TestTemplateHardwareType(t *testing.T) {
+	types := fakeCall{method: "GET", uri: serverHardwareTypesURI,
+		data: `{"members":[{"uri":"/rest/server-hardware-types/9","name":"BL460c Gen9 1"}]}`}
+
+	c, done := fakeOV(t, types, types)
+	d := &Driver{ClientOV: c, ServerTemplate: "DOCKER_TEMPLATE",
+		HardwareRequirements: HardwareRequirements{ServerHardwareType: "BL460c Gen9 1"}}
+	sht, err := d.templateHardwareType(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "/rest/server-hardware-types/9", sht)
+	_, err = d.templateHardwareType(map[string]interface{}{"serverHardwareTypeUri": "/rest/server-hardware-types/8"})
+	assert.True(t, errors.Is(err, ErrServerHardwareTypeMismatch))
+	assert.Equal(t, CategoryUser, CategoryOf(err))
+	done()
+
+	d.HardwareRequirements.ServerHardwareType = ""
+	sht, err = d.templateHardwareType(map[string]interface{}{"serverHardwareTypeUri": "/rest/server-hardware-types/8"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", sht)
+}
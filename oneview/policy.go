@@ -0,0 +1,139 @@
+package oneview
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// ErrPolicyRefused - a policy hook vetoed the create
+var ErrPolicyRefused = errors.New("Create refused by policy")
+
+// policyTimeout - how long a policy webhook gets to answer
+var policyTimeout = 30 * time.Second
+
+// PolicyRequest - what a policy decides on, the profile in the plan is the
+// body create will submit
+type PolicyRequest struct {
+	Machine string       `json:"machine"`
+	Plan    *CreatePlan  `json:"plan"`
+	Spec    *MachineSpec `json:"spec,omitempty"`
+}
+
+// PolicyHook - an organisational check run before create changes anything,
+// ie; naming conventions, allowed networks or size limits.  Returning an
+// error vetoes the create with the error as the reason.
+type PolicyHook func(req PolicyRequest) error
+
+var (
+	policyHooksMu sync.RWMutex
+	policyHooks   []PolicyHook
+)
+
+// AddPolicyHook - check every following create with hook, hooks run in the
+// order they were added
+func AddPolicyHook(hook PolicyHook) {
+	policyHooksMu.Lock()
+	defer policyHooksMu.Unlock()
+	policyHooks = append(policyHooks, hook)
+}
+
+// checkPolicy - run the registered hooks and then extra, the first veto
+// stops the create
+func checkPolicy(req PolicyRequest, extra ...PolicyHook) error {
+	policyHooksMu.RLock()
+	hooks := append(append([]PolicyHook{}, policyHooks...), extra...)
+	policyHooksMu.RUnlock()
+	for _, hook := range hooks {
+		if err := hook(req); err != nil {
+			return &policyRefusal{err: err}
+		}
+	}
+	return nil
+}
+
+// policyRefusal - the veto of a hook, it is ErrPolicyRefused and unwraps to
+// the error of the hook
+type policyRefusal struct {
+	err error
+}
+
+func (e *policyRefusal) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPolicyRefused, e.err)
+}
+
+// Unwrap - the error of the hook
+func (e *policyRefusal) Unwrap() error {
+	return e.err
+}
+
+// Is - errors.Is(err, ErrPolicyRefused)
+func (e *policyRefusal) Is(target error) bool {
+	return target == ErrPolicyRefused
+}
+
+// policyDecision - the answer of a policy webhook
+type policyDecision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// WebhookPolicy - a hook posting the request as json to url, the answer is
+// {"allowed": true} or {"allowed": false, "reason": "..."}.  A webhook that
+// can not be reached or answers anything else vetoes the create.
+func WebhookPolicy(url string, client *http.Client) PolicyHook {
+	return func(req PolicyRequest) error {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("policy webhook %s : %w", url, err)
+		}
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("policy webhook %s : %w", url, err)
+		}
+		return parsePolicyDecision(resp.StatusCode, data)
+	}
+}
+
+// parsePolicyDecision - the veto, if any, in a webhook response
+func parsePolicyDecision(status int, data []byte) error {
+	if status < 200 || status > 299 {
+		return fmt.Errorf("policy webhook returned %d", status)
+	}
+	var d policyDecision
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("policy webhook returned an invalid decision : %w", err)
+	}
+	if d.Allowed {
+		return nil
+	}
+	if d.Reason == "" {
+		d.Reason = "no reason given"
+	}
+	return errors.New(d.Reason)
+}
+
+// checkCreatePolicy - run the policy hooks, and the webhook when one is set,
+// over the plan
+func (d *Driver) checkCreatePolicy(plan *CreatePlan) error {
+	var extra []PolicyHook
+	if d.PolicyWebhook != "" {
+		client := newHTTPClient(d.ClientOV.SSLVerify)
+		client.Timeout = policyTimeout
+		extra = append(extra, WebhookPolicy(d.PolicyWebhook, client))
+	}
+	log.Debugf("checking the create of %s against policy", d.MachineName)
+	return checkPolicy(PolicyRequest{Machine: d.MachineName, Plan: plan, Spec: d.Spec}, extra...)
+}
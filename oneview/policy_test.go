@@ -0,0 +1,37 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicyDecision(t *testing.T) {
+	assert.NoError(t, parsePolicyDecision(200, []byte(`{"allowed": true}`)))
+	assert.EqualError(t, parsePolicyDecision(200, []byte(`{"allowed": false, "reason": "names start with ops-"}`)), "names start with ops-")
+	assert.EqualError(t, parsePolicyDecision(200, []byte(`{"allowed": false}`)), "no reason given")
+	assert.EqualError(t, parsePolicyDecision(503, nil), "policy webhook returned 503")
+	assert.Error(t, parsePolicyDecision(200, []byte(`allowed`)))
+}
+
+func TestCheckPolicy(t *testing.T) {
+	req := PolicyRequest{Machine: "docker1", Plan: &CreatePlan{Machine: "docker1"}}
+	var seen []string
+	allow := func(r PolicyRequest) error { seen = append(seen, "allow "+r.Machine); return nil }
+	deny := func(r PolicyRequest) error { seen = append(seen, "deny"); return errors.New("too big") }
+
+	assert.NoError(t, checkPolicy(req, allow))
+	err := checkPolicy(req, deny, allow)
+	assert.True(t, errors.Is(err, ErrPolicyRefused))
+	assert.EqualError(t, err, "Create refused by policy: too big")
+	assert.Equal(t, []string{"allow docker1", "deny"}, seen)
+	assert.Equal(t, CategoryUser, CategoryOf(err))
+}
+
+func TestPolicyRefusalWraps(t *testing.T) {
+	veto := errors.New("too big")
+	err := checkPolicy(PolicyRequest{}, func(PolicyRequest) error { return veto })
+	assert.True(t, errors.Is(err, ErrPolicyRefused))
+	assert.True(t, errors.Is(err, veto))
+}
@@ -0,0 +1,122 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// ErrPowerCapNotSupported - the hardware does not report a calibrated power range
+var ErrPowerCapNotSupported = errors.New("Server hardware does not support power capping")
+
+// bios settings that control dynamic power capping
+const (
+	biosDynamicPowerCapping = "DynamicPowerCapping"
+	biosPowerRegulator      = "PowerRegulator"
+)
+
+// PowerSettings - power details from the hardware environmental configuration
+type PowerSettings struct {
+	// CalibratedMaxPower - the power limit in watts, 0 when not supported
+	CalibratedMaxPower int `json:"calibratedMaxPower,omitempty"`
+	// IdleMaxPower - lowest limit the hardware accepts, in watts
+	IdleMaxPower        int    `json:"idleMaxPower,omitempty"`
+	CapHistorySupported bool   `json:"capHistorySupported,omitempty"`
+	LicenseRequired     string `json:"licenseRequiredMessage,omitempty"`
+}
+
+// Supported - true when the hardware can be capped
+func (p PowerSettings) Supported() bool {
+	return p.CalibratedMaxPower > 0 && p.LicenseRequired == ""
+}
+
+// environmentalConfigurationURI - power settings of a server hardware
+func environmentalConfigurationURI(hardwareURI string) string {
+	return hardwareURI + "/environmentalConfiguration"
+}
+
+// GetPowerSettings - power settings of a server hardware
+func GetPowerSettings(c *ov.OVClient, hardwareURI string) (PowerSettings, error) {
+	var p PowerSettings
+	data, err := ovCall(c, rest.GET, environmentalConfigurationURI(hardwareURI), nil)
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(data, &p)
+	return p, err
+}
+
+// SetPowerLimit - set the power limit of a server hardware in watts
+func SetPowerLimit(c *ov.OVClient, hardwareURI string, watts int) error {
+	p, err := GetPowerSettings(c, hardwareURI)
+	if err != nil {
+		return err
+	}
+	if !p.Supported() {
+		return ErrPowerCapNotSupported
+	}
+	if watts < p.IdleMaxPower {
+		return fmt.Errorf("power limit %dW is below the idle power of %dW", watts, p.IdleMaxPower)
+	}
+	data, err := ovCall(c, rest.PUT, environmentalConfigurationURI(hardwareURI), map[string]interface{}{"calibratedMaxPower": watts})
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	var t Task
+	if err := json.Unmarshal(data, &t); err == nil && isTaskType(t.Type) {
+		return waitForTask(c, t.URI)
+	}
+	return nil
+}
+
+// setBIOSSetting - override one bios setting on a raw profile, the profile
+// starts managing bios when it did not
+func setBIOSSetting(profile map[string]interface{}, id, value string) bool {
+	bios, _ := profile["bios"].(map[string]interface{})
+	if bios == nil {
+		bios = map[string]interface{}{}
+	}
+	settings, _ := bios["overriddenSettings"].([]interface{})
+	managed, _ := bios["manageBios"].(bool)
+	for _, s := range settings {
+		m, ok := s.(map[string]interface{})
+		if !ok || m["id"] != id {
+			continue
+		}
+		if m["value"] == value && managed {
+			return false
+		}
+		m["value"] = value
+		bios["manageBios"] = true
+		profile["bios"] = bios
+		return true
+	}
+	bios["manageBios"] = true
+	bios["overriddenSettings"] = append(settings, map[string]interface{}{"id": id, "value": value})
+	profile["bios"] = bios
+	return true
+}
+
+// disablePowerCapping - profile change turning off dynamic power capping,
+// which throttles container workloads, and running at static high performance
+func disablePowerCapping(profile map[string]interface{}) (bool, error) {
+	changed := setBIOSSetting(profile, biosDynamicPowerCapping, "Disabled")
+	changed = setBIOSSetting(profile, biosPowerRegulator, "StaticHighPerf") || changed
+	return changed, nil
+}
+
+// powerCappingChange - profile change for the power capping setting
+func (d *Driver) powerCappingChange() profileChange {
+	return func(profile map[string]interface{}) (bool, error) {
+		if !d.DisablePowerCapping {
+			return false, nil
+		}
+		return disablePowerCapping(profile)
+	}
+}
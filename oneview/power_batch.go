@@ -0,0 +1,233 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// PowerOp - a power change of server hardware
+type PowerOp string
+
+// power operations, a graceful off asks the os to shut down
+const (
+	PowerOpOn       PowerOp = "on"
+	PowerOpOff      PowerOp = "off"
+	PowerOpForceOff PowerOp = "off-force"
+	PowerOpRestart  PowerOp = "restart"
+)
+
+// defaultPowerParallelism - machines changed at once when none is given
+const defaultPowerParallelism = 4
+
+// powerStateBody - the power state request of the operation
+func (op PowerOp) powerStateBody() (map[string]interface{}, error) {
+	switch op {
+	case PowerOpOn:
+		return powerStateRequest(PowerStateOn, PowerControlMomentaryPress), nil
+	case PowerOpOff:
+		return powerStateRequest(PowerStateOff, PowerControlMomentaryPress), nil
+	case PowerOpForceOff:
+		return powerStateRequest(PowerStateOff, PowerControlPressAndHold), nil
+	case PowerOpRestart:
+		return powerStateRequest(PowerStateOn, PowerControlReset), nil
+	}
+	return nil, fmt.Errorf("unknown power operation %q, must be one of on, off, off-force or restart", op)
+}
+
+// PowerResult - what a power operation did to one machine
+type PowerResult struct {
+	Machine string
+	// Skipped - the hardware already had the power state
+	Skipped bool
+	Err     error
+	Took    time.Duration
+}
+
+// PowerSummary - the results of a power operation, in machine order
+type PowerSummary struct {
+	Op      PowerOp
+	Results []PowerResult
+}
+
+// Failed - the results with an error
+func (s PowerSummary) Failed() []PowerResult {
+	var failed []PowerResult
+	for _, r := range s.Results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// String - ie; off: 3 changed, 1 already off, 1 failed
+func (s PowerSummary) String() string {
+	changed, skipped := 0, 0
+	for _, r := range s.Results {
+		switch {
+		case r.Err != nil:
+		case r.Skipped:
+			skipped++
+		default:
+			changed++
+		}
+	}
+	parts := []string{fmt.Sprintf("%d changed", changed)}
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d already %s", skipped, strings.TrimSuffix(string(s.Op), "-force")))
+	}
+	if failed := len(s.Failed()); failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", failed))
+	}
+	return fmt.Sprintf("%s: %s", s.Op, strings.Join(parts, ", "))
+}
+
+// PowerOperation - change the power of the hardware of many machines,
+// parallelism at a time, ie; to shut down the hardware of a whole swarm.
+// Every machine is tried, the summary has what happened to each; the error
+// is only for appliances that cannot be reached at all.
+func PowerOperation(cfg ApplianceConfig, machines []string, op PowerOp, parallelism int) (PowerSummary, error) {
+	summary := PowerSummary{Op: op, Results: make([]PowerResult, len(machines))}
+	body, err := op.powerStateBody()
+	if err != nil {
+		return summary, err
+	}
+	c, ic, err := cfg.clients()
+	if err != nil {
+		return summary, err
+	}
+	defer closeAll(&Driver{ClientOV: c, ClientICSP: ic})
+	if parallelism <= 0 {
+		parallelism = defaultPowerParallelism
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallelism)
+	for i, name := range machines {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, name string) {
+			defer func() { <-slots; wg.Done() }()
+			start := time.Now()
+			skipped, err := powerMachine(c, name, body)
+			summary.Results[i] = PowerResult{Machine: name, Skipped: skipped, Err: err, Took: time.Since(start)}
+			if err != nil {
+				log.Warnf("Unable to power %s %s : %s", op, name, err)
+			} else {
+				log.Infof("%s %s", name, op)
+			}
+		}(i, name)
+	}
+	wg.Wait()
+	return summary, nil
+}
+
+// powerMachine - send a power state to the hardware of the machine profile
+// and wait for the task, returns true when it already had that state.  The
+// workers share c, so only the serialized calls of this package are used.
+func powerMachine(c *ov.OVClient, name string, body map[string]interface{}) (bool, error) {
+	profileURI, err := findURIByName(c, serverProfilesURI, name)
+	if err != nil {
+		return false, err
+	}
+	if profileURI == "" {
+		return false, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	profile, err := getResourceMap(c, profileURI)
+	if err != nil {
+		return false, err
+	}
+	uri, _ := profile["serverHardwareUri"].(string)
+	if uri == "" {
+		return false, fmt.Errorf("profile %s has no server hardware", name)
+	}
+	hardware, err := getResourceMap(c, uri)
+	if err != nil {
+		return false, err
+	}
+	if hardware["powerState"] == body["powerState"] && body["powerControl"] != PowerControlReset {
+		return true, nil
+	}
+	return false, setServerHardwarePower(c, uri, body["powerState"].(string), body["powerControl"].(string))
+}
+
+// powerOperationKind - the scheduler kind of a queued PowerOperation
+const powerOperationKind = "power"
+
+// queuedPower - the payload of a queued PowerOperation
+type queuedPower struct {
+	Op          PowerOp  `json:"op"`
+	Machines    []string `json:"machines"`
+	Parallelism int      `json:"parallelism,omitempty"`
+}
+
+// EnqueuePower - queue a PowerOperation to run in a maintenance window
+func (s *Scheduler) EnqueuePower(machines []string, op PowerOp, parallelism int) error {
+	if _, err := op.powerStateBody(); err != nil {
+		return err
+	}
+	return s.enqueuePower(queuedPower{Op: op, Machines: machines, Parallelism: parallelism}, 0, time.Time{})
+}
+
+func (s *Scheduler) enqueuePower(q queuedPower, attempts int, notBefore time.Time) error {
+	payload, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	return s.Enqueue(Operation{Kind: powerOperationKind, Payload: payload, Attempts: attempts, NotBefore: notBefore})
+}
+
+// powerHandler - run queued power operations against the appliances of cfg.
+// Machines that failed are queued again on their own, so a restart does not
+// reach the others twice.
+func powerHandler(s *Scheduler, cfg ApplianceConfig) OperationFunc {
+	return func(op Operation) error {
+		var q queuedPower
+		if err := json.Unmarshal(op.Payload, &q); err != nil {
+			return err
+		}
+		summary, err := PowerOperation(cfg, q.Machines, q.Op, q.Parallelism)
+		if err != nil {
+			return err
+		}
+		log.Infof("%s %s", op.ID, summary)
+		failed := summary.Failed()
+		if len(failed) == 0 {
+			return nil
+		}
+		var names []string
+		for _, r := range failed {
+			names = append(names, r.Machine)
+		}
+		if s.MaxAttempts > 0 && op.Attempts >= s.MaxAttempts {
+			return fmt.Errorf("power %s failed for %s", q.Op, strings.Join(names, ", "))
+		}
+		delay := powerRetryDelay(failed)
+		log.Warnf("power %s failed for %s, trying them again in %s", q.Op, strings.Join(names, ", "), delay)
+		q.Machines = names
+		return s.enqueuePower(q, op.Attempts, s.now().Add(delay))
+	}
+}
+
+// powerRetryDelay - the longest wait a busy appliance asked for among the
+// failed machines, defaultBusyRetry when none said
+func powerRetryDelay(failed []PowerResult) time.Duration {
+	delay := time.Duration(-1)
+	for _, r := range failed {
+		var busy *BusyError
+		if errors.As(r.Err, &busy) && busy.RetryAfter > delay {
+			delay = busy.RetryAfter
+		}
+	}
+	if delay < 0 {
+		return defaultBusyRetry
+	}
+	return delay
+}
@@ -0,0 +1,60 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerStateBody(t *testing.T) {
+	body, err := PowerOpForceOff.powerStateBody()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"powerState": "Off", "powerControl": "PressAndHold"}, body)
+
+	_, err = PowerOp("cycle").powerStateBody()
+	assert.Error(t, err)
+	_, err = PowerOperation(ApplianceConfig{}, []string{"docker-1"}, "cycle", 0)
+	assert.Error(t, err)
+}
+
+func TestPowerSummary(t *testing.T) {
+	s := PowerSummary{Op: PowerOpOff, Results: []PowerResult{
+		{Machine: "docker-1"},
+		{Machine: "docker-2", Skipped: true},
+		{Machine: "docker-3", Err: errors.New("task failed")},
+		{Machine: "docker-4"},
+	}}
+	assert.Equal(t, "off: 2 changed, 1 already off, 1 failed", s.String())
+	assert.Equal(t, []PowerResult{{Machine: "docker-3", Err: errors.New("task failed")}}, s.Failed())
+}
+
+func TestPowerRetryDelay(t *testing.T) {
+	assert.Equal(t, defaultBusyRetry, powerRetryDelay([]PowerResult{{Machine: "docker-1", Err: errors.New("task failed")}}))
+	assert.Equal(t, 2*time.Minute, powerRetryDelay([]PowerResult{
+		{Machine: "docker-1", Err: errors.New("task failed")},
+		{Machine: "docker-2", Err: fmt.Errorf("power off : %w", &BusyError{RetryAfter: 2 * time.Minute})},
+		{Machine: "docker-3", Err: &BusyError{RetryAfter: time.Second}},
+	}))
+}
+
+func TestPowerMachine(t *testing.T) {
+	list := fakeCall{method: "GET", uri: serverProfilesURI, query: map[string]interface{}{"filter": []string{"name='docker-1'"}},
+		data: `{"members":[{"uri":"/rest/server-profiles/1","name":"docker-1"}]}`}
+	profile := fakeCall{method: "GET", uri: "/rest/server-profiles/1", data: `{"serverHardwareUri":"/rest/server-hardware/7"}`}
+	off, err := PowerOpOff.powerStateBody()
+	assert.NoError(t, err)
+
+	c, done := fakeOV(t, list, profile, fakeCall{method: "GET", uri: "/rest/server-hardware/7", data: `{"powerState":"Off"}`})
+	skipped, err := powerMachine(c, "docker-1", off)
+	done()
+	assert.NoError(t, err)
+	assert.True(t, skipped)
+
+	c, done = fakeOV(t, list, fakeCall{method: "GET", uri: "/rest/server-profiles/1", data: `{}`})
+	_, err = powerMachine(c, "docker-1", off)
+	done()
+	assert.EqualError(t, err, "profile docker-1 has no server hardware")
+}
@@ -0,0 +1,39 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisablePowerCapping(t *testing.T) {
+	profile := map[string]interface{}{
+		"bios": map[string]interface{}{
+			"manageBios": true,
+			"overriddenSettings": []interface{}{
+				map[string]interface{}{"id": "PowerRegulator", "value": "DynamicPowerSavings"},
+			},
+		},
+	}
+	changed, err := disablePowerCapping(profile)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	settings := profile["bios"].(map[string]interface{})["overriddenSettings"].([]interface{})
+	assert.Len(t, settings, 2)
+	assert.Equal(t, "StaticHighPerf", settings[0].(map[string]interface{})["value"])
+	assert.Equal(t, map[string]interface{}{"id": "DynamicPowerCapping", "value": "Disabled"}, settings[1])
+
+	changed, _ = disablePowerCapping(profile)
+	assert.False(t, changed)
+
+	empty := map[string]interface{}{}
+	changed, _ = disablePowerCapping(empty)
+	assert.True(t, changed)
+	assert.Equal(t, true, empty["bios"].(map[string]interface{})["manageBios"])
+}
+
+func TestPowerSettingsSupported(t *testing.T) {
+	assert.True(t, PowerSettings{CalibratedMaxPower: 450, IdleMaxPower: 120}.Supported())
+	assert.False(t, PowerSettings{}.Supported())
+	assert.False(t, PowerSettings{CalibratedMaxPower: 450, LicenseRequired: "iLO Advanced license required"}.Supported())
+}
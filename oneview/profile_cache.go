@@ -0,0 +1,89 @@
+package oneview
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// ErrProfileNotFound - no server profile has the name
+var ErrProfileNotFound = errors.New("Server profile not found")
+
+// ProfileCache - profile lookups by name for many goroutines, concurrent
+// lookups of the same name share one appliance call and results are kept
+// for TTL
+type ProfileCache struct {
+	TTL time.Duration
+
+	fetch   func(name string) (ServerProfileSummary, error)
+	now     func() time.Time
+	group   flightGroup
+	mu      sync.Mutex
+	entries map[string]profileCacheEntry
+}
+
+type profileCacheEntry struct {
+	profile ServerProfileSummary
+	at      time.Time
+}
+
+// NewProfileCache - cache profile lookups made with the client
+func NewProfileCache(c *ov.OVClient, ttl time.Duration) *ProfileCache {
+	return newProfileCache(ttl, func(name string) (ServerProfileSummary, error) {
+		return getProfileSummary(c, name)
+	})
+}
+
+func newProfileCache(ttl time.Duration, fetch func(string) (ServerProfileSummary, error)) *ProfileCache {
+	return &ProfileCache{
+		TTL:     ttl,
+		fetch:   fetch,
+		now:     time.Now,
+		entries: make(map[string]profileCacheEntry),
+	}
+}
+
+// getProfileSummary - the profile with the name, ErrProfileNotFound when none
+func getProfileSummary(c *ov.OVClient, name string) (ServerProfileSummary, error) {
+	list, err := ListProfiles(c, ListOptions{Filters: []string{applianceFilter("name", name)}})
+	if err != nil {
+		return ServerProfileSummary{}, err
+	}
+	for _, p := range list {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return ServerProfileSummary{}, ErrProfileNotFound
+}
+
+// Lookup - the named profile, from the cache when it is fresh.  Profiles
+// that are not found are not cached.
+func (pc *ProfileCache) Lookup(name string) (ServerProfileSummary, error) {
+	pc.mu.Lock()
+	e, ok := pc.entries[name]
+	pc.mu.Unlock()
+	if ok && pc.now().Sub(e.at) < pc.TTL {
+		return e.profile, nil
+	}
+	v, err, _ := pc.group.Do(name, func() (interface{}, error) {
+		p, err := pc.fetch(name)
+		if err != nil {
+			return p, err
+		}
+		pc.mu.Lock()
+		pc.entries[name] = profileCacheEntry{profile: p, at: pc.now()}
+		pc.mu.Unlock()
+		return p, nil
+	})
+	return v.(ServerProfileSummary), err
+}
+
+// Invalidate - forget a profile, ie; after changing or deleting it
+func (pc *ProfileCache) Invalidate(name string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	delete(pc.entries, name)
+}
@@ -0,0 +1,63 @@
+package oneview
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileCacheSingleFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	pc := newProfileCache(time.Minute, func(name string) (ServerProfileSummary, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return ServerProfileSummary{Name: name, URI: "/rest/server-profiles/1"}, nil
+	})
+
+	var wg sync.WaitGroup
+	results := make([]ServerProfileSummary, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := pc.Lookup("docker1")
+			assert.NoError(t, err)
+			results[i] = p
+		}(i)
+	}
+	// give the lookups time to pile up on the one in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, p := range results {
+		assert.Equal(t, "/rest/server-profiles/1", p.URI)
+	}
+
+	// cached until invalidated or expired
+	pc.Lookup("docker1")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	pc.Invalidate("docker1")
+	pc.Lookup("docker1")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	pc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	pc.Lookup("docker1")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestProfileCacheNotFound(t *testing.T) {
+	var calls int32
+	pc := newProfileCache(time.Minute, func(name string) (ServerProfileSummary, error) {
+		atomic.AddInt32(&calls, 1)
+		return ServerProfileSummary{}, ErrProfileNotFound
+	})
+	_, err := pc.Lookup("missing")
+	assert.Equal(t, ErrProfileNotFound, err)
+	pc.Lookup("missing")
+	assert.Equal(t, int32(2), calls)
+}
@@ -0,0 +1,142 @@
+package oneview
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// connection function types
+const (
+	FunctionTypeEthernet     = "Ethernet"
+	FunctionTypeFibreChannel = "FibreChannel"
+	FunctionTypeFCoE         = "FCoE"
+	FunctionTypeISCSI        = "iSCSI"
+)
+
+// connection boot priorities, a profile boots from at most one primary and
+// one secondary connection
+const (
+	BootPrimary     = "Primary"
+	BootSecondary   = "Secondary"
+	BootNotBootable = "NotBootable"
+)
+
+// connectionList - the connections of the profile whichever way the api
+// version lists them
+func (p *ServerProfileSpec) connectionList() *[]ProfileConnection {
+	if p.ConnectionSettings != nil {
+		return &p.ConnectionSettings.Connections
+	}
+	return &p.Connections
+}
+
+// Connection - the connection with the id, nil when there is none
+func (p *ServerProfileSpec) Connection(id int) *ProfileConnection {
+	conns := p.connectionList()
+	for i := range *conns {
+		if (*conns)[i].ID == id {
+			return &(*conns)[i]
+		}
+	}
+	return nil
+}
+
+// AddConnection - add a connection to the network, with the next free id,
+// on a port OneView picks and not bootable.  Empty functionType is Ethernet,
+// requestedMbps 0 leaves the bandwidth to the network.
+func (p *ServerProfileSpec) AddConnection(networkURI, functionType string, requestedMbps int) (*ProfileConnection, error) {
+	if networkURI == "" {
+		return nil, fmt.Errorf("a connection needs a network")
+	}
+	switch functionType {
+	case "":
+		functionType = FunctionTypeEthernet
+	case FunctionTypeEthernet, FunctionTypeFibreChannel, FunctionTypeFCoE, FunctionTypeISCSI:
+	default:
+		return nil, fmt.Errorf("unknown connection function type %s", functionType)
+	}
+	conns := p.connectionList()
+	id := 1
+	for _, c := range *conns {
+		if c.ID >= id {
+			id = c.ID + 1
+		}
+	}
+	conn := ProfileConnection{
+		ID:           id,
+		Name:         fmt.Sprintf("connection %d", id),
+		FunctionType: functionType,
+		NetworkURI:   networkURI,
+		PortID:       autoPortID,
+		Boot:         &ConnectionBoot{Priority: BootNotBootable},
+	}
+	if requestedMbps > 0 {
+		conn.RequestedMbps = strconv.Itoa(requestedMbps)
+	}
+	*conns = append(*conns, conn)
+	return &(*conns)[len(*conns)-1], nil
+}
+
+// RemoveConnection - remove the connection and the san storage paths over
+// it.  When it was the primary boot connection the secondary takes over.
+func (p *ServerProfileSpec) RemoveConnection(id int) error {
+	conns := p.connectionList()
+	idx := -1
+	for i, c := range *conns {
+		if c.ID == id {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("profile %s has no connection %d", p.Name, id)
+	}
+	removed := (*conns)[idx]
+	*conns = append((*conns)[:idx], (*conns)[idx+1:]...)
+	if removed.Boot != nil && removed.Boot.Priority == BootPrimary {
+		for i := range *conns {
+			if b := (*conns)[i].Boot; b != nil && b.Priority == BootSecondary {
+				b.Priority = BootPrimary
+			}
+		}
+	}
+	if p.SanStorage != nil {
+		for i := range p.SanStorage.VolumeAttachments {
+			a := &p.SanStorage.VolumeAttachments[i]
+			var paths []StoragePath
+			for _, sp := range a.StoragePaths {
+				if sp.ConnectionID != id {
+					paths = append(paths, sp)
+				}
+			}
+			a.StoragePaths = paths
+		}
+	}
+	return nil
+}
+
+// SetBootPriority - make the connection Primary, Secondary or NotBootable,
+// a connection that had the priority before becomes NotBootable
+func (p *ServerProfileSpec) SetBootPriority(id int, priority string) error {
+	switch priority {
+	case BootPrimary, BootSecondary, BootNotBootable:
+	default:
+		return fmt.Errorf("unknown boot priority %s, use Primary, Secondary or NotBootable", priority)
+	}
+	conn := p.Connection(id)
+	if conn == nil {
+		return fmt.Errorf("profile %s has no connection %d", p.Name, id)
+	}
+	if priority != BootNotBootable {
+		conns := p.connectionList()
+		for i := range *conns {
+			if b := (*conns)[i].Boot; b != nil && b.Priority == priority && (*conns)[i].ID != id {
+				b.Priority = BootNotBootable
+			}
+		}
+	}
+	if conn.Boot == nil {
+		conn.Boot = &ConnectionBoot{}
+	}
+	conn.Boot.Priority = priority
+	return nil
+}
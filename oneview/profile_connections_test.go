@@ -0,0 +1,48 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddConnection(t *testing.T) {
+	var p ServerProfileSpec
+	c, err := p.AddConnection("/rest/ethernet-networks/A", "", 2500)
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileConnection{ID: 1, Name: "connection 1", FunctionType: "Ethernet", NetworkURI: "/rest/ethernet-networks/A",
+		PortID: "Auto", RequestedMbps: "2500", Boot: &ConnectionBoot{Priority: BootNotBootable}}, *c)
+	p.Connections = append(p.Connections, ProfileConnection{ID: 5})
+	c, err = p.AddConnection("/rest/fc-networks/B", FunctionTypeFibreChannel, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, c.ID)
+	_, err = p.AddConnection("/rest/ethernet-networks/A", "Token Ring", 0)
+	assert.Error(t, err)
+
+	// newer api versions keep connections under connectionSettings
+	p = ServerProfileSpec{ConnectionSettings: &ConnectionSettings{ManageConnections: true}}
+	p.AddConnection("/rest/ethernet-networks/A", "", 0)
+	assert.Len(t, p.ConnectionSettings.Connections, 1)
+	assert.Len(t, p.Connections, 0)
+}
+
+func TestBootPriority(t *testing.T) {
+	var p ServerProfileSpec
+	for i := 0; i < 3; i++ {
+		p.AddConnection("/rest/ethernet-networks/A", "", 0)
+	}
+	assert.NoError(t, p.SetBootPriority(1, BootPrimary))
+	assert.NoError(t, p.SetBootPriority(2, BootSecondary))
+	assert.NoError(t, p.SetBootPriority(3, BootPrimary))
+	assert.Equal(t, BootNotBootable, p.Connection(1).Boot.Priority)
+	assert.Equal(t, BootPrimary, p.Connection(3).Boot.Priority)
+	assert.Error(t, p.SetBootPriority(9, BootPrimary))
+	assert.Error(t, p.SetBootPriority(1, "First"))
+
+	p.SanStorage = &SanStorage{VolumeAttachments: []VolumeAttachment{{ID: 1, StoragePaths: []StoragePath{{3, true}, {2, true}}}}}
+	assert.NoError(t, p.RemoveConnection(3))
+	assert.Nil(t, p.Connection(3))
+	assert.Equal(t, BootPrimary, p.Connection(2).Boot.Priority)
+	assert.Equal(t, []StoragePath{{2, true}}, p.SanStorage.VolumeAttachments[0].StoragePaths)
+	assert.Error(t, p.RemoveConnection(3))
+}
@@ -0,0 +1,127 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/docker-machine-oneview/version"
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// metadataMarker - starts the machine metadata in a profile description,
+// the json object follows it to the end of the description
+const metadataMarker = "docker-machine:"
+
+// MachineMetadata - who owns the machine a profile belongs to, kept in the
+// profile description so appliances too old for the labels api carry it
+type MachineMetadata struct {
+	Owner     string    `json:"owner,omitempty"`
+	Machine   string    `json:"machine"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	Created   time.Time `json:"created,omitempty"`
+}
+
+// EncodeDescription - the description text followed by the metadata, any
+// metadata already in the text is replaced
+func EncodeDescription(text string, m MachineMetadata) (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	text, _, _ = DecodeDescription(text)
+	if text == "" {
+		return metadataMarker + string(data), nil
+	}
+	return text + " " + metadataMarker + string(data), nil
+}
+
+// DecodeDescription - split a profile description into its text and the
+// metadata, the metadata is nil when the description has none.  Metadata
+// that does not parse is left in the text with the error.
+func DecodeDescription(description string) (string, *MachineMetadata, error) {
+	i := strings.LastIndex(description, metadataMarker)
+	if i < 0 {
+		return description, nil, nil
+	}
+	var m MachineMetadata
+	if err := json.Unmarshal([]byte(description[i+len(metadataMarker):]), &m); err != nil {
+		return description, nil, fmt.Errorf("unable to read machine metadata in profile description : %w", err)
+	}
+	return strings.TrimSpace(description[:i]), &m, nil
+}
+
+// MachineProfile - a profile with the metadata in its description
+type MachineProfile struct {
+	ServerProfileSummary
+	Metadata MachineMetadata `json:"metadata"`
+}
+
+// ListMachineProfiles - profiles carrying machine metadata that match, by
+// name.  A nil match returns every one of them.
+func ListMachineProfiles(c *ov.OVClient, match func(MachineMetadata) bool) ([]MachineProfile, error) {
+	return ListScopedMachineProfiles(c, "", match)
+}
+
+// ListScopedMachineProfiles - ListMachineProfiles only looking in the scope
+// at scopeURI, so teams sharing an appliance each see their own machines
+func ListScopedMachineProfiles(c *ov.OVClient, scopeURI string, match func(MachineMetadata) bool) ([]MachineProfile, error) {
+	filters := []string{fmt.Sprintf("description matches '%%%s%%'", metadataMarker)}
+	profiles, err := ListProfiles(c, ListOptions{Filters: filters, Scope: scopeURI})
+	if err != nil {
+		return nil, err
+	}
+	var list []MachineProfile
+	for _, p := range profiles {
+		_, m, err := DecodeDescription(p.Description)
+		if err != nil || m == nil {
+			continue
+		}
+		if match == nil || match(*m) {
+			list = append(list, MachineProfile{ServerProfileSummary: p, Metadata: *m})
+		}
+	}
+	return list, nil
+}
+
+// OwnedBy - match for ListMachineProfiles, profiles of one owner
+func OwnedBy(owner string) func(MachineMetadata) bool {
+	return func(m MachineMetadata) bool {
+		return m.Owner == owner
+	}
+}
+
+// metadataOwner - the local user and workstation running the driver
+func metadataOwner() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	return leaseOwner(name)
+}
+
+// metadataChange - profile change writing the machine metadata into the
+// description, keeping the description text from the server template
+func (d *Driver) metadataChange() profileChange {
+	return func(profile map[string]interface{}) (bool, error) {
+		before, _ := profile["description"].(string)
+		text, existing, _ := DecodeDescription(before)
+		m := MachineMetadata{
+			Owner:     metadataOwner(),
+			Machine:   d.MachineName,
+			CreatedBy: "docker-machine-driver-oneview " + version.Version,
+			Created:   time.Now().UTC().Truncate(time.Second),
+		}
+		if existing != nil && existing.Machine == m.Machine {
+			return false, nil
+		}
+		description, err := EncodeDescription(text, m)
+		if err != nil {
+			return false, err
+		}
+		profile["description"] = description
+		return true, nil
+	}
+}
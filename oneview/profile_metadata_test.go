@@ -0,0 +1,43 @@
+package oneview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescriptionMetadata(t *testing.T) {
+	m := MachineMetadata{Owner: "jane@ws1", Machine: "docker1", CreatedBy: "docker-machine-driver-oneview 0.9-dev",
+		Created: time.Date(2016, 3, 1, 18, 5, 33, 0, time.UTC)}
+	d, err := EncodeDescription("Docker hosts", m)
+	assert.NoError(t, err)
+	assert.Equal(t, `Docker hosts docker-machine:{"owner":"jane@ws1","machine":"docker1","createdBy":"docker-machine-driver-oneview 0.9-dev","created":"2016-03-01T18:05:33Z"}`, d)
+
+	text, got, err := DecodeDescription(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "Docker hosts", text)
+	assert.Equal(t, m, *got)
+
+	// encoding again replaces the metadata rather than adding more
+	m.Machine = "docker2"
+	d, err = EncodeDescription(d, m)
+	assert.NoError(t, err)
+	text, got, _ = DecodeDescription(d)
+	assert.Equal(t, "Docker hosts", text)
+	assert.Equal(t, "docker2", got.Machine)
+
+	text, got, err = DecodeDescription("plain description")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Equal(t, "plain description", text)
+
+	_, got, err = DecodeDescription("docker-machine:{broken")
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func TestOwnedBy(t *testing.T) {
+	assert.True(t, OwnedBy("jane@ws1")(MachineMetadata{Owner: "jane@ws1"}))
+	assert.False(t, OwnedBy("jane@ws1")(MachineMetadata{Owner: "bob@ws2"}))
+}
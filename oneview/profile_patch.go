@@ -0,0 +1,73 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// profile sections the appliance applies again when patched, see ReapplyOp
+const (
+	ReapplyFirmware           = "firmware"
+	ReapplyConnections        = "connectionSettings"
+	ReapplyLocalStorage       = "localStorage"
+	ReapplySanStorage         = "sanStorage"
+	ReapplyBios               = "bios"
+	ReapplyOSDeploymentConfig = "osDeploymentSettings"
+)
+
+// ErrNoPatchOps - a profile patch with nothing in it
+var ErrNoPatchOps = errors.New("no patch operations for the profile")
+
+// RefreshProfileOp - patch op making the appliance read the server hardware
+// again and bring the profile state up to date
+func RefreshProfileOp() PatchOp {
+	return PatchOp{Op: PatchReplace, Path: "/refreshState", Value: "RefreshPending"}
+}
+
+// TemplateComplianceOp - patch op updating the profile from its server
+// profile template
+func TemplateComplianceOp() PatchOp {
+	return PatchOp{Op: PatchReplace, Path: "/templateCompliance", Value: "Compliant"}
+}
+
+// ReapplyOp - patch op applying a profile section to the server again, ie;
+// ReapplyFirmware installs the firmware baseline of the profile again
+func ReapplyOp(section string) PatchOp {
+	return PatchOp{Op: PatchReplace, Path: "/" + section + "/reapplyState", Value: "ApplyPending"}
+}
+
+// PatchProfile - send patch operations to the named profile and block until
+// the appliance has applied them, refresh and reapply only exist as patches
+func PatchProfile(c *ov.OVClient, name string, ops []PatchOp, force ...ForceOption) error {
+	if len(ops) == 0 {
+		return ErrNoPatchOps
+	}
+	uri, err := findURIByName(c, serverProfilesURI, name)
+	if err != nil {
+		return err
+	}
+	if uri == "" {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	log.Debugf("patching profile %s with %d operations", name, len(ops))
+	data, err := ovCall(c, rest.PATCH, withForce(uri, force), ops)
+	if err != nil {
+		return err
+	}
+	return waitForTaskResponse(c, data)
+}
+
+// RefreshProfile - refresh the named profile from its server hardware
+func RefreshProfile(c *ov.OVClient, name string) error {
+	return PatchProfile(c, name, []PatchOp{RefreshProfileOp()})
+}
+
+// UpdateProfileFromBaseline - install the firmware baseline of the named
+// profile on its server again, ie; after the baseline was updated
+func UpdateProfileFromBaseline(c *ov.OVClient, name string, force ...ForceOption) error {
+	return PatchProfile(c, name, []PatchOp{ReapplyOp(ReapplyFirmware)}, force...)
+}
@@ -0,0 +1,21 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileLifecycleOps(t *testing.T) {
+	data, err := json.Marshal([]PatchOp{RefreshProfileOp(), TemplateComplianceOp(), ReapplyOp(ReapplyFirmware)})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/refreshState", "value": "RefreshPending"},
+		{"op": "replace", "path": "/templateCompliance", "value": "Compliant"},
+		{"op": "replace", "path": "/firmware/reapplyState", "value": "ApplyPending"}]`, string(data))
+}
+
+func TestPatchProfileNeedsOps(t *testing.T) {
+	assert.Equal(t, ErrNoPatchOps, PatchProfile(nil, "docker-1", nil))
+}
@@ -0,0 +1,183 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// ServerProfileSpec - a server profile with the sections that configure the
+// server, ov.ServerProfile only has the top level attributes
+type ServerProfileSpec struct {
+	Type                     string              `json:"type,omitempty"`
+	URI                      string              `json:"uri,omitempty"`
+	ETag                     string              `json:"eTag,omitempty"`
+	Name                     string              `json:"name,omitempty"`
+	Description              string              `json:"description,omitempty"`
+	SerialNumber             string              `json:"serialNumber,omitempty"`
+	ServerHardwareURI        string              `json:"serverHardwareUri,omitempty"`
+	ServerHardwareTypeURI    string              `json:"serverHardwareTypeUri,omitempty"`
+	EnclosureGroupURI        string              `json:"enclosureGroupUri,omitempty"`
+	EnclosureURI             string              `json:"enclosureUri,omitempty"`
+	EnclosureBay             int                 `json:"enclosureBay,omitempty"`
+	ServerProfileTemplateURI string              `json:"serverProfileTemplateUri,omitempty"`
+	HideUnusedFlexNics       *bool               `json:"hideUnusedFlexNics,omitempty"`
+	Connections              []ProfileConnection `json:"connections,omitempty"`
+	ConnectionSettings       *ConnectionSettings `json:"connectionSettings,omitempty"`
+	Boot                     *ProfileBoot        `json:"boot,omitempty"`
+	BootMode                 *ProfileBootMode    `json:"bootMode,omitempty"`
+	Bios                     *ProfileBios        `json:"bios,omitempty"`
+	LocalStorage             *LocalStorage       `json:"localStorage,omitempty"`
+	SanStorage               *SanStorage         `json:"sanStorage,omitempty"`
+}
+
+// ProfileConnection - a network or fibre channel connection of a profile
+type ProfileConnection struct {
+	ID            int             `json:"id,omitempty"`
+	Name          string          `json:"name,omitempty"`
+	FunctionType  string          `json:"functionType,omitempty"`
+	NetworkURI    string          `json:"networkUri,omitempty"`
+	PortID        string          `json:"portId,omitempty"`
+	RequestedMbps string          `json:"requestedMbps,omitempty"`
+	MAC           string          `json:"mac,omitempty"`
+	MACType       string          `json:"macType,omitempty"`
+	WWPN          string          `json:"wwpn,omitempty"`
+	WWNN          string          `json:"wwnn,omitempty"`
+	WWPNType      string          `json:"wwpnType,omitempty"`
+	Boot          *ConnectionBoot `json:"boot,omitempty"`
+}
+
+// ConnectionBoot - how a connection takes part in booting
+type ConnectionBoot struct {
+	Priority         string       `json:"priority,omitempty"`
+	BootVlanID       int          `json:"bootVlanId,omitempty"`
+	EthernetBootType string       `json:"ethernetBootType,omitempty"`
+	BootVolumeSource string       `json:"bootVolumeSource,omitempty"`
+	Targets          []BootTarget `json:"targets,omitempty"`
+}
+
+// BootTarget - a san boot target of a connection
+type BootTarget struct {
+	ArrayWWPN string `json:"arrayWwpn,omitempty"`
+	LUN       string `json:"lun,omitempty"`
+}
+
+// ProfileBoot - the boot order, ie; CD, USB, HardDisk, PXE
+type ProfileBoot struct {
+	ManageBoot bool     `json:"manageBoot"`
+	Order      []string `json:"order,omitempty"`
+}
+
+// ProfileBootMode - BIOS or UEFI booting
+type ProfileBootMode struct {
+	ManageMode    bool   `json:"manageMode"`
+	Mode          string `json:"mode,omitempty"`
+	PXEBootPolicy string `json:"pxeBootPolicy,omitempty"`
+}
+
+// ProfileBios - bios settings changed from their defaults
+type ProfileBios struct {
+	ManageBios         bool          `json:"manageBios"`
+	OverriddenSettings []BiosSetting `json:"overriddenSettings,omitempty"`
+}
+
+// BiosSetting - one bios setting
+type BiosSetting struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// LocalStorage - the local raid controllers and their logical drives
+type LocalStorage struct {
+	Controllers []LocalStorageController `json:"controllers,omitempty"`
+}
+
+// LocalStorageController - one local storage controller
+type LocalStorageController struct {
+	DeviceSlot          string         `json:"deviceSlot,omitempty"`
+	Mode                string         `json:"mode,omitempty"`
+	Initialize          bool           `json:"initialize"`
+	ImportConfiguration bool           `json:"importConfiguration,omitempty"`
+	LogicalDrives       []LogicalDrive `json:"logicalDrives,omitempty"`
+}
+
+// LogicalDrive - a raid logical drive
+type LogicalDrive struct {
+	Name              string `json:"name,omitempty"`
+	RaidLevel         string `json:"raidLevel,omitempty"`
+	Bootable          bool   `json:"bootable"`
+	NumPhysicalDrives int    `json:"numPhysicalDrives,omitempty"`
+	DriveTechnology   string `json:"driveTechnology,omitempty"`
+}
+
+// SanStorage - the san volumes attached to the profile
+type SanStorage struct {
+	ManageSanStorage  bool               `json:"manageSanStorage"`
+	HostOSType        string             `json:"hostOSType,omitempty"`
+	VolumeAttachments []VolumeAttachment `json:"volumeAttachments,omitempty"`
+}
+
+// VolumeAttachment - a volume attached to the profile
+type VolumeAttachment struct {
+	ID           int           `json:"id,omitempty"`
+	VolumeURI    string        `json:"volumeUri,omitempty"`
+	LUNType      string        `json:"lunType,omitempty"`
+	LUN          string        `json:"lun,omitempty"`
+	IsBootVolume bool          `json:"isBootVolume,omitempty"`
+	StoragePaths []StoragePath `json:"storagePaths,omitempty"`
+}
+
+// StoragePath - the connection a volume is reached through
+type StoragePath struct {
+	ConnectionID int  `json:"connectionId"`
+	IsEnabled    bool `json:"isEnabled"`
+}
+
+// GetProfileSpec - read the named profile with all its sections
+func GetProfileSpec(c *ov.OVClient, name string) (ServerProfileSpec, error) {
+	var spec ServerProfileSpec
+	uri, err := findURIByName(c, serverProfilesURI, name)
+	if err != nil {
+		return spec, err
+	}
+	if uri == "" {
+		return spec, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	resource, err := getResourceMap(c, uri)
+	if err != nil {
+		return spec, err
+	}
+	normalizeFields(resource, profileFieldAliases)
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return spec, err
+	}
+	err = json.Unmarshal(data, &spec)
+	return spec, err
+}
+
+// SubmitProfileSpec - create a server profile with its sections and block
+// until the appliance has applied it, as SubmitNewProfile
+func SubmitProfileSpec(c *ov.OVClient, spec ServerProfileSpec, force ...ForceOption) error {
+	if spec.Type == "" {
+		spec.Type = profileType(c.APIVersion)
+	}
+	raw, err := specMap(spec)
+	if err != nil {
+		return err
+	}
+	versionFields(raw, profileFieldAliases, c.APIVersion)
+	return submitProfile(c, raw, force, defaultProgress())
+}
+
+// specMap - the raw attributes of a profile spec
+func specMap(spec ServerProfileSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	err = json.Unmarshal(data, &raw)
+	return raw, err
+}
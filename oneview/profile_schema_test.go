@@ -0,0 +1,41 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerProfileSpec(t *testing.T) {
+	data := []byte(`{
+		"name": "docker1",
+		"eTag": "7",
+		"connections": [{"id": 1, "name": "public", "functionType": "Ethernet", "portId": "Flb 1:1-a",
+			"mac": "AA:BB:CC:00:00:01", "boot": {"priority": "Primary", "bootVlanId": 20}}],
+		"boot": {"manageBoot": true, "order": ["PXE", "HardDisk"]},
+		"bootMode": {"manageMode": true, "mode": "UEFIOptimized", "pxeBootPolicy": "IPv4"},
+		"bios": {"manageBios": true, "overriddenSettings": [{"id": "PowerRegulator", "value": "StaticHighPerf"}]},
+		"localStorage": {"controllers": [{"deviceSlot": "Embedded", "mode": "RAID", "initialize": true,
+			"logicalDrives": [{"name": "os", "raidLevel": "RAID1", "bootable": true, "numPhysicalDrives": 2}]}]},
+		"sanStorage": {"manageSanStorage": true, "hostOSType": "RHE Linux (5.x, 6.x)",
+			"volumeAttachments": [{"id": 1, "volumeUri": "/rest/storage-volumes/V", "lunType": "Auto",
+				"storagePaths": [{"connectionId": 2, "isEnabled": true}]}]}
+	}`)
+	var spec ServerProfileSpec
+	assert.NoError(t, json.Unmarshal(data, &spec))
+	assert.Equal(t, "Flb 1:1-a", spec.Connections[0].PortID)
+	assert.Equal(t, 20, spec.Connections[0].Boot.BootVlanID)
+	assert.Equal(t, []string{"PXE", "HardDisk"}, spec.Boot.Order)
+	assert.Equal(t, "UEFIOptimized", spec.BootMode.Mode)
+	assert.Equal(t, BiosSetting{"PowerRegulator", "StaticHighPerf"}, spec.Bios.OverriddenSettings[0])
+	assert.Equal(t, "RAID1", spec.LocalStorage.Controllers[0].LogicalDrives[0].RaidLevel)
+	assert.Equal(t, StoragePath{2, true}, spec.SanStorage.VolumeAttachments[0].StoragePaths[0])
+
+	raw, err := specMap(ServerProfileSpec{Name: "docker2", BootMode: &ProfileBootMode{ManageMode: true, Mode: "BIOS"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":     "docker2",
+		"bootMode": map[string]interface{}{"manageMode": true, "mode": "BIOS"},
+	}, raw)
+}
@@ -0,0 +1,97 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// ErrTemplateNotFound - no server profile template has the name
+var ErrTemplateNotFound = errors.New("Server profile template not found")
+
+// ServerProfileTemplate - a server profile template, profiles made from it
+// follow it for compliance.  Profiles are made from the raw template, see
+// CreateProfileFromTemplate, so no section the driver does not know is lost.
+type ServerProfileTemplate struct {
+	Type                     string              `json:"type,omitempty"`
+	URI                      string              `json:"uri,omitempty"`
+	ETag                     string              `json:"eTag,omitempty"`
+	Name                     string              `json:"name,omitempty"`
+	Description              string              `json:"description,omitempty"`
+	ServerProfileDescription string              `json:"serverProfileDescription,omitempty"`
+	ServerHardwareTypeURI    string              `json:"serverHardwareTypeUri,omitempty"`
+	EnclosureGroupURI        string              `json:"enclosureGroupUri,omitempty"`
+	AffinityType             string              `json:"affinity,omitempty"`
+	HideUnusedFlexNics       *bool               `json:"hideUnusedFlexNics,omitempty"`
+	Status                   ResourceStatus      `json:"status,omitempty"`
+	Connections              []ProfileConnection `json:"connections,omitempty"`
+	ConnectionSettings       *ConnectionSettings `json:"connectionSettings,omitempty"`
+	Boot                     *ProfileBoot        `json:"boot,omitempty"`
+	BootMode                 *ProfileBootMode    `json:"bootMode,omitempty"`
+	Bios                     *ProfileBios        `json:"bios,omitempty"`
+	LocalStorage             *LocalStorage       `json:"localStorage,omitempty"`
+	SanStorage               *SanStorage         `json:"sanStorage,omitempty"`
+}
+
+// ConnectionSettings - the connections of a template from api version 600,
+// earlier versions list them in connections
+type ConnectionSettings struct {
+	ManageConnections bool                `json:"manageConnections"`
+	Connections       []ProfileConnection `json:"connections,omitempty"`
+}
+
+// TemplateConnections - the connections of the template whichever way the
+// api version lists them
+func (t ServerProfileTemplate) TemplateConnections() []ProfileConnection {
+	if t.ConnectionSettings != nil {
+		return t.ConnectionSettings.Connections
+	}
+	return t.Connections
+}
+
+// ListServerProfileTemplates - the server profile templates, in opts.Sort order
+func ListServerProfileTemplates(c *ov.OVClient, opts ListOptions) ([]ServerProfileTemplate, error) {
+	var list []ServerProfileTemplate
+	err := listOrdered(c, serverProfileTemplatesURI, opts, func(members json.RawMessage) error {
+		var page []ServerProfileTemplate
+		if err := decodeVersioned(members, &page, profileFieldAliases); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// CreateServerProfileTemplate - create a server profile template and wait
+// for the task
+func CreateServerProfileTemplate(c *ov.OVClient, t ServerProfileTemplate) error {
+	if t.Name == "" {
+		return errors.New("server profile template needs a name")
+	}
+	body, err := encodeVersioned(t, profileFieldAliases, c.APIVersion)
+	if err != nil {
+		return err
+	}
+	data, err := ovCall(c, rest.POST, serverProfileTemplatesURI, body)
+	if err != nil {
+		return err
+	}
+	return waitForTaskResponse(c, data)
+}
+
+// DeleteServerProfileTemplate - delete the named server profile template,
+// the appliance refuses while profiles still use it
+func DeleteServerProfileTemplate(c *ov.OVClient, name string) error {
+	uri, err := findURIByName(c, serverProfileTemplatesURI, name)
+	if err != nil {
+		return err
+	}
+	if uri == "" {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return deleteResource(c, uri)
+}
@@ -0,0 +1,17 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateConnections(t *testing.T) {
+	var old, current ServerProfileTemplate
+	assert.NoError(t, json.Unmarshal([]byte(`{"name": "t", "connections": [{"id": 1, "name": "public"}]}`), &old))
+	assert.NoError(t, json.Unmarshal([]byte(`{"name": "t", "connectionSettings": {"manageConnections": true,
+		"connections": [{"id": 1, "name": "public"}, {"id": 2, "name": "data"}]}}`), &current))
+	assert.Equal(t, []ProfileConnection{{ID: 1, Name: "public"}}, old.TemplateConnections())
+	assert.Len(t, current.TemplateConnections(), 2)
+}
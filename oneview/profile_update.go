@@ -0,0 +1,35 @@
+package oneview
+
+import (
+	"github.com/docker/machine/libmachine/log"
+)
+
+// profileChange - change a raw profile in place, returns true when something
+// was changed
+type profileChange func(profile map[string]interface{}) (bool, error)
+
+// updateProfile - apply changes to the machine profile with a single update,
+// nothing is sent when none of the changes modify the profile.  Appliances
+// taking json patch only get the changed attributes.
+func (d *Driver) updateProfile(changes ...profileChange) error {
+	uri := d.Profile.URI.String()
+	profile, err := getResourceMap(d.ClientOV, uri)
+	if err != nil {
+		return err
+	}
+	before := copyResourceMap(profile)
+	changed := false
+	for _, change := range changes {
+		c, err := change(profile)
+		if err != nil {
+			return err
+		}
+		changed = changed || c
+	}
+	if !changed {
+		log.Debugf("profile %s already has the requested settings", d.MachineName)
+		return nil
+	}
+	log.Infof("Updating profile settings for %s...", d.MachineName)
+	return patchResource(d.ClientOV, withForce(uri, d.ProfileForce), before, profile)
+}
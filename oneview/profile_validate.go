@@ -0,0 +1,174 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// ErrInvalidProfile - a profile the appliance would refuse with a 400
+var ErrInvalidProfile = errors.New("Invalid server profile")
+
+// profileTypes - the profile type string of each api version, an api
+// version takes the type of the highest version at or below it
+var profileTypes = map[int]string{
+	200:  "ServerProfileV5",
+	300:  "ServerProfileV6",
+	500:  "ServerProfileV7",
+	600:  "ServerProfileV8",
+	800:  "ServerProfileV9",
+	1000: "ServerProfileV10",
+	1200: "ServerProfileV11",
+}
+
+// serverHardwareTypeAPIVersion - first api version needing the server
+// hardware type on every profile
+const serverHardwareTypeAPIVersion = 200
+
+// profileType - the type string the api version takes, empty before 200
+func profileType(apiVersion int) string {
+	return typeForVersion(profileTypes, apiVersion)
+}
+
+// typeForVersion - the type string of the highest api version in types at
+// or below apiVersion, empty when there is none
+func typeForVersion(types map[int]string, apiVersion int) string {
+	best := 0
+	for v := range types {
+		if v <= apiVersion && v > best {
+			best = v
+		}
+	}
+	return types[best]
+}
+
+// FieldError - one attribute of a profile that is wrong
+type FieldError struct {
+	Field   string
+	Problem string
+}
+
+// ProfileValidationError - every attribute of a profile that is wrong
+type ProfileValidationError struct {
+	Profile string
+	Fields  []FieldError
+}
+
+// Error - all the problems on one line
+func (e *ProfileValidationError) Error() string {
+	problems := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		problems[i] = f.Field + " " + f.Problem
+	}
+	if e.Profile == "" {
+		return fmt.Sprintf("%s: %s", ErrInvalidProfile, strings.Join(problems, "; "))
+	}
+	return fmt.Sprintf("%s %s: %s", ErrInvalidProfile, e.Profile, strings.Join(problems, "; "))
+}
+
+// Unwrap - errors.Is(err, ErrInvalidProfile) holds
+func (e *ProfileValidationError) Unwrap() error {
+	return ErrInvalidProfile
+}
+
+// Validate - check the attributes the appliance needs for the api version
+// before the profile is sent, returns a *ProfileValidationError with every
+// problem rather than the first the appliance finds.  A profile in an
+// enclosure bay is for a blade and needs its enclosure group, a rack server
+// profile has neither.
+func (s ServerProfileSpec) Validate(apiVersion int) error {
+	e := &ProfileValidationError{Profile: s.Name}
+	add := func(field, format string, args ...interface{}) {
+		e.Fields = append(e.Fields, FieldError{Field: field, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Name == "" {
+		add("name", "is required")
+	}
+	if want := profileType(apiVersion); want != "" && s.Type != want {
+		if s.Type == "" {
+			add("type", "is required, %s for api version %d", want, apiVersion)
+		} else {
+			add("type", "is %s, api version %d takes %s", s.Type, apiVersion, want)
+		}
+	}
+	if apiVersion >= serverHardwareTypeAPIVersion && s.ServerHardwareTypeURI == "" {
+		add("serverHardwareTypeUri", "is required from api version %d", serverHardwareTypeAPIVersion)
+	}
+	switch {
+	case s.EnclosureBay > 0 && s.EnclosureURI == "":
+		add("enclosureUri", "is required with enclosureBay")
+	case s.EnclosureURI != "" && s.EnclosureBay == 0:
+		add("enclosureBay", "is required with enclosureUri")
+	}
+	if (s.EnclosureURI != "" || s.EnclosureBay > 0) && s.EnclosureGroupURI == "" {
+		add("enclosureGroupUri", "is required for a blade in an enclosure bay")
+	}
+
+	ids := map[int]bool{}
+	primary := map[string]int{}
+	for i, c := range s.Connections {
+		field := fmt.Sprintf("connections[%d]", i)
+		if c.ID > 0 {
+			if ids[c.ID] {
+				add(field+".id", "%d is used by another connection", c.ID)
+			}
+			ids[c.ID] = true
+		}
+		if c.FunctionType == "" {
+			add(field+".functionType", "is required")
+		}
+		if c.NetworkURI == "" {
+			add(field+".networkUri", "is required")
+		}
+		if c.Boot != nil && c.Boot.Priority == BootPrimary {
+			primary[c.FunctionType]++
+		}
+	}
+	functionTypes := make([]string, 0, len(primary))
+	for ft := range primary {
+		functionTypes = append(functionTypes, ft)
+	}
+	sort.Strings(functionTypes)
+	for _, ft := range functionTypes {
+		if primary[ft] > 1 {
+			add("connections", "has %d primary boot %s connections, only one may be primary", primary[ft], ft)
+		}
+	}
+
+	if len(e.Fields) > 0 {
+		return e
+	}
+	return nil
+}
+
+// ValidateProfile - Validate for the top level attributes of an ov.ServerProfile
+func ValidateProfile(profile ov.ServerProfile, apiVersion int) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	var spec ServerProfileSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+	return spec.Validate(apiVersion)
+}
+
+// validateProfileMap - Validate for a raw profile body, every profile the
+// driver creates goes through this before it is sent
+func validateProfileMap(profile map[string]interface{}, apiVersion int) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	var spec ServerProfileSpec
+	if err := decodeVersioned(data, &spec, profileFieldAliases); err != nil {
+		return err
+	}
+	return spec.Validate(apiVersion)
+}
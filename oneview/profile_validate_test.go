@@ -0,0 +1,78 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileType(t *testing.T) {
+	assert.Equal(t, "", profileType(120))
+	assert.Equal(t, "ServerProfileV5", profileType(201))
+	assert.Equal(t, "ServerProfileV8", profileType(600))
+	assert.Equal(t, "ServerProfileV11", profileType(2000))
+}
+
+func TestValidateProfileSpec(t *testing.T) {
+	blade := ServerProfileSpec{
+		Type:                  "ServerProfileV8",
+		Name:                  "docker-1",
+		ServerHardwareTypeURI: "/rest/server-hardware-types/1",
+		EnclosureGroupURI:     "/rest/enclosure-groups/1",
+		EnclosureURI:          "/rest/enclosures/1",
+		EnclosureBay:          3,
+		Connections: []ProfileConnection{
+			{ID: 1, FunctionType: "Ethernet", NetworkURI: "/rest/ethernet-networks/1", Boot: &ConnectionBoot{Priority: BootPrimary}},
+			{ID: 2, FunctionType: "Ethernet", NetworkURI: "/rest/ethernet-networks/2", Boot: &ConnectionBoot{Priority: BootSecondary}},
+		},
+	}
+	assert.NoError(t, blade.Validate(600))
+
+	rack := ServerProfileSpec{Type: "ServerProfileV5", Name: "docker-2", ServerHardwareTypeURI: "/rest/server-hardware-types/2"}
+	assert.NoError(t, rack.Validate(201))
+
+	bad := blade
+	bad.Type = ""
+	bad.EnclosureGroupURI = ""
+	bad.ServerHardwareTypeURI = ""
+	bad.Connections = []ProfileConnection{
+		{ID: 1, FunctionType: "Ethernet", Boot: &ConnectionBoot{Priority: BootPrimary}},
+		{ID: 1, FunctionType: "Ethernet", NetworkURI: "/rest/ethernet-networks/2", Boot: &ConnectionBoot{Priority: BootPrimary}},
+	}
+	err := bad.Validate(600)
+	assert.True(t, errors.Is(err, ErrInvalidProfile))
+	var verr *ProfileValidationError
+	assert.True(t, errors.As(err, &verr))
+	var fields []string
+	for _, f := range verr.Fields {
+		fields = append(fields, f.Field)
+	}
+	assert.Equal(t, []string{"type", "serverHardwareTypeUri", "enclosureGroupUri", "connections[0].networkUri", "connections[1].id", "connections"}, fields)
+	assert.Equal(t, CategoryUser, CategoryOf(err))
+
+	bad = rack
+	bad.Type = "ServerProfileV5"
+	bad.EnclosureBay = 2
+	assert.EqualError(t, bad.Validate(600), "Invalid server profile docker-2: type is ServerProfileV5, api version 600 takes ServerProfileV8; enclosureUri is required with enclosureBay; enclosureGroupUri is required for a blade in an enclosure bay")
+}
+
+func TestValidateProfile(t *testing.T) {
+	err := ValidateProfile(ov.ServerProfile{Name: "docker-1"}, 120)
+	assert.NoError(t, err)
+	err = ValidateProfile(ov.ServerProfile{Type: "ServerProfileV5"}, 201)
+	assert.EqualError(t, err, "Invalid server profile: name is required; serverHardwareTypeUri is required from api version 200")
+}
+
+func TestSubmitProfileValidates(t *testing.T) {
+	// refused before anything is sent to the appliance
+	c, done := fakeOV(t)
+	defer done()
+	err := submitProfile(c, map[string]interface{}{
+		"name":        "docker-1",
+		"connections": []interface{}{map[string]interface{}{"id": 1, "functionType": "Ethernet"}},
+	}, nil, defaultProgress())
+	assert.True(t, errors.Is(err, ErrInvalidProfile))
+	assert.Contains(t, err.Error(), "networkUri")
+}
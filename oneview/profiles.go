@@ -0,0 +1,129 @@
+package oneview
+
+import (
+	"encoding/json"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// ServerProfileSummary - the profile attributes used for inventory
+type ServerProfileSummary struct {
+	URI               string         `json:"uri,omitempty"`
+	Name              string         `json:"name,omitempty"`
+	Description       string         `json:"description,omitempty"`
+	SerialNumber      string         `json:"serialNumber,omitempty"`
+	UUID              string         `json:"uuid,omitempty"`
+	Status            ResourceStatus `json:"status,omitempty"`
+	State             ProfileState   `json:"state,omitempty"`
+	ServerHardwareURI string         `json:"serverHardwareUri,omitempty"`
+	TemplateURI       string         `json:"serverProfileTemplateUri,omitempty"`
+	Created           Timestamp      `json:"created,omitempty"`
+	Modified          Timestamp      `json:"modified,omitempty"`
+}
+
+// ExpandedProfile - a profile with the hardware it is assigned to
+type ExpandedProfile struct {
+	ServerProfileSummary
+	ServerHardware *ServerHardwareInventory `json:"serverHardware,omitempty"`
+}
+
+// ListProfiles - list profile summaries across every page, in opts.Sort order
+func ListProfiles(c *ov.OVClient, opts ListOptions) ([]ServerProfileSummary, error) {
+	var list []ServerProfileSummary
+	err := listOrdered(c, serverProfilesURI, opts, func(members json.RawMessage) error {
+		var page []ServerProfileSummary
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// GetProfiles - every profile matching filter in sort order, from all the
+// pages, empty filter or sort leave them out of the query
+func GetProfiles(c *ov.OVClient, filter, sort string) (ov.ServerProfileList, error) {
+	var list ov.ServerProfileList
+	err := StreamProfiles(c, filter, sort, func(p ov.ServerProfile) error {
+		list.Members = append(list.Members, p)
+		return nil
+	})
+	list.Count = len(list.Members)
+	list.Total = len(list.Members)
+	return list, err
+}
+
+// StreamProfiles - GetProfiles handing each profile to fn as its page comes
+// in, rather than holding them all.  An error from fn stops the listing and
+// is returned.
+func StreamProfiles(c *ov.OVClient, filter, sort string, fn func(ov.ServerProfile) error) error {
+	var opts ListOptions
+	if filter != "" {
+		opts.Filters = []string{filter}
+	}
+	opts.Sort = sort
+	return listMembers(c, serverProfilesURI, opts.query(), func(members json.RawMessage) error {
+		var page []ov.ServerProfile
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		for _, p := range page {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListProfilesExpanded - list profiles with their server hardware using the
+// expand view, in name order.  Appliances that do not expand profiles get their hardware
+// filled in from a single hardware list, rather than one get per profile.
+func ListProfilesExpanded(c *ov.OVClient, filters []string) ([]ExpandedProfile, error) {
+	var list []ExpandedProfile
+	opts := ListOptions{Filters: filters, View: ViewExpand}
+	err := listOrdered(c, serverProfilesURI, opts, func(members json.RawMessage) error {
+		var page []ExpandedProfile
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	missing := false
+	for _, p := range list {
+		if p.ServerHardwareURI != "" && p.ServerHardware == nil {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return list, nil
+	}
+	log.Debugf("appliance did not expand profile hardware, listing hardware")
+	hardware, err := listHardwareInventory(c, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	expandProfiles(list, hardware)
+	return list, nil
+}
+
+// expandProfiles - attach hardware to the profiles it is assigned to
+func expandProfiles(profiles []ExpandedProfile, hardware []ServerHardwareInventory) {
+	byURI := make(map[string]*ServerHardwareInventory, len(hardware))
+	for i := range hardware {
+		byURI[hardware[i].URI] = &hardware[i]
+	}
+	for i := range profiles {
+		if profiles[i].ServerHardware == nil {
+			profiles[i].ServerHardware = byURI[profiles[i].ServerHardwareURI]
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOptionsQuery(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{}, ListOptions{}.query())
+	q := ListOptions{Filters: []string{"name='a'"}, Sort: "name:ascending", View: ViewExpand}.query()
+	assert.Equal(t, []string{"name='a'"}, q["filter"])
+	assert.Equal(t, "name:ascending", q["sort"])
+	assert.Equal(t, "expand", q["view"])
+}
+
+func TestExpandedProfileJSON(t *testing.T) {
+	var p ExpandedProfile
+	data := `{"name":"docker-1","serverHardwareUri":"/rest/server-hardware/1",
+		"serverHardware":{"uri":"/rest/server-hardware/1","name":"enc1, bay 1","memoryMb":65536}}`
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+	assert.Equal(t, "docker-1", p.Name)
+	assert.Equal(t, 64, p.ServerHardware.MemoryGb())
+}
+
+func TestExpandProfiles(t *testing.T) {
+	profiles := []ExpandedProfile{
+		{ServerProfileSummary: ServerProfileSummary{Name: "a", ServerHardwareURI: "/rest/server-hardware/1"}},
+		{ServerProfileSummary: ServerProfileSummary{Name: "b"}},
+	}
+	expandProfiles(profiles, []ServerHardwareInventory{{URI: "/rest/server-hardware/1", Name: "enc1, bay 1"}})
+	assert.Equal(t, "enc1, bay 1", profiles[0].ServerHardware.Name)
+	assert.Nil(t, profiles[1].ServerHardware)
+}
+
+func TestOrderMembers(t *testing.T) {
+	raw := func(s ...string) []json.RawMessage {
+		var out []json.RawMessage
+		for _, m := range s {
+			out = append(out, json.RawMessage(m))
+		}
+		return out
+	}
+	// the second page repeats a member of the first
+	members := raw(
+		`{"uri":"/rest/x/3","name":"b","memoryMb":2}`,
+		`{"uri":"/rest/x/1","name":"c","memoryMb":1}`,
+		`{"uri":"/rest/x/2","name":"b","memoryMb":3}`,
+		`{"uri":"/rest/x/1","name":"c","memoryMb":1}`,
+		`{"uri":"/rest/x/4","memoryMb":4}`,
+	)
+	uris := func(ms []json.RawMessage) []string {
+		var out []string
+		for _, m := range ms {
+			var v struct {
+				URI string `json:"uri"`
+			}
+			json.Unmarshal(m, &v)
+			out = append(out, v.URI)
+		}
+		return out
+	}
+
+	byName, err := orderMembers(members, ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/rest/x/4", "/rest/x/2", "/rest/x/3", "/rest/x/1"}, uris(byName))
+
+	byMemory, err := orderMembers(members, ListOptions{Sort: "memoryMb:descending"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/rest/x/4", "/rest/x/2", "/rest/x/3", "/rest/x/1"}, uris(byMemory))
+
+	byURI, err := orderMembers(members, ListOptions{Sort: "uri:ascending"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/rest/x/1", "/rest/x/2", "/rest/x/3", "/rest/x/4"}, uris(byURI))
+}
+
+func TestListOptionsFields(t *testing.T) {
+	assert.Equal(t, []string{"serialNumber", "uri", "name"}, ListOptions{Fields: []string{"serialNumber"}}.fields())
+	assert.Equal(t, []string{"name", "uri", "status"}, ListOptions{Fields: []string{"name", "uri"}, Sort: "status:descending"}.fields())
+}
+
+func TestProjectMember(t *testing.T) {
+	member := json.RawMessage(`{"uri": "/rest/server-profiles/1", "name": "a", "serialNumber": "S1", "connections": [{"id": 1}]}`)
+	projected, err := projectMember(member, []string{"name", "serialNumber", "uri", "status"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"uri": "/rest/server-profiles/1", "name": "a", "serialNumber": "S1"}`, string(projected))
+	whole, err := projectMember(member, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, member, whole)
+}
+
+func TestGetProfilesPages(t *testing.T) {
+	c, done := fakeOV(t,
+		fakeCall{method: "GET", uri: serverProfilesURI,
+			query: map[string]interface{}{"filter": []string{"name='docker*'"}, "sort": "name:ascending"},
+			data:  `{"members":[{"name":"docker-1"},{"name":"docker-2"}],"nextPageUri":"/rest/server-profiles?start=2&count=2"}`},
+		fakeCall{method: "GET", uri: serverProfilesURI,
+			query: map[string]interface{}{"start": []string{"2"}, "count": []string{"2"}},
+			data:  `{"members":[{"name":"docker-3"}]}`})
+	defer done()
+	list, err := GetProfiles(c, "name='docker*'", "name:ascending")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Equal(t, "docker-3", list.Members[2].Name)
+}
+
+func TestStreamProfilesStops(t *testing.T) {
+	stop := errors.New("stop")
+	c, done := fakeOV(t, fakeCall{method: "GET", uri: serverProfilesURI,
+		data: `{"members":[{"name":"docker-1"},{"name":"docker-2"}],"nextPageUri":"/rest/server-profiles?start=2&count=2"}`})
+	defer done()
+	var names []string
+	err := StreamProfiles(c, "", "", func(p ov.ServerProfile) error {
+		names = append(names, p.Name)
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []string{"docker-1"}, names, "no more profiles or pages after an error")
+}
+
+func TestGetProfilesError(t *testing.T) {
+	failed := errors.New("Response Status: 500 Internal Server Error")
+	c, done := fakeOV(t,
+		fakeCall{method: "GET", uri: serverProfilesURI, data: `{"members":[{"name":"docker-1"}],"nextPageUri":"/rest/server-profiles?start=1"}`},
+		fakeCall{method: "GET", uri: serverProfilesURI, err: failed})
+	defer done()
+	list, err := GetProfiles(c, "", "")
+	assert.True(t, errors.Is(err, failed))
+	assert.Len(t, list.Members, 1, "profiles from earlier pages are still returned")
+}
@@ -0,0 +1,208 @@
+package oneview
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// progressLogInterval - least time between two logged updates for the same id
+var progressLogInterval = 10 * time.Second
+
+// logProgress - ProgressSink writing to the driver log, with transfer
+// rate and time remaining worked out from the updates seen for each id
+type logProgress struct {
+	mu      sync.Mutex
+	started map[string]progressStart
+	logged  map[string]time.Time
+	now     func() time.Time
+}
+
+// progressStart - first update seen for an id, a resumed transfer starts
+// part of the way through
+type progressStart struct {
+	at   time.Time
+	from int64
+}
+
+// newLogProgress - progress output for the driver log
+func newLogProgress() *logProgress {
+	return &logProgress{
+		started: make(map[string]progressStart),
+		logged:  make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// ProgressSink - where task waits, uploads, downloads and driver stages
+// report progress.  It has the method of docker's progress.Output so the
+// progress readers and writers of docker take one, and progress.ChanOutput
+// is a sink for a channel.  Updates come from many goroutines at once, ie;
+// WaitForTasks, so sinks must be safe for that.
+type ProgressSink interface {
+	WriteProgress(p progress.Progress) error
+}
+
+var (
+	progressMu sync.Mutex
+	// progressSink - where updates go unless a caller gives its own sink
+	progressSink ProgressSink = newLogProgress()
+)
+
+// SetProgressSink - send progress updates to s rather than the driver log,
+// nil goes back to the log.  Events still see every update when they are on.
+func SetProgressSink(s ProgressSink) {
+	if s == nil {
+		s = newLogProgress()
+	}
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progressSink = s
+}
+
+// currentProgressSink - the sink set with SetProgressSink
+func currentProgressSink() ProgressSink {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	return progressSink
+}
+
+// defaultProgress - where task waits and transfers report progress, through
+// the event stream when it is open
+func defaultProgress() ProgressSink {
+	s := currentProgressSink()
+	if eventsOpen() {
+		return &eventProgress{next: s}
+	}
+	return s
+}
+
+// NewLogProgressSink - a sink writing updates to the docker-machine log,
+// throttled so each id logs at most every progressLogInterval
+func NewLogProgressSink() ProgressSink {
+	return newLogProgress()
+}
+
+// ProgressFunc - a func taking progress updates as a ProgressSink, ie;
+// for SubmitNewProfileWithProgress.  The func is called from many goroutines
+// when tasks are waited on together.
+type ProgressFunc func(p progress.Progress) error
+
+// WriteProgress - call the func
+func (f ProgressFunc) WriteProgress(p progress.Progress) error {
+	return f(p)
+}
+
+// WriteProgress - log the update, throttled per id
+func (l *logProgress) WriteProgress(p progress.Progress) error {
+	if p.Message != "" {
+		log.Infof("%s: %s", p.ID, p.Message)
+		return nil
+	}
+	if p.Action == "" && p.Aux != nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	start, ok := l.started[p.ID]
+	if !ok {
+		start = progressStart{at: now, from: p.Current}
+		l.started[p.ID] = start
+	}
+	done := p.LastUpdate || (p.Total > 0 && p.Current >= p.Total)
+	if last, ok := l.logged[p.ID]; ok && !done && now.Sub(last) < progressLogInterval {
+		return nil
+	}
+	l.logged[p.ID] = now
+	log.Infof("%s: %s", p.ID, formatProgress(p, p.Current-start.from, now.Sub(start.at)))
+	if done {
+		delete(l.started, p.ID)
+		delete(l.logged, p.ID)
+	}
+	return nil
+}
+
+// formatProgress - ie; Downloading 512.0MB of 2.0GB (25%), 10.5MB/s, 2m30s left
+// where the rate is worked out from the bytes moved during elapsed
+func formatProgress(p progress.Progress, moved int64, elapsed time.Duration) string {
+	if p.Total <= 0 {
+		if p.Current > 0 {
+			return fmt.Sprintf("%s %s", p.Action, formatBytes(p.Current))
+		}
+		return p.Action
+	}
+	pct := p.Current * 100 / p.Total
+	s := fmt.Sprintf("%s %s of %s (%d%%)", p.Action, formatBytes(p.Current), formatBytes(p.Total), pct)
+	if elapsed <= 0 || moved <= 0 {
+		return s
+	}
+	rate := float64(moved) / elapsed.Seconds()
+	s += fmt.Sprintf(", %s/s", formatBytes(int64(rate)))
+	if remaining := p.Total - p.Current; remaining > 0 {
+		eta := time.Duration(float64(remaining)/rate) * time.Second
+		s += fmt.Sprintf(", %s left", eta)
+	}
+	return s
+}
+
+// formatBytes - human readable size
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// progressWriter - io.Writer reporting the bytes written through it
+type progressWriter struct {
+	w          io.Writer
+	out        progress.Output
+	id         string
+	action     string
+	current    int64
+	total      int64
+	lastUpdate int64
+}
+
+// newProgressWriter - wrap w, reporting progress towards total bytes, which
+// starts at current when resuming a transfer
+func newProgressWriter(w io.Writer, out progress.Output, current, total int64, id, action string) *progressWriter {
+	return &progressWriter{w: w, out: out, id: id, action: action, current: current, total: total, lastUpdate: current}
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.current += int64(n)
+	updateEvery := int64(1024 * 512)
+	if p.total > 0 {
+		if increment := p.total / 100; increment < updateEvery {
+			updateEvery = increment
+		}
+	}
+	if p.current-p.lastUpdate > updateEvery || err != nil {
+		p.update(false)
+		p.lastUpdate = p.current
+	}
+	return n, err
+}
+
+// update - report the current position
+func (p *progressWriter) update(last bool) {
+	p.out.WriteProgress(progress.Progress{ID: p.id, Action: p.action, Current: p.current, Total: p.total, LastUpdate: last})
+}
+
+// newProgressReader - wrap r, reporting the bytes read towards size
+func newProgressReader(r io.ReadCloser, out progress.Output, size int64, id, action string) io.ReadCloser {
+	return progress.NewProgressReader(r, out, size, id, action)
+}
@@ -0,0 +1,72 @@
+package oneview
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512B", formatBytes(512))
+	assert.Equal(t, "1.5KB", formatBytes(1536))
+	assert.Equal(t, "2.0GB", formatBytes(2*1024*1024*1024))
+}
+
+func TestFormatProgress(t *testing.T) {
+	p := progress.Progress{Action: "Uploading", Current: 512 * 1024 * 1024, Total: 2048 * 1024 * 1024}
+	assert.Equal(t, "Uploading 512.0MB of 2.0GB (25%), 10.0MB/s, 2m33s left",
+		formatProgress(p, 512*1024*1024, 51200*time.Millisecond))
+	assert.Equal(t, "Uploading 512.0MB of 2.0GB (25%)", formatProgress(p, 0, 0))
+	assert.Equal(t, "Running 50%", formatProgress(progress.Progress{Action: "Running 50%"}, 0, time.Second))
+}
+
+func TestProgressWriter(t *testing.T) {
+	updates := make(chan progress.Progress, 1000)
+	var buf bytes.Buffer
+	pw := newProgressWriter(&buf, progress.ChanOutput(updates), 100, 1100, "dump", "Downloading")
+	for i := 0; i < 10; i++ {
+		pw.Write(make([]byte, 100))
+	}
+	pw.update(true)
+	close(updates)
+
+	var last progress.Progress
+	n := 0
+	for p := range updates {
+		last = p
+		n++
+	}
+	assert.True(t, n > 1)
+	assert.Equal(t, int64(1100), last.Current)
+	assert.True(t, last.LastUpdate)
+	assert.Equal(t, 1000, buf.Len())
+}
+
+func TestProgressFunc(t *testing.T) {
+	var got []progress.Progress
+	out := ProgressFunc(func(p progress.Progress) error {
+		got = append(got, p)
+		return nil
+	})
+	assert.NoError(t, out.WriteProgress(progress.Progress{ID: "Create", Action: "Running 40%"}))
+	assert.Equal(t, []progress.Progress{{ID: "Create", Action: "Running 40%"}}, got)
+}
+
+func TestSetProgressSink(t *testing.T) {
+	updates := make(chan progress.Progress, 10)
+	SetProgressSink(progress.ChanOutput(updates))
+	defer SetProgressSink(nil)
+
+	defaultProgress().WriteProgress(progress.Progress{ID: "Create profile docker1", Action: "Running 40%"})
+	runStage("docker1", "allocate", func() error { return nil })
+	close(updates)
+
+	var got []string
+	for p := range updates {
+		got = append(got, p.ID+": "+p.Action)
+	}
+	assert.Equal(t, []string{"Create profile docker1: Running 40%", "docker1: allocate", "docker1: allocate done"}, got)
+}
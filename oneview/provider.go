@@ -0,0 +1,106 @@
+package oneview
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// BareMetalProvider - the backend specific part of managing a machine, the
+// driver runs the steps common to every composable infrastructure around
+// it.  OneView with ICsp is the only backend so far.
+type BareMetalProvider interface {
+	// Plan - work out where and how the machine gets created without
+	// changing anything, ErrPlanOnly stops the create after it
+	Plan() error
+	// Allocate - claim hardware and give it the machine's configuration
+	Allocate() error
+	// Configure - apply settings to the allocated hardware while it is off
+	Configure() error
+	// Deploy - install the os
+	Deploy() error
+	// Address - the address ssh and docker reach the machine on
+	Address() (string, error)
+	// Verify - checks once the machine is reachable, they only warn
+	Verify()
+	// Locate - find the hardware of an existing machine
+	Locate() error
+	// PowerOn - power the hardware on and check the os is ready
+	PowerOn() error
+	// PowerOff - power the hardware off
+	PowerOff() error
+	// Release - give back the hardware and everything the machine holds
+	Release() error
+	// Close - end the sessions with the backend
+	Close()
+}
+
+// machineHost - the backend independent steps the create pipeline runs
+type machineHost interface {
+	GetMachineName() string
+	createKeyPair() error
+	installSSHKey() error
+	setIPAddress(ip string)
+}
+
+// runCreate - the create pipeline
+func runCreate(p BareMetalProvider, h machineHost) error {
+	stage := func(name string, fn func() error) error {
+		return runStage(h.GetMachineName(), name, fn)
+	}
+	if err := stage("plan", p.Plan); err != nil {
+		return err
+	}
+
+	log.Infof("Generating SSH keys...")
+	if err := stage("keys", h.createKeyPair); err != nil {
+		return fmt.Errorf("unable to create key pair: %w", err)
+	}
+
+	if err := stage("allocate", p.Allocate); err != nil {
+		return err
+	}
+	if err := stage("configure", p.Configure); err != nil {
+		return err
+	}
+	if err := stage("deploy", p.Deploy); err != nil {
+		return err
+	}
+
+	err := stage("address", func() error {
+		ip, err := p.Address()
+		if err == nil {
+			h.setIPAddress(ip)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// use ssh to set keys, and test ssh
+	if err := stage("ssh", h.installSSHKey); err != nil {
+		return err
+	}
+	stage("verify", func() error {
+		p.Verify()
+		return nil
+	})
+	return nil
+}
+
+// backend - the provider of the driver, OneView unless one was set
+func (d *Driver) backend() BareMetalProvider {
+	if d.provider != nil {
+		return d.provider
+	}
+	if !d.usesICSP() {
+		return &httpBootProvider{oneviewProvider: oneviewProvider{d: d}}
+	}
+	return &oneviewProvider{d: d}
+}
+
+// setIPAddress - keep the address the machine was found on
+func (d *Driver) setIPAddress(ip string) {
+	d.IPAddress = ip
+}
@@ -0,0 +1,87 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProvider - records the steps run, failing the one named in fail
+type fakeProvider struct {
+	steps []string
+	fail  string
+}
+
+func (f *fakeProvider) step(name string) error {
+	f.steps = append(f.steps, name)
+	if name == f.fail {
+		return errors.New(name + " failed")
+	}
+	return nil
+}
+
+func (f *fakeProvider) Plan() error      { return f.step("plan") }
+func (f *fakeProvider) Allocate() error  { return f.step("allocate") }
+func (f *fakeProvider) Configure() error { return f.step("configure") }
+func (f *fakeProvider) Deploy() error    { return f.step("deploy") }
+func (f *fakeProvider) Address() (string, error) {
+	return "10.0.0.5", f.step("address")
+}
+func (f *fakeProvider) Verify()         { f.step("verify") }
+func (f *fakeProvider) Locate() error   { return f.step("locate") }
+func (f *fakeProvider) PowerOn() error  { return f.step("poweron") }
+func (f *fakeProvider) PowerOff() error { return f.step("poweroff") }
+func (f *fakeProvider) Release() error  { return f.step("release") }
+func (f *fakeProvider) Close()          { f.step("close") }
+
+// fakeHost - records the host steps into the provider's list
+type fakeHost struct {
+	p  *fakeProvider
+	ip string
+}
+
+func (h *fakeHost) createKeyPair() error   { return h.p.step("keys") }
+func (h *fakeHost) installSSHKey() error   { return h.p.step("ssh") }
+func (h *fakeHost) setIPAddress(ip string) { h.ip = ip }
+func (h *fakeHost) GetMachineName() string { return "docker1" }
+
+func TestRunCreate(t *testing.T) {
+	p := &fakeProvider{}
+	h := &fakeHost{p: p}
+	assert.NoError(t, runCreate(p, h))
+	assert.Equal(t, []string{"plan", "keys", "allocate", "configure", "deploy", "address", "ssh", "verify"}, p.steps)
+	assert.Equal(t, "10.0.0.5", h.ip)
+
+	p = &fakeProvider{fail: "configure"}
+	h = &fakeHost{p: p}
+	assert.EqualError(t, runCreate(p, h), "configure failed")
+	assert.Equal(t, []string{"plan", "keys", "allocate", "configure"}, p.steps)
+	assert.Equal(t, "", h.ip)
+
+	p = &fakeProvider{fail: "keys"}
+	err := runCreate(p, &fakeHost{p: p})
+	assert.EqualError(t, err, "unable to create key pair: keys failed")
+}
+
+func TestRunReImage(t *testing.T) {
+	p := &fakeProvider{}
+	h := &fakeHost{p: p}
+	assert.NoError(t, runReImage(p, h))
+	assert.Equal(t, []string{"locate", "poweroff", "deploy", "address", "ssh", "verify"}, p.steps)
+	assert.Equal(t, "10.0.0.5", h.ip)
+
+	p = &fakeProvider{fail: "deploy"}
+	h = &fakeHost{p: p}
+	assert.EqualError(t, runReImage(p, h), "deploy failed")
+	assert.Equal(t, []string{"locate", "poweroff", "deploy"}, p.steps)
+	assert.Equal(t, "", h.ip)
+}
+
+func TestBackend(t *testing.T) {
+	d := &Driver{}
+	assert.IsType(t, &oneviewProvider{}, d.backend())
+	f := &fakeProvider{}
+	d.provider = f
+	assert.Equal(t, f, d.backend())
+}
@@ -0,0 +1,107 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// rack server add errors
+var (
+	ErrRackServerImportMissingHost  = errors.New("Rack server add needs the iLO hostname")
+	ErrRackServerImportMissingLogin = errors.New("Rack server add needs the iLO username and password")
+	ErrNotRackServer                = errors.New("Only rack servers can be removed, blades go with their enclosure")
+)
+
+// RackServerImport - how to bring a DL rack server under management through
+// its iLO, blades come with their enclosure instead
+type RackServerImport struct {
+	// Hostname - ip or hostname of the iLO
+	Hostname string
+	// Username, Password - iLO credentials, OneView creates its own account
+	// on the iLO with them
+	Username string
+	Password string
+	// LicensingIntent - OneView or OneViewNoiLO, defaults to OneView
+	LicensingIntent string
+	// Monitored - only monitor the server, no profiles can be applied
+	Monitored bool
+	// Force - take the server over from another appliance managing it
+	Force bool
+}
+
+// Validate - check the add has what the appliance needs
+func (s RackServerImport) Validate() error {
+	if s.Hostname == "" {
+		return ErrRackServerImportMissingHost
+	}
+	if s.Username == "" || s.Password == "" {
+		return ErrRackServerImportMissingLogin
+	}
+	return nil
+}
+
+// body - the add server hardware request
+func (s RackServerImport) body() map[string]interface{} {
+	body := map[string]interface{}{
+		"hostname": s.Hostname,
+		"username": s.Username,
+		"password": s.Password,
+		"force":    s.Force,
+	}
+	if s.Monitored {
+		body["configurationState"] = "Monitored"
+		return body
+	}
+	licensing := s.LicensingIntent
+	if licensing == "" {
+		licensing = LicensingOneView
+	}
+	body["configurationState"] = "Managed"
+	body["licensingIntent"] = licensing
+	return body
+}
+
+// AddRackServer - add a rack server and wait for the appliance to take it
+// over, returns the uri of the new server hardware
+func AddRackServer(c *ov.OVClient, s RackServerImport) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	log.Infof("Adding rack server %s", s.Hostname)
+	data, err := ovCall(c, rest.POST, serverHardwareURI, s.body())
+	if err != nil {
+		return "", err
+	}
+	var t Task
+	if err := json.Unmarshal(data, &t); err != nil {
+		return "", err
+	}
+	if t.URI == "" {
+		return "", fmt.Errorf("appliance did not return a task to wait on")
+	}
+	t, err = waitForTaskResult(interruptCtx, c, t.URI)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Added rack server %s as %s", s.Hostname, t.AssociatedResource.ResourceName)
+	return t.AssociatedResource.ResourceURI, nil
+}
+
+// RemoveRackServer - stop managing the rack server at uri and wait for the
+// appliance to let it go, ErrNotRackServer for blades
+func RemoveRackServer(c *ov.OVClient, uri string) error {
+	h, err := GetServerHardware(c, uri)
+	if err != nil {
+		return err
+	}
+	if !h.isRackServer() {
+		return fmt.Errorf("%w: %s", ErrNotRackServer, h.Name)
+	}
+	log.Infof("Removing rack server %s", h.Name)
+	return deleteResource(c, uri)
+}
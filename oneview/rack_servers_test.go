@@ -0,0 +1,28 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRackServerImport(t *testing.T) {
+	s := RackServerImport{Hostname: "ilo1", Username: "Administrator", Password: "pw"}
+	assert.NoError(t, s.Validate())
+	assert.Equal(t, ErrRackServerImportMissingHost, RackServerImport{}.Validate())
+	assert.Equal(t, ErrRackServerImportMissingLogin, RackServerImport{Hostname: "ilo1"}.Validate())
+
+	assert.Equal(t, map[string]interface{}{
+		"hostname":           "ilo1",
+		"username":           "Administrator",
+		"password":           "pw",
+		"force":              false,
+		"configurationState": "Managed",
+		"licensingIntent":    LicensingOneView,
+	}, s.body())
+
+	s.Monitored = true
+	body := s.body()
+	assert.Equal(t, "Monitored", body["configurationState"])
+	assert.NotContains(t, body, "licensingIntent")
+}
@@ -0,0 +1,135 @@
+package oneview
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultErrorBodyLimit - bytes of an appliance error kept in messages
+	defaultErrorBodyLimit = 4096
+	redacted              = "[REDACTED]"
+)
+
+// DefaultRedactFields - field name patterns that are always redacted
+var DefaultRedactFields = []string{"password", "token", "community", "secret", "sessionid", "auth"}
+
+// ErrorBodyPolicy - how much of an appliance error body ends up in errors
+// and logs, and which fields are masked on the way
+type ErrorBodyPolicy struct {
+	// Limit - bytes kept, 0 leaves the body out and a negative limit keeps
+	// all of it
+	Limit int
+	// Fields - regular expressions matched against field names, case is
+	// ignored, values of matching fields are replaced with [REDACTED]
+	Fields []string
+
+	jsonRE  *regexp.Regexp
+	queryRE *regexp.Regexp
+}
+
+// NewErrorBodyPolicy - compile a policy, the default fields are always
+// part of it
+func NewErrorBodyPolicy(limit int, fields []string) (*ErrorBodyPolicy, error) {
+	all := append(append([]string{}, DefaultRedactFields...), fields...)
+	for _, f := range fields {
+		if _, err := regexp.Compile(f); err != nil {
+			return nil, fmt.Errorf("Invalid option --oneview-redact-field %q : %w", f, err)
+		}
+	}
+	key := "(?:" + strings.Join(all, "|") + ")"
+	return &ErrorBodyPolicy{
+		Limit:   limit,
+		Fields:  all,
+		jsonRE:  regexp.MustCompile(`(?i)("[^"]*` + key + `[^"]*"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\s]+)`),
+		queryRE: regexp.MustCompile(`(?i)\b([\w.-]*` + key + `[\w.-]*=)[^&\s"]+`),
+	}, nil
+}
+
+var (
+	errorBodyMu     sync.RWMutex
+	errorBodyPolicy *ErrorBodyPolicy
+)
+
+func init() {
+	errorBodyPolicy, _ = NewErrorBodyPolicy(defaultErrorBodyLimit, nil)
+}
+
+// SetErrorBodyPolicy - use p for every following error
+func SetErrorBodyPolicy(p *ErrorBodyPolicy) {
+	errorBodyMu.Lock()
+	defer errorBodyMu.Unlock()
+	errorBodyPolicy = p
+}
+
+func currentErrorBodyPolicy() *ErrorBodyPolicy {
+	errorBodyMu.RLock()
+	defer errorBodyMu.RUnlock()
+	return errorBodyPolicy
+}
+
+// Redact - mask the values of sensitive fields in json or query strings
+func (p *ErrorBodyPolicy) Redact(s string) string {
+	s = p.jsonRE.ReplaceAllString(s, `${1}"`+redacted+`"`)
+	return p.queryRE.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// Body - an error body as it may be logged, redacted then cut to the limit
+func (p *ErrorBodyPolicy) Body(data []byte) string {
+	if p.Limit == 0 {
+		return fmt.Sprintf("(%d byte body left out)", len(data))
+	}
+	return p.truncate(p.Redact(string(data)))
+}
+
+// truncate - cut s to the limit from the middle, the start of an error has
+// the request and the end has the response status
+func (p *ErrorBodyPolicy) truncate(s string) string {
+	if p.Limit < 0 || len(s) <= p.Limit {
+		return s
+	}
+	head := p.Limit / 2
+	tail := p.Limit - head
+	return fmt.Sprintf("%s... (%d bytes cut) ...%s", s[:head], len(s)-p.Limit, s[len(s)-tail:])
+}
+
+// redactedError - an error with a cleaned up message, errors.Is and
+// errors.As still see the original
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+// Unwrap - the original error
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError - apply the policy to an error from the rest clients or the
+// oneview library, which carry the appliance response in their message
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*redactedError); ok {
+		return err
+	}
+	p := currentErrorBodyPolicy()
+	msg := p.Redact(err.Error())
+	// the body is not separate from the rest of the message here, so only
+	// a positive limit applies
+	if p.Limit > 0 {
+		msg = p.truncate(msg)
+	}
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+// errorBody - an error body under the current policy
+func errorBody(data []byte) string {
+	return currentErrorBodyPolicy().Body(data)
+}
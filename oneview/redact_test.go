@@ -0,0 +1,73 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorBodyPolicyRedact(t *testing.T) {
+	p, err := NewErrorBodyPolicy(-1, []string{"apiKey"})
+	assert.NoError(t, err)
+	body := `{"userName":"admin","password":"s3cr\"et","sessionID":"abc","snmp":{"communityString":"public"},"apikey":"k","count":3}`
+	assert.Equal(t, `{"userName":"admin","password":"[REDACTED]","sessionID":"[REDACTED]","snmp":{"communityString":"[REDACTED]"},"apikey":"[REDACTED]","count":3}`, p.Redact(body))
+	assert.Equal(t, "GET /rest/x?auth_token=[REDACTED]&view=expand", p.Redact("GET /rest/x?auth_token=abc123&view=expand"))
+
+	_, err = NewErrorBodyPolicy(10, []string{"("})
+	assert.Error(t, err)
+}
+
+func TestErrorBodyPolicyLimit(t *testing.T) {
+	p, _ := NewErrorBodyPolicy(5, nil)
+	assert.Equal(t, "he... (6 bytes cut) ...rld", p.Body([]byte("hello world")))
+	p, _ = NewErrorBodyPolicy(0, nil)
+	assert.Equal(t, "(11 byte body left out)", p.Body([]byte("hello world")))
+}
+
+func TestErrorBodyPolicyRestored(t *testing.T) {
+	defer SetErrorBodyPolicy(currentErrorBodyPolicy())
+
+	d := &Driver{}
+	assert.NoError(t, d.UnmarshalJSON([]byte(`{"RedactFields": ["serial"]}`)))
+	assert.Equal(t, defaultErrorBodyLimit, d.ErrorBodyLimit)
+	assert.Equal(t, `{"serialNumber":"[REDACTED]"}`, errorBody([]byte(`{"serialNumber":"MX123"}`)))
+
+	assert.Error(t, d.UnmarshalJSON([]byte(`{"RedactFields": ["("]}`)))
+}
+
+func TestRedactError(t *testing.T) {
+	defer SetErrorBodyPolicy(currentErrorBodyPolicy())
+	p, _ := NewErrorBodyPolicy(defaultErrorBodyLimit, nil)
+	SetErrorBodyPolicy(p)
+
+	plain := errors.New("Error with request: 404")
+	assert.Equal(t, plain, redactError(plain))
+
+	orig := &BusyError{}
+	wrapped := redactError(fmt.Errorf(`{"password":"x"} : %w`, orig))
+	assert.False(t, strings.Contains(wrapped.Error(), `"x"`))
+	var be *BusyError
+	assert.True(t, errors.As(wrapped, &be))
+}
+
+func TestRedactErrorKeepsStatus(t *testing.T) {
+	defer SetErrorBodyPolicy(currentErrorBodyPolicy())
+	p, _ := NewErrorBodyPolicy(80, nil)
+	SetErrorBodyPolicy(p)
+
+	body := `{"password":"x","details":"` + strings.Repeat("a", 200) + `"}`
+	err := redactError(applianceError(errors.New("Error with request: /rest/login-sessions " + body + " Response Status: 401 Unauthorized")))
+	assert.Len(t, err.Error(), 80+len("... (184 bytes cut) ..."))
+	assert.True(t, strings.HasPrefix(err.Error(), "Error with request: /rest/login"))
+	assert.Equal(t, 401, statusCodeOf(err))
+	// a second pass leaves it alone
+	assert.Equal(t, err, redactError(err))
+
+	classified := classifyError(errors.New(`login failed {"password":"s3cret"}`))
+	assert.False(t, strings.Contains(classified.Error(), "s3cret"))
+	err = libraryCall(nil, "POST", func() error { return errors.New(`{"sessionID":"abc"}`) })
+	assert.False(t, strings.Contains(err.Error(), "abc"))
+}
@@ -0,0 +1,205 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// redfishTimeout - iLO answers quickly or not at all, do not hold up the
+// driver operation reading telemetry
+var redfishTimeout = 30 * time.Second
+
+// RedfishClient - reads hardware telemetry straight from a blade's iLO
+// over redfish, for detail OneView does not report or reports late
+type RedfishClient struct {
+	Address  string
+	Port     int
+	User     string
+	Password string
+	HTTP     *http.Client
+}
+
+// NewRedfishClient - client for the iLO at address, port 0 is 443
+func NewRedfishClient(address string, port int, user, password string, sslVerify bool) *RedfishClient {
+	c := newHTTPClient(sslVerify)
+	c.Timeout = redfishTimeout
+	if port == 0 {
+		port = 443
+	}
+	return &RedfishClient{Address: address, Port: port, User: user, Password: password, HTTP: c}
+}
+
+// get - decode a redfish resource
+func (r *RedfishClient) get(path string, out interface{}) error {
+	req, err := http.NewRequest("GET", "https://"+net.JoinHostPort(r.Address, strconv.Itoa(r.Port))+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(r.User, r.Password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &ApplianceError{StatusCode: resp.StatusCode, Err: fmt.Errorf("redfish %s returned %s", path, resp.Status)}
+	}
+	return json.Unmarshal(data, out)
+}
+
+// ComponentHealth - health of one fan or power supply
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	Health  string `json:"health,omitempty"`
+	State   string `json:"state,omitempty"`
+	Reading string `json:"reading,omitempty"`
+}
+
+// HardwareHealth - telemetry read from the iLO, kept with the machine so it
+// shows in docker-machine inspect
+type HardwareHealth struct {
+	Read               time.Time         `json:"read"`
+	Fans               []ComponentHealth `json:"fans,omitempty"`
+	PowerSupplies      []ComponentHealth `json:"powerSupplies,omitempty"`
+	PowerConsumedWatts float64           `json:"powerConsumedWatts,omitempty"`
+}
+
+// redfishStatus - the status block of redfish resources
+type redfishStatus struct {
+	Health string `json:"Health"`
+	State  string `json:"State"`
+}
+
+// parseThermal - fans from a redfish Thermal resource, iLO 4 names them
+// FanName and reports CurrentReading rather than the standard attributes
+func parseThermal(data []byte) ([]ComponentHealth, error) {
+	var thermal struct {
+		Fans []struct {
+			Name           string        `json:"Name"`
+			FanName        string        `json:"FanName"`
+			Reading        *float64      `json:"Reading"`
+			CurrentReading *float64      `json:"CurrentReading"`
+			ReadingUnits   string        `json:"ReadingUnits"`
+			Units          string        `json:"Units"`
+			Status         redfishStatus `json:"Status"`
+		} `json:"Fans"`
+	}
+	if err := json.Unmarshal(data, &thermal); err != nil {
+		return nil, err
+	}
+	var fans []ComponentHealth
+	for _, f := range thermal.Fans {
+		c := ComponentHealth{Name: f.Name, Health: f.Status.Health, State: f.Status.State}
+		if c.Name == "" {
+			c.Name = f.FanName
+		}
+		reading, units := f.Reading, f.ReadingUnits
+		if reading == nil {
+			reading, units = f.CurrentReading, f.Units
+		}
+		if reading != nil {
+			c.Reading = fmt.Sprintf("%g %s", *reading, units)
+		}
+		fans = append(fans, c)
+	}
+	return fans, nil
+}
+
+// parsePower - power supplies and the power drawn from a redfish Power resource
+func parsePower(data []byte) ([]ComponentHealth, float64, error) {
+	var power struct {
+		PowerControl []struct {
+			PowerConsumedWatts float64 `json:"PowerConsumedWatts"`
+		} `json:"PowerControl"`
+		PowerSupplies []struct {
+			Name                 string        `json:"Name"`
+			LastPowerOutputWatts *float64      `json:"LastPowerOutputWatts"`
+			Status               redfishStatus `json:"Status"`
+		} `json:"PowerSupplies"`
+	}
+	if err := json.Unmarshal(data, &power); err != nil {
+		return nil, 0, err
+	}
+	var supplies []ComponentHealth
+	for i, p := range power.PowerSupplies {
+		c := ComponentHealth{Name: p.Name, Health: p.Status.Health, State: p.Status.State}
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("Power Supply %d", i+1)
+		}
+		if p.LastPowerOutputWatts != nil {
+			c.Reading = fmt.Sprintf("%g W", *p.LastPowerOutputWatts)
+		}
+		supplies = append(supplies, c)
+	}
+	var watts float64
+	if len(power.PowerControl) > 0 {
+		watts = power.PowerControl[0].PowerConsumedWatts
+	}
+	return supplies, watts, nil
+}
+
+// Health - fan and power supply health of the first chassis
+func (r *RedfishClient) Health() (HardwareHealth, error) {
+	h := HardwareHealth{Read: time.Now().UTC()}
+	var chassis struct {
+		Members []struct {
+			ID string `json:"@odata.id"`
+		} `json:"Members"`
+	}
+	if err := r.get("/redfish/v1/Chassis/", &chassis); err != nil {
+		return h, err
+	}
+	if len(chassis.Members) == 0 {
+		return h, fmt.Errorf("iLO %s reports no chassis", r.Address)
+	}
+	path := strings.TrimSuffix(chassis.Members[0].ID, "/")
+	var raw json.RawMessage
+	if err := r.get(path+"/Thermal/", &raw); err != nil {
+		return h, err
+	}
+	fans, err := parseThermal(raw)
+	if err != nil {
+		return h, err
+	}
+	if err := r.get(path+"/Power/", &raw); err != nil {
+		return h, err
+	}
+	supplies, watts, err := parsePower(raw)
+	if err != nil {
+		return h, err
+	}
+	h.Fans, h.PowerSupplies, h.PowerConsumedWatts = fans, supplies, watts
+	return h, nil
+}
+
+// readHealth - refresh the machine health from its iLO when redfish
+// telemetry is on, failures only get logged
+func (d *Driver) readHealth() {
+	if !d.RedfishTelemetry {
+		return
+	}
+	address := d.Hardware.GetIloIPAddress()
+	if address == "" {
+		log.Debugf("no iLO address for %s, skipping redfish telemetry", d.MachineName)
+		return
+	}
+	h, err := NewRedfishClient(address, d.IloPort, d.IloUser, d.IloPassword, d.ClientOV.SSLVerify).Health()
+	if err != nil {
+		log.Warnf("Unable to read redfish telemetry for %s from iLO %s : %s", d.MachineName, address, err)
+		return
+	}
+	d.Health = &h
+}
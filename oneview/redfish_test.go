@@ -0,0 +1,30 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThermal(t *testing.T) {
+	fans, err := parseThermal([]byte(`{"Fans": [
+		{"FanName": "Fan 1", "CurrentReading": 19, "Units": "Percent", "Status": {"Health": "OK", "State": "Enabled"}},
+		{"Name": "Fan 2", "Reading": 35, "ReadingUnits": "Percent", "Status": {"Health": "Critical", "State": "Enabled"}}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []ComponentHealth{
+		{Name: "Fan 1", Health: "OK", State: "Enabled", Reading: "19 Percent"},
+		{Name: "Fan 2", Health: "Critical", State: "Enabled", Reading: "35 Percent"},
+	}, fans)
+}
+
+func TestParsePower(t *testing.T) {
+	supplies, watts, err := parsePower([]byte(`{"PowerControl": [{"PowerConsumedWatts": 212}],
+		"PowerSupplies": [{"LastPowerOutputWatts": 105, "Status": {"Health": "OK", "State": "Enabled"}},
+		{"Name": "PS 2", "Status": {"Health": "Warning", "State": "Absent"}}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 212.0, watts)
+	assert.Equal(t, []ComponentHealth{
+		{Name: "Power Supply 1", Health: "OK", State: "Enabled", Reading: "105 W"},
+		{Name: "PS 2", Health: "Warning", State: "Absent"},
+	}, supplies)
+}
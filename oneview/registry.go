@@ -0,0 +1,103 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// ResourceCategory - how to fetch and decode resources of one category
+type ResourceCategory struct {
+	// Category - the appliance category, ie; server-hardware
+	Category string
+	// URIPrefix - uris of the category start with this, ie; /rest/server-hardware
+	URIPrefix string
+	// New - returns a pointer to a new typed resource to decode into
+	New func() interface{}
+}
+
+var (
+	categoriesMu sync.RWMutex
+	categories   = map[string]ResourceCategory{}
+)
+
+// RegisterCategory - add or replace a resource category
+func RegisterCategory(rc ResourceCategory) {
+	categoriesMu.Lock()
+	defer categoriesMu.Unlock()
+	categories[rc.Category] = rc
+}
+
+// CategoryForURI - the category a uri belongs to, the longest matching
+// prefix wins so nested collections can have their own type
+func CategoryForURI(uri string) (ResourceCategory, bool) {
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	path, _ := splitURIQuery(uri)
+	var found ResourceCategory
+	ok := false
+	for _, rc := range categories {
+		if path != rc.URIPrefix && !strings.HasPrefix(path, rc.URIPrefix+"/") {
+			continue
+		}
+		if !ok || len(rc.URIPrefix) > len(found.URIPrefix) {
+			found, ok = rc, true
+		}
+	}
+	return found, ok
+}
+
+// Categories - names of the registered categories, sorted
+func Categories() []string {
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	var names []string
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetResourceByURI - get any resource, decoded into the type registered for
+// its category.  Resources of unknown categories come back as a raw map.
+func GetResourceByURI(c *ov.OVClient, uri string) (interface{}, error) {
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResource(uri, data)
+}
+
+// decodeResource - decode a resource body for the category of uri
+func decodeResource(uri string, data []byte) (interface{}, error) {
+	rc, ok := CategoryForURI(uri)
+	if !ok {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+	v := rc.New()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("unable to decode %s as %s : %w", uri, rc.Category, err)
+	}
+	return v, nil
+}
+
+func init() {
+	RegisterCategory(ResourceCategory{Category: "server-profiles", URIPrefix: serverProfilesURI,
+		New: func() interface{} { return &ServerProfileSummary{} }})
+	RegisterCategory(ResourceCategory{Category: "server-hardware", URIPrefix: serverHardwareURI,
+		New: func() interface{} { return &ServerHardwareInventory{} }})
+	RegisterCategory(ResourceCategory{Category: "tasks", URIPrefix: "/rest/tasks",
+		New: func() interface{} { return &Task{} }})
+	RegisterCategory(ResourceCategory{Category: "connection-templates", URIPrefix: "/rest/connection-templates",
+		New: func() interface{} { return &ConnectionTemplate{} }})
+}
@@ -0,0 +1,39 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryForURI(t *testing.T) {
+	rc, ok := CategoryForURI("/rest/server-hardware/31393736-3831-4753-4831-30305837524E")
+	assert.True(t, ok)
+	assert.Equal(t, "server-hardware", rc.Category)
+
+	// server-hardware-types is its own category, not server-hardware
+	_, ok = CategoryForURI("/rest/server-hardware-types/1")
+	assert.False(t, ok)
+
+	RegisterCategory(ResourceCategory{Category: "test-nested", URIPrefix: "/rest/server-hardware/1/firmware",
+		New: func() interface{} { return &map[string]string{} }})
+	defer func() {
+		categoriesMu.Lock()
+		delete(categories, "test-nested")
+		categoriesMu.Unlock()
+	}()
+	rc, _ = CategoryForURI("/rest/server-hardware/1/firmware?filter=x")
+	assert.Equal(t, "test-nested", rc.Category)
+}
+
+func TestDecodeResource(t *testing.T) {
+	v, err := decodeResource("/rest/tasks/1", []byte(`{"uri":"/rest/tasks/1","taskState":"Running"}`))
+	assert.NoError(t, err)
+	task, ok := v.(*Task)
+	assert.True(t, ok)
+	assert.Equal(t, TaskStateRunning, task.TaskState)
+
+	v, err = decodeResource("/rest/unknown/1", []byte(`{"name":"x"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "x", v.(map[string]interface{})["name"])
+}
@@ -0,0 +1,64 @@
+package oneview
+
+import (
+	"github.com/docker/machine/libmachine/log"
+)
+
+// ReImage - reinstall the operating system on the machine while keeping its
+// server profile, and with it the hardware, mac addresses and serial number.
+// The os is deployed again by the provider with the same personalization
+// and ssh keys, docker is provisioned again by running docker-machine
+// provision.
+func (d *Driver) ReImage() error {
+	return d.operation("reimage", d.reimage)
+}
+
+// reimage - implements ReImage
+func (d *Driver) reimage() error {
+	log.Infof("Re-imaging ... %s", d.MachineName)
+	d.logApplianceVersion("ReImage")
+	watchInterrupts()
+	p := d.backend()
+	defer p.Close()
+	if err := runReImage(p, d); err != nil {
+		return err
+	}
+	log.Infof("%s, Completed re-image of %s", d.DriverName(), d.MachineName)
+	return nil
+}
+
+// runReImage - the re-image pipeline, the create steps after allocate run
+// again on the hardware the machine already has
+func runReImage(p BareMetalProvider, h machineHost) error {
+	stage := func(name string, fn func() error) error {
+		return runStage(h.GetMachineName(), name, fn)
+	}
+	if err := stage("locate", p.Locate); err != nil {
+		return err
+	}
+	// the os deployment expects to find the hardware powered off
+	if err := stage("poweroff", p.PowerOff); err != nil {
+		return err
+	}
+	if err := stage("deploy", p.Deploy); err != nil {
+		return err
+	}
+	err := stage("address", func() error {
+		ip, err := p.Address()
+		if err == nil {
+			h.setIPAddress(ip)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := stage("ssh", h.installSSHKey); err != nil {
+		return err
+	}
+	stage("verify", func() error {
+		p.Verify()
+		return nil
+	})
+	return nil
+}
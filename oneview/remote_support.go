@@ -0,0 +1,111 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+const (
+	remoteSupportURI = "/rest/support"
+	// remoteSupportAPIVersion - first api version with remote support
+	remoteSupportAPIVersion = 300
+)
+
+// ErrRemoteSupportUnavailable - the appliance api predates remote support
+var ErrRemoteSupportUnavailable = errors.New("Remote support needs OneView api version 300 or later")
+
+// RemoteSupportConfiguration - appliance wide remote support settings
+type RemoteSupportConfiguration struct {
+	URI                  string `json:"uri,omitempty"`
+	CompanyName          string `json:"companyName,omitempty"`
+	EnableRemoteSupport  bool   `json:"enableRemoteSupport"`
+	InsightOnlineEnabled bool   `json:"insightOnlineEnabled"`
+	AutoEnableDevices    bool   `json:"autoEnableDevices"`
+	MarketingOptIn       bool   `json:"marketingOptIn"`
+}
+
+// HardwareRemoteSupport - remote support state of one server hardware
+type HardwareRemoteSupport struct {
+	URI            string `json:"uri,omitempty"`
+	SupportEnabled bool   `json:"supportEnabled"`
+	SupportState   string `json:"supportState,omitempty"`
+	// HardwareURI - the server hardware the state is for
+	HardwareURI string `json:"-"`
+}
+
+// Compliant - call home is on for the hardware
+func (h HardwareRemoteSupport) Compliant() bool {
+	return h.SupportEnabled
+}
+
+// GetRemoteSupportConfiguration - read the appliance remote support settings
+func GetRemoteSupportConfiguration(c *ov.OVClient) (RemoteSupportConfiguration, error) {
+	var cfg RemoteSupportConfiguration
+	if c.APIVersion < remoteSupportAPIVersion {
+		return cfg, ErrRemoteSupportUnavailable
+	}
+	data, err := ovCall(c, rest.GET, remoteSupportURI+"/configuration", nil)
+	if err != nil {
+		return cfg, err
+	}
+	err = json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// GetHardwareRemoteSupport - read the remote support state of server hardware
+func GetHardwareRemoteSupport(c *ov.OVClient, hardwareURI string) (HardwareRemoteSupport, error) {
+	hs := HardwareRemoteSupport{HardwareURI: hardwareURI}
+	if c.APIVersion < remoteSupportAPIVersion {
+		return hs, ErrRemoteSupportUnavailable
+	}
+	data, err := ovCall(c, rest.GET, remoteSupportURI+"/server-hardware/"+path.Base(hardwareURI), nil)
+	if err != nil {
+		return hs, err
+	}
+	err = json.Unmarshal(data, &hs)
+	return hs, err
+}
+
+// RemoteSupportReport - call home settings for a set of hardware
+type RemoteSupportReport struct {
+	Configuration RemoteSupportConfiguration
+	Hardware      []HardwareRemoteSupport
+}
+
+// Compliant - remote support is on for the appliance and every hardware
+func (r RemoteSupportReport) Compliant() bool {
+	return r.Configuration.EnableRemoteSupport && len(r.NonCompliant()) == 0
+}
+
+// NonCompliant - hardware that will not call home
+func (r RemoteSupportReport) NonCompliant() []HardwareRemoteSupport {
+	var list []HardwareRemoteSupport
+	for _, h := range r.Hardware {
+		if !h.Compliant() {
+			list = append(list, h)
+		}
+	}
+	return list
+}
+
+// GetRemoteSupportReport - read the appliance settings and the state of each
+// hardware, ie; the hardware hosting docker machines
+func GetRemoteSupportReport(c *ov.OVClient, hardwareURIs []string) (RemoteSupportReport, error) {
+	var r RemoteSupportReport
+	var err error
+	if r.Configuration, err = GetRemoteSupportConfiguration(c); err != nil {
+		return r, err
+	}
+	for _, uri := range hardwareURIs {
+		hs, err := GetHardwareRemoteSupport(c, uri)
+		if err != nil {
+			return r, err
+		}
+		r.Hardware = append(r.Hardware, hs)
+	}
+	return r, nil
+}
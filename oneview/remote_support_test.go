@@ -0,0 +1,31 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSupportReport(t *testing.T) {
+	r := RemoteSupportReport{
+		Configuration: RemoteSupportConfiguration{EnableRemoteSupport: true},
+		Hardware: []HardwareRemoteSupport{
+			{HardwareURI: "/rest/server-hardware/1", SupportEnabled: true},
+			{HardwareURI: "/rest/server-hardware/2", SupportState: "NotRegistered"},
+		},
+	}
+	assert.False(t, r.Compliant())
+	assert.Len(t, r.NonCompliant(), 1)
+	assert.Equal(t, "/rest/server-hardware/2", r.NonCompliant()[0].HardwareURI)
+
+	r.Hardware = r.Hardware[:1]
+	assert.True(t, r.Compliant())
+}
+
+func TestRemoteSupportAPIVersion(t *testing.T) {
+	c := &ov.OVClient{}
+	c.APIVersion = 200
+	_, err := GetRemoteSupportConfiguration(c)
+	assert.Equal(t, ErrRemoteSupportUnavailable, err)
+}
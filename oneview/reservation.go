@@ -0,0 +1,223 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	// leaseLabelPrefix - labels starting with this are reservation leases,
+	// the rest of the label is owner:expiry, expiry in unix seconds
+	leaseLabelPrefix = "lease:"
+	// defaultReservationTTL - how long a create holds its hardware unless
+	// --oneview-reservation-ttl says otherwise, long enough for a firmware
+	// update as part of the profile
+	defaultReservationTTL = time.Hour
+)
+
+// ErrHardwareReserved - another create holds an unexpired lease on the hardware
+var ErrHardwareReserved = errors.New("Server hardware is reserved by another create")
+
+// Lease - a claim on server hardware that lapses at Expires, so a crashed
+// create never holds hardware for good.  Leases are labels on the server
+// hardware, any operator workstation talking to the appliance sees them.
+type Lease struct {
+	HardwareURI string
+	Owner       string
+	Expires     time.Time
+}
+
+// label - the label the lease is stored as
+func (l Lease) label() string {
+	return fmt.Sprintf("%s%s:%d", leaseLabelPrefix, l.Owner, l.Expires.Unix())
+}
+
+// String - for logs
+func (l Lease) String() string {
+	return fmt.Sprintf("%s until %s", l.Owner, l.Expires.Format(time.RFC3339))
+}
+
+// parseLease - the lease stored in a label, false for other labels
+func parseLease(hardwareURI, label string) (Lease, bool) {
+	if !strings.HasPrefix(label, leaseLabelPrefix) {
+		return Lease{}, false
+	}
+	rest := label[len(leaseLabelPrefix):]
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 {
+		return Lease{}, false
+	}
+	expires, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil {
+		return Lease{}, false
+	}
+	return Lease{HardwareURI: hardwareURI, Owner: rest[:i], Expires: time.Unix(expires, 0)}, true
+}
+
+// leaseOwner - who a create is, the machine and the workstation it runs on
+func leaseOwner(machine string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return machine + "@" + host
+}
+
+// leasesByExpiry - the lease taken first wins when two creates claim at once
+type leasesByExpiry []Lease
+
+func (l leasesByExpiry) Len() int      { return len(l) }
+func (l leasesByExpiry) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l leasesByExpiry) Less(i, j int) bool {
+	if !l[i].Expires.Equal(l[j].Expires) {
+		return l[i].Expires.Before(l[j].Expires)
+	}
+	return l[i].Owner < l[j].Owner
+}
+
+// activeLeases - unexpired leases in a set of labels, oldest first
+func activeLeases(hardwareURI string, labels []string, now time.Time) []Lease {
+	var leases []Lease
+	for _, label := range labels {
+		if l, ok := parseLease(hardwareURI, label); ok && l.Expires.After(now) {
+			leases = append(leases, l)
+		}
+	}
+	sort.Sort(leasesByExpiry(leases))
+	return leases
+}
+
+// GetLeases - unexpired leases on server hardware
+func GetLeases(c *ov.OVClient, hardwareURI string) ([]Lease, error) {
+	labels, err := GetLabels(c, hardwareURI)
+	if err != nil {
+		return nil, err
+	}
+	return activeLeases(hardwareURI, labels, time.Now()), nil
+}
+
+// ClaimHardware - take a lease on server hardware for ttl, or for
+// defaultReservationTTL when ttl is not positive.  Expired leases are
+// cleared on the way.  Labels are written as a whole and only while the
+// hardware eTag is the one read before, a create that finds it changed
+// backs off with ErrHardwareReserved.  The lease is read back too, when
+// another owner got there as well the oldest lease wins.
+func ClaimHardware(c *ov.OVClient, hardwareURI, owner string, ttl time.Duration) (*Lease, error) {
+	if ttl <= 0 {
+		ttl = defaultReservationTTL
+	}
+	now := time.Now()
+	hardware, err := getResourceMap(c, hardwareURI)
+	if err != nil {
+		return nil, err
+	}
+	var headers map[string]string
+	if etag, _ := hardware["eTag"].(string); etag != "" {
+		headers = map[string]string{"If-Match": etag}
+	}
+	rl, err := getResourceLabels(c, hardwareURI)
+	if err != nil {
+		return nil, err
+	}
+	lease := Lease{HardwareURI: hardwareURI, Owner: owner, Expires: now.Add(ttl)}
+	labels := rl.Labels[:0]
+	for _, l := range rl.Labels {
+		held, ok := parseLease(hardwareURI, l.Name)
+		if !ok {
+			labels = append(labels, l)
+			continue
+		}
+		if held.Owner != owner && held.Expires.After(now) {
+			return nil, fmt.Errorf("%w: %s", ErrHardwareReserved, held)
+		}
+		// expired leases and our own older ones are dropped
+	}
+	rl.Labels = append(labels, resourceLabel{Name: lease.label()})
+	if _, err := ovHeaderCall(c, rest.PUT, labelsResourcesURI+hardwareURI, headers, rl); err != nil {
+		if isPreconditionFailed(err) {
+			return nil, fmt.Errorf("%w: %s changed while claiming it", ErrHardwareReserved, hardwareURI)
+		}
+		return nil, err
+	}
+
+	names, err := GetLabels(c, hardwareURI)
+	if err != nil {
+		return nil, err
+	}
+	leases := activeLeases(hardwareURI, names, now)
+	if len(leases) > 0 && leases[0].label() == lease.label() {
+		return &lease, nil
+	}
+	// lost the race, leave the lease of whoever won alone
+	if err := RemoveLabel(c, hardwareURI, lease.label()); err != nil {
+		log.Warnf("Unable to remove lease %s from %s : %s", lease, hardwareURI, err)
+	}
+	if len(leases) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrHardwareReserved, leases[0])
+	}
+	return nil, ErrHardwareReserved
+}
+
+// Release - give the hardware back before the lease runs out
+func (l *Lease) Release(c *ov.OVClient) error {
+	if l == nil {
+		return nil
+	}
+	return RemoveLabel(c, l.HardwareURI, l.label())
+}
+
+// isReserved - true when someone other than owner holds a lease
+func isReserved(c *ov.OVClient, hardwareURI, owner string) (bool, error) {
+	leases, err := GetLeases(c, hardwareURI)
+	if err != nil {
+		return false, err
+	}
+	for _, l := range leases {
+		if l.Owner != owner {
+			log.Debugf("skipping %s, reserved by %s", hardwareURI, l)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// leaseOwner - the owner for leases this create takes, empty when
+// reservations are off
+func (d *Driver) leaseOwner() string {
+	if d.ReservationTTL <= 0 {
+		return ""
+	}
+	return leaseOwner(d.MachineName)
+}
+
+// reserveHardware - lease the planned hardware, planning again when another
+// create claimed it first.  The lease is nil when nothing needs reserving.
+func (d *Driver) reserveHardware(plan *CreatePlan) (*CreatePlan, *Lease, error) {
+	const attempts = 3
+	for i := 0; ; i++ {
+		if plan.Hardware == nil || d.ReservationTTL <= 0 {
+			return plan, nil, nil
+		}
+		lease, err := ClaimHardware(d.ClientOV, plan.Hardware.URI, d.leaseOwner(), d.ReservationTTL)
+		if err == nil {
+			log.Infof("Reserved %s for %s", plan.Hardware.Name, lease)
+			return plan, lease, nil
+		}
+		if !errors.Is(err, ErrHardwareReserved) || i+1 == attempts {
+			return nil, nil, err
+		}
+		log.Infof("%s was claimed by another create, choosing again : %s", plan.Hardware.Name, err)
+		if plan, err = d.planCreate(); err != nil {
+			return nil, nil, err
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaseLabel(t *testing.T) {
+	l := Lease{HardwareURI: "/rest/server-hardware/1", Owner: "docker-1@ws:1", Expires: time.Unix(1760000000, 0)}
+	assert.Equal(t, "lease:docker-1@ws:1:1760000000", l.label())
+	parsed, ok := parseLease(l.HardwareURI, l.label())
+	assert.True(t, ok)
+	assert.Equal(t, l.Owner, parsed.Owner)
+	assert.True(t, l.Expires.Equal(parsed.Expires))
+
+	_, ok = parseLease("", "cordoned")
+	assert.False(t, ok)
+	_, ok = parseLease("", "lease:docker-1")
+	assert.False(t, ok)
+}
+
+func TestActiveLeases(t *testing.T) {
+	now := time.Unix(1000, 0)
+	leases := activeLeases("/rest/server-hardware/1", []string{
+		"cordoned",
+		"lease:b@ws2:2000",
+		"lease:old@ws3:900",
+		"lease:a@ws1:1500",
+	}, now)
+	assert.Len(t, leases, 2)
+	assert.Equal(t, "a@ws1", leases[0].Owner)
+	assert.Equal(t, "b@ws2", leases[1].Owner)
+}
+
+func TestHardwareReservedCategory(t *testing.T) {
+	assert.Equal(t, CategoryTransient, CategoryOf(fmt.Errorf("%w: a@ws1", ErrHardwareReserved)))
+}
+
+func TestClaimHardwareChangedHardware(t *testing.T) {
+	hw := "/rest/server-hardware/1"
+	c, done := fakeOV(t,
+		fakeCall{method: "GET", uri: hw, data: `{"uri":"` + hw + `","eTag":"7"}`},
+		fakeCall{method: "GET", uri: labelsResourcesURI + hw, data: `{"labels":[{"name":"lease:other@ws:1"}]}`},
+		fakeCall{method: "PUT", uri: labelsResourcesURI + hw, body: func(body interface{}) {
+			// the expired lease is dropped, ours runs for the default ttl
+			rl := body.(resourceLabels)
+			assert.Len(t, rl.Labels, 1)
+			lease, ok := parseLease(hw, rl.Labels[0].Name)
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(defaultReservationTTL), lease.Expires, time.Minute)
+		}, err: errors.New("Error with request: Response Status: 412 Precondition Failed")},
+	)
+	defer done()
+	_, err := ClaimHardware(c, hw, "docker-1@ws", 0)
+	assert.True(t, errors.Is(err, ErrHardwareReserved))
+}
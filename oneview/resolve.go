@@ -0,0 +1,71 @@
+package oneview
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// resolve overrides, host name to address, like curl --resolve
+var (
+	resolveMu        sync.RWMutex
+	resolveOverrides map[string]net.IP
+)
+
+// SetResolveOverrides - connect to the given addresses for the host names,
+// rather than what dns says, for labs where the appliance certificate name
+// does not resolve.  Requests still use the host name, so certificate
+// checks match it.  Only the driver's own http clients dial through the
+// overrides, the rest library dials on its own and looks names up in dns.
+func SetResolveOverrides(overrides map[string]string) error {
+	parsed := map[string]net.IP{}
+	for host, addr := range overrides {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return fmt.Errorf("Invalid option --oneview-resolve %s:%s, %s is not an ip address", host, addr, addr)
+		}
+		parsed[strings.ToLower(strings.TrimSuffix(host, "."))] = ip
+	}
+	resolveMu.Lock()
+	defer resolveMu.Unlock()
+	resolveOverrides = parsed
+	return nil
+}
+
+// parseResolve - overrides given as host:address
+func parseResolve(list []string) (map[string]string, error) {
+	overrides := map[string]string{}
+	for _, r := range list {
+		if strings.TrimSpace(r) == "" {
+			continue
+		}
+		i := strings.Index(r, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid option --oneview-resolve %q, must be host:address", r)
+		}
+		overrides[strings.TrimSpace(r[:i])] = strings.TrimSpace(r[i+1:])
+	}
+	return overrides, nil
+}
+
+// resolveOverride - the address for a host name, nil when not overridden
+func resolveOverride(host string) net.IP {
+	resolveMu.RLock()
+	defer resolveMu.RUnlock()
+	return resolveOverrides[strings.ToLower(strings.TrimSuffix(host, "."))]
+}
+
+// resolveDialContext - dial for the driver's own http clients, overridden
+// host names connect to their address
+func resolveDialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		if ip := resolveOverride(host); ip != nil {
+			address = net.JoinHostPort(ip.String(), port)
+		}
+	}
+	return d.DialContext(ctx, network, address)
+}
@@ -0,0 +1,39 @@
+package oneview
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResolve(t *testing.T) {
+	r, err := parseResolve([]string{"ov.lab.test:10.1.1.5", "icsp.lab.test:fd00::5", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ov.lab.test": "10.1.1.5", "icsp.lab.test": "fd00::5"}, r)
+
+	_, err = parseResolve([]string{"10.1.1.5"})
+	assert.Error(t, err)
+
+	assert.Error(t, SetResolveOverrides(map[string]string{"ov.lab.test": "nowhere"}))
+}
+
+func TestResolveOverrides(t *testing.T) {
+	assert.NoError(t, SetResolveOverrides(map[string]string{"OV.lab.test": "10.1.1.5", "icsp.lab.test": "fd00::5"}))
+	defer SetResolveOverrides(nil)
+
+	assert.Equal(t, "10.1.1.5", resolveOverride("ov.lab.test.").String())
+	assert.Nil(t, resolveOverride("other.lab.test"))
+
+	// the driver's own clients connect to the address, dns is not asked
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	assert.NoError(t, SetResolveOverrides(map[string]string{"ov.lab.test": "127.0.0.1"}))
+	conn, err := resolveDialContext(context.Background(), "tcp", net.JoinHostPort("ov.lab.test", port))
+	assert.NoError(t, err)
+	conn.Close()
+	assert.True(t, net.DefaultResolver.Dial == nil, "the process resolver is left alone")
+}
@@ -0,0 +1,235 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/icsp"
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// callMu - the rest clients keep headers and the query string between calls,
+// so only one goroutine may set them up and make a call at a time
+var callMu sync.Mutex
+
+// ovRestCall - make a call once ovRequest has set it up, tests replace it
+// to answer for the appliance
+var ovRestCall = func(c *ov.OVClient, method rest.Method, uri string, query map[string]interface{}, body interface{}) ([]byte, error) {
+	c.SetQueryString(query)
+	defer c.SetQueryString(map[string]interface{}{})
+	return c.RestAPICall(method, uri, body)
+}
+
+// ovCall - issue an authenticated rest call against the OneView appliance
+func ovCall(c *ov.OVClient, method rest.Method, uri string, body interface{}) ([]byte, error) {
+	path, query := splitURIQuery(uri)
+	return ovQueryCall(c, method, path, query, body)
+}
+
+// ovQueryCall - issue an authenticated rest call with query parameters, the
+// query is cleared afterwards so it does not leak into the next call
+func ovQueryCall(c *ov.OVClient, method rest.Method, uri string, query map[string]interface{}, body interface{}) ([]byte, error) {
+	return ovRequest(c, method, uri, query, nil, body)
+}
+
+// ovHeaderCall - issue an authenticated rest call with extra headers, ie;
+// If-Match, that only apply to this request
+func ovHeaderCall(c *ov.OVClient, method rest.Method, uri string, extra map[string]string, body interface{}) ([]byte, error) {
+	path, query := splitURIQuery(uri)
+	return ovRequest(c, method, path, query, extra, body)
+}
+
+func ovRequest(c *ov.OVClient, method rest.Method, uri string, query map[string]interface{}, extra map[string]string, body interface{}) ([]byte, error) {
+	if err := authorizeOV(c); err != nil {
+		return nil, err
+	}
+	headers := c.GetAuthHeaderMap()
+	for k, v := range extra {
+		headers[k] = v
+	}
+	if err := applyRequestHooks(method.String(), c.Endpoint+uri, headers); err != nil {
+		return nil, err
+	}
+	recordThrottle(c, throttle(c.Endpoint))
+	callMu.Lock()
+	defer callMu.Unlock()
+	c.SetAuthHeaderOptions(headers)
+	if query == nil {
+		query = map[string]interface{}{}
+	}
+	start := time.Now()
+	data, err := ovRestCall(c, method, uri, query, body)
+	recordCall(c, method.String(), time.Since(start), err)
+	return data, redactError(checkLimitError(c.Endpoint, applianceError(err)))
+}
+
+// icspCall - issue an authenticated rest call against the ICSP appliance
+func icspCall(c *icsp.ICSPClient, method rest.Method, uri string, body interface{}) ([]byte, error) {
+	if err := checkPinnedEndpoint(c.Endpoint); err != nil {
+		return nil, err
+	}
+	if err := refreshICSP(c); err != nil {
+		return nil, err
+	}
+	path, query := splitURIQuery(uri)
+	headers := c.GetAuthHeaderMap()
+	if err := applyRequestHooks(method.String(), c.Endpoint+path, headers); err != nil {
+		return nil, err
+	}
+	recordThrottle(c, throttle(c.Endpoint))
+	callMu.Lock()
+	defer callMu.Unlock()
+	c.SetAuthHeaderOptions(headers)
+	if query == nil {
+		query = map[string]interface{}{}
+	}
+	c.SetQueryString(query)
+	defer c.SetQueryString(map[string]interface{}{})
+	start := time.Now()
+	data, err := c.RestAPICall(method, path, body)
+	recordCall(c, method.String(), time.Since(start), err)
+	return data, redactError(checkLimitError(c.Endpoint, applianceError(err)))
+}
+
+// splitURIQuery - split a uri returned by the appliance, like nextPageUri,
+// into the path and the query the rest client expects
+func splitURIQuery(uri string) (string, map[string]interface{}) {
+	i := strings.Index(uri, "?")
+	if i < 0 {
+		return uri, nil
+	}
+	values, err := url.ParseQuery(uri[i+1:])
+	if err != nil || len(values) == 0 {
+		return uri[:i], nil
+	}
+	query := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		query[k] = v
+	}
+	return uri[:i], query
+}
+
+// applianceFilter - an appliance filter expression, ie; state='NoProfileApplied'
+func applianceFilter(attribute, value string) string {
+	return fmt.Sprintf("%s='%s'", attribute, strings.Replace(value, "'", "\\'", -1))
+}
+
+// getResourceMap - get a resource as a raw attribute map, used when we need to
+// change attributes the ov library does not model without dropping the rest
+func getResourceMap(c *ov.OVClient, uri string) (map[string]interface{}, error) {
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resource map[string]interface{}
+	if err := decodeJSON(data, &resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// putResourceMap - put a raw attribute map back to the appliance and wait on
+// the task it returns
+func putResourceMap(c *ov.OVClient, uri string, resource map[string]interface{}) error {
+	data, err := ovCall(c, rest.PUT, uri, resource)
+	if err != nil {
+		return err
+	}
+	return waitForTaskResponse(c, data)
+}
+
+// listMembers - collect the members of a collection, following nextPageUri
+// until the appliance has returned every page.  Each page's members are
+// handed to add as raw json.
+func listMembers(c *ov.OVClient, uri string, query map[string]interface{}, add func(members json.RawMessage) error) error {
+	seen := map[string]bool{}
+	for uri != "" {
+		data, err := ovQueryCall(c, rest.GET, uri, query, nil)
+		if err != nil {
+			return err
+		}
+		var page struct {
+			Members     json.RawMessage `json:"members"`
+			NextPageURI string          `json:"nextPageUri"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		if len(page.Members) > 0 {
+			if err := add(page.Members); err != nil {
+				return err
+			}
+		}
+		// the next page uri carries its own query, and some appliance
+		// versions hand back the page we are on as the next one
+		if seen[page.NextPageURI] {
+			break
+		}
+		seen[page.NextPageURI] = true
+		uri, query = splitURIQuery(page.NextPageURI)
+	}
+	return nil
+}
+
+// deleteResource - delete a resource and wait on the task, when the appliance
+// returns one
+func deleteResource(c *ov.OVClient, uri string) error {
+	return deleteResourceProgress(c, uri, defaultProgress())
+}
+
+// deleteResourceProgress - deleteResource, reporting the task progress to out
+func deleteResourceProgress(c *ov.OVClient, uri string, out ProgressSink) error {
+	data, err := ovCall(c, rest.DELETE, uri, nil)
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+	taskURI, err := taskURI(data)
+	if err != nil {
+		return err
+	}
+	_, err = waitForTaskProgress(interruptCtx, c, taskURI, out)
+	return err
+}
+
+// findURIByName - uri of the named member of a collection, empty when there
+// is none
+func findURIByName(c *ov.OVClient, collection, name string) (string, error) {
+	query := map[string]interface{}{"filter": []string{applianceFilter("name", name)}}
+	var uri string
+	err := listMembers(c, collection, query, func(members json.RawMessage) error {
+		var page []struct {
+			URI  string `json:"uri"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		for _, m := range page {
+			if uri == "" && m.Name == name {
+				uri = m.URI
+			}
+		}
+		return nil
+	})
+	return uri, err
+}
+
+// isNotFound - true when the appliance answered a call with 404
+func isNotFound(err error) bool {
+	return err != nil && statusCodeOf(err) == http.StatusNotFound
+}
+
+// isPreconditionFailed - the error is the appliance refusing a change made
+// against an eTag that is no longer current
+func isPreconditionFailed(err error) bool {
+	return err != nil && statusCodeOf(err) == http.StatusPreconditionFailed
+}
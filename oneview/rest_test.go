@@ -0,0 +1,51 @@
+package oneview
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCall - an appliance call a test expects and the answer to it, a nil
+// query or body check is not made
+type fakeCall struct {
+	method string
+	uri    string
+	query  map[string]interface{}
+	body   func(body interface{})
+	data   string
+	err    error
+}
+
+// fakeOV - a token client whose calls are answered in turn from calls, and
+// a func to call when done that checks every call was made
+func fakeOV(t *testing.T, calls ...fakeCall) (*ov.OVClient, func()) {
+	c, err := NewOVClientFromToken("https://ov", "token", false, 800)
+	assert.NoError(t, err)
+	savedCall, savedInterval := ovRestCall, taskPollInterval
+	taskPollInterval = 0
+	ovRestCall = func(_ *ov.OVClient, method rest.Method, uri string, query map[string]interface{}, body interface{}) ([]byte, error) {
+		if len(calls) == 0 {
+			t.Errorf("unexpected call %s %s", method, uri)
+			return nil, fmt.Errorf("unexpected call %s %s", method, uri)
+		}
+		call := calls[0]
+		calls = calls[1:]
+		assert.Equal(t, call.method+" "+call.uri, method.String()+" "+uri)
+		if call.query != nil {
+			assert.Equal(t, call.query, query)
+		}
+		if call.body != nil {
+			call.body(body)
+		}
+		return []byte(call.data), call.err
+	}
+	return c, func() {
+		ovRestCall, taskPollInterval = savedCall, savedInterval
+		RevokeOVClient(c)
+		assert.Empty(t, calls, "calls not made")
+	}
+}
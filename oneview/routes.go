@@ -0,0 +1,107 @@
+package oneview
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// StaticRoute - a route the os gets on top of its default gateway, ie; to
+// reach other hosts over a data network that has no gateway of its own
+type StaticRoute struct {
+	Destination string
+	Gateway     string
+}
+
+// String - ie; 10.20.0.0/16 via 10.1.0.1
+func (r StaticRoute) String() string {
+	return r.Destination + " via " + r.Gateway
+}
+
+// SecondaryInterface - a profile connection the os configures besides the
+// public one, by connection name
+type SecondaryInterface struct {
+	Connection string
+	// Address - address with prefix, or dhcp
+	Address string
+}
+
+// NetworkPersonalization - routes and secondary interfaces for multi-homed
+// machines, handed to the build plans as custom attributes
+type NetworkPersonalization struct {
+	Routes     []StaticRoute
+	Interfaces []SecondaryInterface
+}
+
+// newNetworkPersonalization - build the settings from flag values, routes
+// are destination=gateway and interfaces connection=address
+func newNetworkPersonalization(routes, interfaces []string) (NetworkPersonalization, error) {
+	var np NetworkPersonalization
+	for _, r := range routes {
+		dest, gw, ok := splitPair(r)
+		if !ok {
+			return np, fmt.Errorf("Invalid option --oneview-static-route %q, must be destination=gateway, ie; 10.20.0.0/16=10.1.0.1", r)
+		}
+		_, ipnet, err := net.ParseCIDR(dest)
+		if err != nil {
+			return np, fmt.Errorf("Invalid option --oneview-static-route %q, destination must be a network with prefix", r)
+		}
+		ip := net.ParseIP(gw)
+		if ip == nil || (ip.To4() == nil) != (ipnet.IP.To4() == nil) {
+			return np, fmt.Errorf("Invalid option --oneview-static-route %q, gateway must be an address of the same family", r)
+		}
+		np.Routes = append(np.Routes, StaticRoute{Destination: ipnet.String(), Gateway: ip.String()})
+	}
+	for _, i := range interfaces {
+		conn, addr, ok := splitPair(i)
+		if !ok {
+			return np, fmt.Errorf("Invalid option --oneview-secondary-interface %q, must be connection=address/prefix or connection=dhcp", i)
+		}
+		if !strings.EqualFold(addr, "dhcp") {
+			if _, _, err := net.ParseCIDR(addr); err != nil {
+				return np, fmt.Errorf("Invalid option --oneview-secondary-interface %q, address must have a prefix, ie; 10.1.0.20/24", i)
+			}
+		} else {
+			addr = "dhcp"
+		}
+		np.Interfaces = append(np.Interfaces, SecondaryInterface{Connection: conn, Address: addr})
+	}
+	return np, nil
+}
+
+// splitPair - key=value, both trimmed and not empty
+func splitPair(s string) (string, string, bool) {
+	i := strings.Index(s, "=")
+	if i < 0 {
+		return "", "", false
+	}
+	k, v := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+	return k, v, k != "" && v != ""
+}
+
+// attributes - ICsp custom attributes for the build plans, @static_routes@
+// and @secondary_interfaces@, with the mac of each secondary interface
+// looked up by its connection name
+func (np NetworkPersonalization) attributes(macOf func(connection string) (string, error)) (map[string]string, error) {
+	if len(np.Routes) == 0 && len(np.Interfaces) == 0 {
+		return nil, nil
+	}
+	var routes, interfaces []string
+	for _, r := range np.Routes {
+		routes = append(routes, r.String())
+	}
+	for _, i := range np.Interfaces {
+		mac, err := macOf(i.Connection)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the mac of secondary interface %s: %w", i.Connection, err)
+		}
+		if mac == "" {
+			return nil, fmt.Errorf("profile connection %s has no mac for a secondary interface", i.Connection)
+		}
+		interfaces = append(interfaces, strings.ToLower(mac)+"="+i.Address)
+	}
+	return map[string]string{
+		"static_routes":        strings.Join(routes, ","),
+		"secondary_interfaces": strings.Join(interfaces, ","),
+	}, nil
+}
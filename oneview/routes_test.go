@@ -0,0 +1,54 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNetworkPersonalization(t *testing.T) {
+	np, err := newNetworkPersonalization([]string{"10.20.1.0/16=10.1.0.1", "fd00:20::/48 = fd00::1"}, []string{"data=10.1.0.20/24", "backup=DHCP"})
+	assert.NoError(t, err)
+	assert.Equal(t, []StaticRoute{{"10.20.0.0/16", "10.1.0.1"}, {"fd00:20::/48", "fd00::1"}}, np.Routes)
+	assert.Equal(t, []SecondaryInterface{{"data", "10.1.0.20/24"}, {"backup", "dhcp"}}, np.Interfaces)
+
+	for _, bad := range [][2][]string{
+		{{"10.20.0.0/16"}, nil},
+		{{"10.20.0.1=10.1.0.1"}, nil},
+		{{"10.20.0.0/16=fd00::1"}, nil},
+		{nil, {"data=10.1.0.20"}},
+		{nil, {"=dhcp"}},
+	} {
+		_, err := newNetworkPersonalization(bad[0], bad[1])
+		assert.Error(t, err, "%v", bad)
+	}
+}
+
+func TestNetworkPersonalizationAttributes(t *testing.T) {
+	macs := map[string]string{"data": "AA:BB:CC:00:00:02"}
+	macOf := func(name string) (string, error) {
+		if mac, ok := macs[name]; ok {
+			return mac, nil
+		}
+		return "", errors.New("no connection " + name)
+	}
+	attrs, err := NetworkPersonalization{}.attributes(macOf)
+	assert.NoError(t, err)
+	assert.Nil(t, attrs)
+
+	np := NetworkPersonalization{
+		Routes:     []StaticRoute{{"10.20.0.0/16", "10.1.0.1"}, {"10.30.0.0/16", "10.1.0.1"}},
+		Interfaces: []SecondaryInterface{{"data", "10.1.0.20/24"}},
+	}
+	attrs, err = np.attributes(macOf)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"static_routes":        "10.20.0.0/16 via 10.1.0.1,10.30.0.0/16 via 10.1.0.1",
+		"secondary_interfaces": "aa:bb:cc:00:00:02=10.1.0.20/24",
+	}, attrs)
+
+	np.Interfaces = append(np.Interfaces, SecondaryInterface{"backup", "dhcp"})
+	_, err = np.attributes(macOf)
+	assert.Error(t, err)
+}
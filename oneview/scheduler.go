@@ -0,0 +1,476 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	// defaultBusyRetry - how long to defer work when the appliance is busy
+	// but does not say for how long
+	defaultBusyRetry = 5 * time.Minute
+	// defaultQueueAttempts - tries of an operation from OpenOperationQueue
+	defaultQueueAttempts = 5
+	// operationQueueFile - the queue of OpenOperationQueue in the machine store
+	operationQueueFile = "oneview-operations.json"
+	// queueLockStale - a queue lock this old was left by a process that died
+	queueLockStale = time.Minute
+	// queueLockWait - how long to wait for another process to save the queue
+	queueLockWait = 2 * queueLockStale
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// MaintenanceWindow - a daily time range, optionally limited to some days,
+// when bulk changes to the appliance are allowed.  A window can wrap
+// midnight, ie; 22:00-06:00, and then belongs to the day it starts on.
+type MaintenanceWindow struct {
+	Days  []time.Weekday
+	Start time.Duration // offset from midnight
+	End   time.Duration // offset from midnight
+}
+
+// ParseMaintenanceWindow - parse windows like "22:00-06:00" or "sat,sun 00:00-23:59"
+func ParseMaintenanceWindow(s string) (MaintenanceWindow, error) {
+	var mw MaintenanceWindow
+	fields := strings.Fields(strings.TrimSpace(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return mw, fmt.Errorf("invalid maintenance window %q", s)
+	}
+	if len(fields) == 2 {
+		for _, d := range strings.Split(fields[0], ",") {
+			wd, ok := weekdays[strings.ToLower(d)]
+			if !ok {
+				return mw, fmt.Errorf("invalid day %q in maintenance window %q", d, s)
+			}
+			mw.Days = append(mw.Days, wd)
+		}
+	}
+	times := strings.Split(fields[len(fields)-1], "-")
+	if len(times) != 2 {
+		return mw, fmt.Errorf("invalid maintenance window %q", s)
+	}
+	var err error
+	if mw.Start, err = parseClock(times[0]); err != nil {
+		return mw, err
+	}
+	if mw.End, err = parseClock(times[1]); err != nil {
+		return mw, err
+	}
+	return mw, nil
+}
+
+// ParseMaintenanceWindows - parse windows separated by semicolons, ie;
+// "22:00-06:00; sat,sun 00:00-23:59", none for an empty string
+func ParseMaintenanceWindows(s string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, w := range strings.Split(s, ";") {
+		if strings.TrimSpace(w) == "" {
+			continue
+		}
+		mw, err := ParseMaintenanceWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, mw)
+	}
+	return windows, nil
+}
+
+// parseClock - parse hh:mm into an offset from midnight
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains - true when t falls inside the window
+func (mw MaintenanceWindow) Contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	if mw.Start <= mw.End {
+		return mw.onDay(t.Weekday()) && offset >= mw.Start && offset < mw.End
+	}
+	// wraps midnight, the early hours belong to the previous day's window
+	if offset >= mw.Start {
+		return mw.onDay(t.Weekday())
+	}
+	return offset < mw.End && mw.onDay(midnight.AddDate(0, 0, -1).Weekday())
+}
+
+func (mw MaintenanceWindow) onDay(wd time.Weekday) bool {
+	if len(mw.Days) == 0 {
+		return true
+	}
+	for _, d := range mw.Days {
+		if d == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// BusyError - the appliance asked us to come back later, Err is the call
+// that was refused when there is one
+type BusyError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *BusyError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("appliance is busy, retry after %s", e.RetryAfter)
+}
+
+// Unwrap - the refused call
+func (e *BusyError) Unwrap() error {
+	return e.Err
+}
+
+// busyFromResponse - a BusyError for 429 and 503 responses, nil otherwise
+func busyFromResponse(resp *http.Response, now time.Time) error {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil
+	}
+	return &BusyError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), now)}
+}
+
+// parseRetryAfter - Retry-After is either delay seconds or an http date
+func parseRetryAfter(h string, now time.Time) time.Duration {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return defaultBusyRetry
+	}
+	if secs, err := strconv.Atoi(h); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultBusyRetry
+}
+
+// Operation - a queued bulk change, Kind selects the registered handler
+type Operation struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	NotBefore time.Time       `json:"notBefore,omitempty"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"lastError,omitempty"`
+}
+
+// OperationFunc - runs one queued operation, return a *BusyError to have it
+// deferred instead of failed
+type OperationFunc func(op Operation) error
+
+// Scheduler - holds bulk mutating operations until the appliance is inside a
+// maintenance window and not busy.  The queue is saved to QueuePath after
+// every change so work survives the process exiting, and several processes
+// can share it.
+type Scheduler struct {
+	Windows   []MaintenanceWindow
+	QueuePath string
+	// MaxAttempts - drop an operation after this many failures, 0 keeps retrying
+	MaxAttempts int
+
+	mu       sync.Mutex
+	queue    []Operation
+	handlers map[string]OperationFunc
+	// running - ids of operations a RunPending is running, they stay in
+	// the saved queue until they are done
+	running map[string]bool
+	now     func() time.Time
+}
+
+// NewScheduler - load or create the queue stored at queuePath
+func NewScheduler(queuePath string, windows []MaintenanceWindow) (*Scheduler, error) {
+	s := &Scheduler{
+		Windows:   windows,
+		QueuePath: queuePath,
+		handlers:  make(map[string]OperationFunc),
+		running:   make(map[string]bool),
+		now:       time.Now,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenOperationQueue - the scheduler for bulk changes to the appliances of
+// cfg, ie; queued power operations.  The maintenance windows come from
+// ONEVIEW_MAINTENANCE_WINDOWS, none allows changes at any time, and the
+// queue is kept in ONEVIEW_OPERATION_QUEUE or else the machine store.
+func OpenOperationQueue(cfg ApplianceConfig) (*Scheduler, error) {
+	windows, err := ParseMaintenanceWindows(os.Getenv("ONEVIEW_MAINTENANCE_WINDOWS"))
+	if err != nil {
+		return nil, err
+	}
+	path := os.Getenv("ONEVIEW_OPERATION_QUEUE")
+	if path == "" {
+		path = filepath.Join(machineStorePath(), operationQueueFile)
+	}
+	s, err := NewScheduler(path, windows)
+	if err != nil {
+		return nil, err
+	}
+	s.MaxAttempts = defaultQueueAttempts
+	s.Handle(powerOperationKind, powerHandler(s, cfg))
+	return s, nil
+}
+
+// Handle - register the handler for an operation kind
+func (s *Scheduler) Handle(kind string, fn OperationFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[kind] = fn
+}
+
+// Enqueue - add an operation to the saved queue
+func (s *Scheduler) Enqueue(op Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if op.ID == "" {
+		op.ID = fmt.Sprintf("%s-%d", op.Kind, s.now().UnixNano())
+	}
+	return s.update(func(queue []Operation) []Operation {
+		return append(queue, op)
+	})
+}
+
+// Pending - copy of the operations still queued
+func (s *Scheduler) Pending() []Operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Operation(nil), s.queue...)
+}
+
+// InWindow - true when bulk changes are allowed right now
+func (s *Scheduler) InWindow() bool {
+	if len(s.Windows) == 0 {
+		return true
+	}
+	now := s.now()
+	for _, w := range s.Windows {
+		if w.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunPending - run every due operation while inside a maintenance window,
+// returns how many operations completed.  The handlers run without the
+// lock held, so they may Enqueue more work.
+func (s *Scheduler) RunPending() (int, error) {
+	if !s.InWindow() {
+		log.Debugf("outside of maintenance windows, %d operations deferred", len(s.Pending()))
+		return 0, nil
+	}
+	ready, handlers := s.takeReady()
+	done := 0
+	// finished operations map to nil, the others to their new state
+	results := map[string]*Operation{}
+	for i := range ready {
+		if !s.InWindow() {
+			break
+		}
+		op := ready[i]
+		op.Attempts++
+		err := handlers[i](op)
+		if err == nil {
+			done++
+			results[op.ID] = nil
+			continue
+		}
+		op.LastError = err.Error()
+		var busy *BusyError
+		if errors.As(err, &busy) {
+			log.Infof("appliance busy, deferring %s for %s", op.ID, busy.RetryAfter)
+			op.NotBefore = s.now().Add(busy.RetryAfter)
+			results[op.ID] = &op
+			// everything after this would hit the same busy appliance
+			break
+		}
+		if s.MaxAttempts > 0 && op.Attempts >= s.MaxAttempts {
+			log.Errorf("dropping operation %s after %d attempts : %s", op.ID, op.Attempts, err)
+			results[op.ID] = nil
+			continue
+		}
+		log.Warnf("operation %s failed, will retry : %s", op.ID, err)
+		results[op.ID] = &op
+	}
+	return done, s.finish(ready, results)
+}
+
+// takeReady - the due operations with a handler, in queue order, marked as
+// running so another RunPending leaves them alone
+func (s *Scheduler) takeReady() ([]Operation, []OperationFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// pick up what other processes queued or finished since
+	if err := s.reload(); err != nil {
+		log.Warnf("using the operation queue as last read : %s", err)
+	}
+	var ready []Operation
+	var handlers []OperationFunc
+	for _, op := range s.queue {
+		fn, ok := s.handlers[op.Kind]
+		if !ok || s.running[op.ID] || s.now().Before(op.NotBefore) {
+			continue
+		}
+		s.running[op.ID] = true
+		ready = append(ready, op)
+		handlers = append(handlers, fn)
+	}
+	return ready, handlers
+}
+
+// finish - put the results of ran operations in the queue and save it
+func (s *Scheduler) finish(ran []Operation, results map[string]*Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, op := range ran {
+		delete(s.running, op.ID)
+	}
+	return s.update(func(queue []Operation) []Operation {
+		var remaining []Operation
+		for _, op := range queue {
+			result, ok := results[op.ID]
+			switch {
+			case !ok:
+				remaining = append(remaining, op)
+			case result != nil:
+				remaining = append(remaining, *result)
+			}
+		}
+		return remaining
+	})
+}
+
+// update - apply change to the saved queue.  Other processes may share the
+// queue file, so change is applied to the queue as saved, read under the
+// queue lock, and not to the copy in memory.
+func (s *Scheduler) update(change func(queue []Operation) []Operation) error {
+	if s.QueuePath == "" {
+		s.queue = change(s.queue)
+		return nil
+	}
+	unlock, err := lockQueueFile(s.QueuePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.queue = change(s.queue)
+	return s.save()
+}
+
+// reload - read the saved queue under the queue lock
+func (s *Scheduler) reload() error {
+	if s.QueuePath == "" {
+		return nil
+	}
+	unlock, err := lockQueueFile(s.QueuePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.load()
+}
+
+// load - read the saved queue, an empty queue when there is none yet
+func (s *Scheduler) load() error {
+	if s.QueuePath == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(s.QueuePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var queue []Operation
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &queue); err != nil {
+			return fmt.Errorf("unable to read operation queue %s : %w", s.QueuePath, err)
+		}
+	}
+	s.queue = queue
+	return nil
+}
+
+// lockQueueFile - hold the lock file next to the queue, it is created
+// exclusively so this works the same on every platform the driver is built
+// for.  A lock older than queueLockStale is taken over, the queue is only
+// locked while it is read and written.
+func lockQueueFile(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(queueLockWait)
+	for {
+		f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > queueLockStale {
+			log.Warnf("taking over stale operation queue lock %s", lock)
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("operation queue %s is locked, remove %s when no other docker-machine is running", path, lock)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// save - write the queue atomically, the caller holds the queue lock
+func (s *Scheduler) save() error {
+	if s.QueuePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.queue, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.QueuePath), ".queue")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.QueuePath)
+}
@@ -0,0 +1,211 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	mw, err := ParseMaintenanceWindow("sat 22:00-06:00")
+	assert.NoError(t, err)
+
+	// 2016-08-20 is a saturday
+	assert.True(t, mw.Contains(time.Date(2016, 8, 20, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, mw.Contains(time.Date(2016, 8, 21, 5, 59, 0, 0, time.UTC)))
+	assert.False(t, mw.Contains(time.Date(2016, 8, 21, 6, 0, 0, 0, time.UTC)))
+	assert.False(t, mw.Contains(time.Date(2016, 8, 21, 23, 0, 0, 0, time.UTC)))
+	assert.False(t, mw.Contains(time.Date(2016, 8, 20, 5, 0, 0, 0, time.UTC)))
+
+	mw, err = ParseMaintenanceWindow("01:00-02:00")
+	assert.NoError(t, err)
+	assert.True(t, mw.Contains(time.Date(2016, 8, 17, 1, 30, 0, 0, time.UTC)))
+	assert.False(t, mw.Contains(time.Date(2016, 8, 17, 2, 30, 0, 0, time.UTC)))
+
+	_, err = ParseMaintenanceWindow("someday 01:00-02:00")
+	assert.Error(t, err)
+	_, err = ParseMaintenanceWindow("25:00-02:00")
+	assert.Error(t, err)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2016, 8, 20, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, defaultBusyRetry, parseRetryAfter("", now))
+	assert.Equal(t, defaultBusyRetry, parseRetryAfter("soon", now))
+}
+
+func TestSchedulerDefersAndPersists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oneview-scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	queue := filepath.Join(dir, "queue.json")
+
+	now := time.Date(2016, 8, 20, 12, 0, 0, 0, time.UTC)
+	mw, _ := ParseMaintenanceWindow("22:00-06:00")
+	s, err := NewScheduler(queue, []MaintenanceWindow{mw})
+	assert.NoError(t, err)
+
+	var ran []string
+	busy := true
+	setup := func(s *Scheduler) {
+		s.now = func() time.Time { return now }
+		s.MaxAttempts = 1
+		s.Handle("poweroff", func(op Operation) error {
+			if busy {
+				return &BusyError{RetryAfter: time.Minute}
+			}
+			ran = append(ran, op.ID)
+			return nil
+		})
+		s.Handle("broken", func(op Operation) error { return errors.New("nope") })
+	}
+	setup(s)
+
+	assert.NoError(t, s.Enqueue(Operation{ID: "a", Kind: "poweroff"}))
+	assert.NoError(t, s.Enqueue(Operation{ID: "b", Kind: "broken"}))
+
+	// outside of the window nothing runs
+	done, err := s.RunPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, done)
+
+	// a new scheduler picks up the saved queue
+	now = time.Date(2016, 8, 20, 23, 0, 0, 0, time.UTC)
+	s, err = NewScheduler(queue, []MaintenanceWindow{mw})
+	assert.NoError(t, err)
+	setup(s)
+	assert.Len(t, s.Pending(), 2)
+
+	// busy appliance defers everything
+	done, err = s.RunPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, done)
+	assert.Len(t, s.Pending(), 2)
+
+	busy = false
+	now = now.Add(2 * time.Minute)
+	done, err = s.RunPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, done)
+	assert.Equal(t, []string{"a"}, ran)
+	// broken was dropped after MaxAttempts
+	assert.Len(t, s.Pending(), 0)
+}
+
+func TestSchedulerHandlerEnqueues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oneview-scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	queue := filepath.Join(dir, "queue.json")
+
+	s, err := NewScheduler(queue, nil)
+	assert.NoError(t, err)
+	s.Handle("split", func(op Operation) error {
+		// the queue is saved with the running operation still in it
+		saved, err := NewScheduler(queue, nil)
+		assert.NoError(t, err)
+		assert.Len(t, saved.Pending(), 1)
+		return s.Enqueue(Operation{ID: "later", Kind: "split", NotBefore: time.Now().Add(time.Hour)})
+	})
+	assert.NoError(t, s.Enqueue(Operation{ID: "first", Kind: "split"}))
+
+	done, err := s.RunPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, done)
+	pending := s.Pending()
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "later", pending[0].ID)
+}
+
+func TestSchedulersShareQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oneview-scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	queue := filepath.Join(dir, "queue.json")
+
+	first, err := NewScheduler(queue, nil)
+	assert.NoError(t, err)
+	second, err := NewScheduler(queue, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, first.Enqueue(Operation{ID: "a", Kind: "poweroff"}))
+	// second read the queue before a was added, saving must not drop it
+	assert.NoError(t, second.Enqueue(Operation{ID: "b", Kind: "poweron"}))
+	assert.Len(t, second.Pending(), 2)
+
+	first.Handle("poweroff", func(op Operation) error {
+		return second.Enqueue(Operation{ID: "c", Kind: "poweron"})
+	})
+	done, err := first.RunPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, done)
+
+	saved, err := NewScheduler(queue, nil)
+	assert.NoError(t, err)
+	var ids []string
+	for _, op := range saved.Pending() {
+		ids = append(ids, op.ID)
+	}
+	assert.Equal(t, []string{"b", "c"}, ids)
+	_, err = os.Stat(queue + ".lock")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLockQueueFileStale(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oneview-scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	queue := filepath.Join(dir, "queue.json")
+
+	// left behind by a process that died
+	assert.NoError(t, ioutil.WriteFile(queue+".lock", nil, 0600))
+	old := time.Now().Add(-2 * queueLockStale)
+	assert.NoError(t, os.Chtimes(queue+".lock", old, old))
+
+	unlock, err := lockQueueFile(queue)
+	assert.NoError(t, err)
+	unlock()
+	_, err = os.Stat(queue + ".lock")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRunPendingWrappedBusy(t *testing.T) {
+	now := time.Date(2016, 8, 20, 12, 0, 0, 0, time.UTC)
+	s, err := NewScheduler("", nil)
+	assert.NoError(t, err)
+	s.now = func() time.Time { return now }
+	s.Handle("poweroff", func(op Operation) error {
+		return fmt.Errorf("power off : %w", &BusyError{RetryAfter: time.Minute})
+	})
+	assert.NoError(t, s.Enqueue(Operation{ID: "a", Kind: "poweroff"}))
+
+	done, err := s.RunPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, done)
+	pending := s.Pending()
+	assert.Len(t, pending, 1)
+	assert.Equal(t, now.Add(time.Minute), pending[0].NotBefore)
+}
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	windows, err := ParseMaintenanceWindows("22:00-06:00; sat,sun 00:00-23:59")
+	assert.NoError(t, err)
+	assert.Len(t, windows, 2)
+	assert.Equal(t, []time.Weekday{time.Saturday, time.Sunday}, windows[1].Days)
+
+	windows, err = ParseMaintenanceWindows("")
+	assert.NoError(t, err)
+	assert.Empty(t, windows)
+
+	_, err = ParseMaintenanceWindows("22:00-06:00;never")
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	scopesURI = "/rest/scopes"
+	// scopesAPIVersion - first api version with scopes, lists take a
+	// scopeUris query parameter from then on
+	scopesAPIVersion = 300
+	// initialScopesAPIVersion - first api version taking initialScopeUris
+	// when a resource is created
+	initialScopesAPIVersion = 600
+)
+
+var (
+	// ErrScopesUnsupported - the appliance api predates scopes
+	ErrScopesUnsupported = errors.New("Scopes need OneView api version 300 or later")
+	// ErrScopeNotFound - no scope has the name
+	ErrScopeNotFound = errors.New("Scope not found")
+)
+
+// Scope - a set of resources a team is given access to on a shared
+// appliance
+type Scope struct {
+	Type        string `json:"type,omitempty"`
+	URI         string `json:"uri,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetScopeByName - the named scope, ErrScopeNotFound when there is none
+func GetScopeByName(c *ov.OVClient, name string) (Scope, error) {
+	var s Scope
+	if c.APIVersion < scopesAPIVersion {
+		return s, ErrScopesUnsupported
+	}
+	uri, err := findURIByName(c, scopesURI, name)
+	if err != nil {
+		return s, err
+	}
+	if uri == "" {
+		return s, fmt.Errorf("%w: %s", ErrScopeNotFound, name)
+	}
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// scopeURIByName - uri of the named scope, empty for no scope
+func scopeURIByName(c *ov.OVClient, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	s, err := GetScopeByName(c, name)
+	return s.URI, err
+}
+
+// scopeChange - profile change creating the profile in the scope, so the
+// team listing its scope sees the machine.  Appliances that can not create
+// straight into a scope leave it out with a warning.
+func scopeChange(apiVersion int, scopeURI string) profileChange {
+	return func(profile map[string]interface{}) (bool, error) {
+		if scopeURI == "" {
+			return false, nil
+		}
+		if apiVersion < initialScopesAPIVersion {
+			log.Warnf("OneView api version %d can not create the profile in scope %s, add it to the scope on the appliance", apiVersion, scopeURI)
+			return false, nil
+		}
+		profile["initialScopeUris"] = []interface{}{scopeURI}
+		return true, nil
+	}
+}
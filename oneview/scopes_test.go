@@ -0,0 +1,36 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListScope(t *testing.T) {
+	opts := ListOptions{Scope: "/rest/scopes/team1"}
+	assert.Equal(t, "/rest/scopes/team1", opts.query()["scopeUris"])
+	assert.NotContains(t, ListOptions{}.query(), "scopeUris")
+
+	c := &ov.OVClient{}
+	c.APIVersion = 200
+	err := listOrdered(c, serverProfilesURI, opts, func(json.RawMessage) error { return nil })
+	assert.Equal(t, ErrScopesUnsupported, err)
+	assert.True(t, HardwareRequirements{AllowUnhealthy: true, Scope: "team1"}.isSet())
+}
+
+func TestScopeChange(t *testing.T) {
+	profile := map[string]interface{}{}
+	changed, err := scopeChange(600, "")(profile)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, _ = scopeChange(500, "/rest/scopes/team1")(profile)
+	assert.False(t, changed)
+	assert.NotContains(t, profile, "initialScopeUris")
+
+	changed, _ = scopeChange(600, "/rest/scopes/team1")(profile)
+	assert.True(t, changed)
+	assert.Equal(t, []interface{}{"/rest/scopes/team1"}, profile["initialScopeUris"])
+}
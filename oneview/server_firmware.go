@@ -0,0 +1,82 @@
+package oneview
+
+import (
+	"encoding/json"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// FirmwareComponent - one piece of installed firmware, ie; the system rom
+// or a nic
+type FirmwareComponent struct {
+	Name     string `json:"componentName"`
+	Version  string `json:"componentVersion"`
+	Key      string `json:"componentKey,omitempty"`
+	Location string `json:"componentLocation,omitempty"`
+}
+
+// ServerFirmware - what firmware a server runs and the baseline its profile
+// manages it with, for compliance checks
+type ServerFirmware struct {
+	ServerHardwareURI string `json:"serverHardwareUri"`
+	// RomVersion - the system rom, ie; I36 v2.52 (10/21/2016)
+	RomVersion string `json:"romVersion,omitempty"`
+	// IloVersion - firmware of the management processor
+	IloVersion string              `json:"iloVersion,omitempty"`
+	Components []FirmwareComponent `json:"components"`
+	// BaselineURI - the firmware baseline of the server profile, empty when
+	// the profile does not manage firmware or there is no profile
+	BaselineURI string `json:"baselineUri,omitempty"`
+}
+
+// Component - the installed component with the name, false when there is
+// none
+func (f ServerFirmware) Component(name string) (FirmwareComponent, bool) {
+	for _, c := range f.Components {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return FirmwareComponent{}, false
+}
+
+// GetServerFirmware - the firmware inventory of the server hardware at uri
+func GetServerFirmware(c *ov.OVClient, uri string) (ServerFirmware, error) {
+	f := ServerFirmware{ServerHardwareURI: uri}
+	h, err := GetServerHardware(c, uri)
+	if err != nil {
+		return f, err
+	}
+	f.RomVersion, f.IloVersion = h.RomVersion, h.MpFirmwareVersion
+	data, err := ovCall(c, rest.GET, uri+"/firmware", nil)
+	if err != nil {
+		return f, err
+	}
+	var inventory struct {
+		Components []FirmwareComponent `json:"components"`
+	}
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return f, err
+	}
+	f.Components = inventory.Components
+	if h.ServerProfileURI != "" {
+		profile, err := getResourceMap(c, h.ServerProfileURI)
+		if err != nil {
+			return f, err
+		}
+		f.BaselineURI = profileBaselineURI(profile)
+	}
+	return f, nil
+}
+
+// profileBaselineURI - the firmware baseline a raw profile manages the
+// server with, empty when it leaves firmware alone
+func profileBaselineURI(profile map[string]interface{}) string {
+	fw, _ := profile["firmware"].(map[string]interface{})
+	if manage, _ := fw["manageFirmware"].(bool); !manage {
+		return ""
+	}
+	uri, _ := fw["firmwareBaselineUri"].(string)
+	return uri
+}
@@ -0,0 +1,110 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// ErrServerHardwareNotFound - no server hardware has the name
+var ErrServerHardwareNotFound = errors.New("Server hardware not found")
+
+// MpIPAddress - an address of the management processor, the iLO
+type MpIPAddress struct {
+	Address string `json:"address,omitempty"`
+	// Type - Static, DHCP, SLAAC, LinkLocal or Undefined
+	Type string `json:"type,omitempty"`
+}
+
+// MpHostInfo - how the management processor is reached
+type MpHostInfo struct {
+	MpHostName    string        `json:"mpHostName,omitempty"`
+	MpIPAddresses []MpIPAddress `json:"mpIpAddresses,omitempty"`
+}
+
+// ServerHardwareDetail - a compute node with its power, iLO, position and
+// serial numbers, ov.ServerHardware has none of the iLO or position details
+type ServerHardwareDetail struct {
+	ServerHardwareInventory
+	UUID                string     `json:"uuid,omitempty"`
+	VirtualSerialNumber string     `json:"virtualSerialNumber,omitempty"`
+	PartNumber          string     `json:"partNumber,omitempty"`
+	ServerName          string     `json:"serverName,omitempty"`
+	MpModel             string     `json:"mpModel,omitempty"`
+	MpFirmwareVersion   string     `json:"mpFirmwareVersion,omitempty"`
+	RomVersion          string     `json:"romVersion,omitempty"`
+	MpHostInfo          MpHostInfo `json:"mpHostInfo,omitempty"`
+	RefreshState        string     `json:"refreshState,omitempty"`
+	// Position - bay of a blade in its enclosure, 0 for rack servers
+	Position int `json:"position,omitempty"`
+}
+
+// IloIPAddress - the iLO address to connect to, a configured address before
+// a link local one, empty when the iLO reports none
+func (h ServerHardwareDetail) IloIPAddress() string {
+	best, rank := "", 0
+	for _, a := range h.MpHostInfo.MpIPAddresses {
+		r := 1
+		switch a.Type {
+		case "Static", "DHCP":
+			r = 3
+		case "SLAAC":
+			r = 2
+		case "LinkLocal":
+			continue
+		}
+		if a.Address != "" && r > rank {
+			best, rank = a.Address, r
+		}
+	}
+	return best
+}
+
+// GetServerHardware - the server hardware at uri
+func GetServerHardware(c *ov.OVClient, uri string) (ServerHardwareDetail, error) {
+	var h ServerHardwareDetail
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return h, err
+	}
+	err = json.Unmarshal(data, &h)
+	return h, err
+}
+
+// GetServerHardwareByName - the named server hardware, ie; "Encl1, bay 3",
+// ErrServerHardwareNotFound when there is none
+func GetServerHardwareByName(c *ov.OVClient, name string) (ServerHardwareDetail, error) {
+	list, err := GetServerHardwareList(c, applianceFilter("name", name))
+	if err != nil {
+		return ServerHardwareDetail{}, err
+	}
+	for _, h := range list {
+		if h.Name == name {
+			return h, nil
+		}
+	}
+	return ServerHardwareDetail{}, fmt.Errorf("%w: %s", ErrServerHardwareNotFound, name)
+}
+
+// GetServerHardwareList - every server hardware matching the appliance
+// filter across all pages, empty filter lists them all
+func GetServerHardwareList(c *ov.OVClient, filter string) ([]ServerHardwareDetail, error) {
+	var opts ListOptions
+	if filter = strings.TrimSpace(filter); filter != "" {
+		opts.Filters = []string{filter}
+	}
+	var list []ServerHardwareDetail
+	err := listOrdered(c, serverHardwareURI, opts, func(members json.RawMessage) error {
+		var page []ServerHardwareDetail
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
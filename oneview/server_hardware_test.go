@@ -0,0 +1,50 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerHardwareDetail(t *testing.T) {
+	var h ServerHardwareDetail
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"uri": "/rest/server-hardware/h1",
+		"name": "Encl1, bay 3",
+		"serialNumber": "VCGE9KB041",
+		"powerState": "On",
+		"position": 3,
+		"locationUri": "/rest/enclosures/e1",
+		"mpHostInfo": {
+			"mpHostName": "ilo-bay3",
+			"mpIpAddresses": [
+				{"address": "fe80::1", "type": "LinkLocal"},
+				{"address": "fd00::3", "type": "SLAAC"},
+				{"address": "10.0.0.3", "type": "DHCP"}
+			]
+		}
+	}`), &h))
+	assert.Equal(t, "Encl1, bay 3", h.Name)
+	assert.Equal(t, "VCGE9KB041", h.SerialNumber)
+	assert.Equal(t, "On", h.PowerState)
+	assert.Equal(t, 3, h.Position)
+	assert.False(t, h.isRackServer())
+	assert.Equal(t, "10.0.0.3", h.IloIPAddress())
+
+	h.MpHostInfo.MpIPAddresses = []MpIPAddress{{Address: "fe80::1", Type: "LinkLocal"}}
+	assert.Equal(t, "", h.IloIPAddress())
+}
+
+func TestServerHardwareNeedsRefresh(t *testing.T) {
+	h := ServerHardwareDetail{}
+	h.State = "Unknown"
+	h.RefreshState = RefreshStateNotRefreshing
+	assert.True(t, h.NeedsRefresh())
+	h.RefreshState = RefreshStateRefreshing
+	assert.False(t, h.NeedsRefresh())
+	h.State, h.RefreshState = "NoProfileApplied", RefreshStateFailed
+	assert.True(t, h.NeedsRefresh())
+	h.RefreshState = RefreshStateNotRefreshing
+	assert.False(t, h.NeedsRefresh())
+}
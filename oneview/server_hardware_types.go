@@ -0,0 +1,93 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+const serverHardwareTypesURI = "/rest/server-hardware-types"
+
+// ErrServerHardwareTypeNotFound - no server hardware type has the name
+var ErrServerHardwareTypeNotFound = errors.New("Server hardware type not found")
+
+// ErrServerHardwareTypeMismatch - --oneview-server-hardware-type is not the
+// type the server template is for
+var ErrServerHardwareTypeMismatch = errors.New("Invalid option --oneview-server-hardware-type, the server template is for another server hardware type")
+
+// ServerHardwareType - a model of server hardware, profiles are made for a
+// type rather than for one server
+type ServerHardwareType struct {
+	Type        string `json:"type,omitempty"`
+	URI         string `json:"uri,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Model       string `json:"model,omitempty"`
+	Family      string `json:"family,omitempty"`
+	FormFactor  string `json:"formFactor,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetServerHardwareTypes - hardware types matching the filter, empty for all
+func GetServerHardwareTypes(c *ov.OVClient, filter string) ([]ServerHardwareType, error) {
+	var opts ListOptions
+	if filter = strings.TrimSpace(filter); filter != "" {
+		opts.Filters = []string{filter}
+	}
+	var list []ServerHardwareType
+	err := listOrdered(c, serverHardwareTypesURI, opts, func(members json.RawMessage) error {
+		var page []ServerHardwareType
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// GetServerHardwareTypeByName - the named server hardware type, ie;
+// "BL460c Gen9 1", ErrServerHardwareTypeNotFound when there is none
+func GetServerHardwareTypeByName(c *ov.OVClient, name string) (ServerHardwareType, error) {
+	list, err := GetServerHardwareTypes(c, applianceFilter("name", name))
+	if err != nil {
+		return ServerHardwareType{}, err
+	}
+	for _, t := range list {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return ServerHardwareType{}, fmt.Errorf("%w: %s", ErrServerHardwareTypeNotFound, name)
+}
+
+// serverHardwareTypeURI - the uri of the --oneview-server-hardware-type
+// type, empty when the template decides
+func (d *Driver) serverHardwareTypeURI() (string, error) {
+	if d.HardwareRequirements.ServerHardwareType == "" {
+		return "", nil
+	}
+	t, err := GetServerHardwareTypeByName(d.ClientOV, d.HardwareRequirements.ServerHardwareType)
+	if err != nil {
+		return "", err
+	}
+	return t.URI, nil
+}
+
+// templateHardwareType - the uri of the --oneview-server-hardware-type to
+// create the machine on, empty when the template's own type is used.  The
+// connections and bios settings of a template only fit its own type, so
+// the option may only name a type for a template that has none.
+func (d *Driver) templateHardwareType(template map[string]interface{}) (string, error) {
+	sht, err := d.serverHardwareTypeURI()
+	if err != nil || sht == "" {
+		return "", err
+	}
+	own, _ := template["serverHardwareTypeUri"].(string)
+	if own != "" && own != sht {
+		return "", fmt.Errorf("%w: %s is for %s, not %s", ErrServerHardwareTypeMismatch, d.ServerTemplate, own, d.HardwareRequirements.ServerHardwareType)
+	}
+	return sht, nil
+}
@@ -0,0 +1,82 @@
+package oneview
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// ErrSessionTokenRevoked - a client made from a session token was used
+// after it was revoked
+var ErrSessionTokenRevoked = errors.New("Session token client was revoked, ask for a new one")
+
+// tokenClients - clients made from a session token, they never log in or
+// out themselves as the session belongs to whoever minted it
+var tokenClients = struct {
+	sync.Mutex
+	clients map[*ov.OVClient]bool
+}{clients: map[*ov.OVClient]bool{}}
+
+// NewOVClientFromToken - a client using an existing session of the OneView
+// appliance at endpoint, so workers can call the appliance without being
+// given credentials.  The client has the full rights of the session and
+// never logs in or out.
+func NewOVClientFromToken(endpoint, token string, sslVerify bool, apiVersion int) (*ov.OVClient, error) {
+	if token == "" {
+		return nil, fmt.Errorf("a session token is required")
+	}
+	endpoint, err := normalizeEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var c *ov.OVClient
+	c = c.NewOVClient("", "", "", endpoint, sslVerify, apiVersion)
+	c.APIKey = token
+	tokenClients.Lock()
+	tokenClients.clients[c] = true
+	tokenClients.Unlock()
+	return c, nil
+}
+
+// DelegateOVClient - a token client sharing the session of c, logging c in
+// first when it has no session yet
+func DelegateOVClient(c *ov.OVClient) (*ov.OVClient, error) {
+	if err := authorizeOV(c); err != nil {
+		return nil, err
+	}
+	return NewOVClientFromToken(c.Endpoint, c.APIKey, c.SSLVerify, c.APIVersion)
+}
+
+// RevokeOVClient - forget a token client, the calls this package makes with
+// it fail from then on.  The session itself stays logged in.
+func RevokeOVClient(c *ov.OVClient) {
+	tokenClients.Lock()
+	defer tokenClients.Unlock()
+	delete(tokenClients.clients, c)
+}
+
+// isTokenClient - true for clients made from a session token
+func isTokenClient(c *ov.OVClient) bool {
+	tokenClients.Lock()
+	defer tokenClients.Unlock()
+	return tokenClients.clients[c]
+}
+
+// authorizeOV - log clients in again when their session expired, token
+// clients never log in
+func authorizeOV(c *ov.OVClient) error {
+	if err := checkPinnedEndpoint(c.Endpoint); err != nil {
+		return err
+	}
+	if isTokenClient(c) {
+		return nil
+	}
+	// only token clients have no user, one that is no longer known was
+	// revoked and must not try to log in
+	if c.User == "" && c.APIKey != "" {
+		return ErrSessionTokenRevoked
+	}
+	return refreshOV(c)
+}
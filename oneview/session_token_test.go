@@ -0,0 +1,29 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenClient(t *testing.T) {
+	_, err := NewOVClientFromToken("https://ov", "", false, 800)
+	assert.Error(t, err)
+
+	c, err := NewOVClientFromToken("https://ov", "session-1", false, 800)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", c.APIKey)
+	assert.True(t, isTokenClient(c))
+	assert.NoError(t, authorizeOV(c))
+
+	d, err := DelegateOVClient(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", d.APIKey)
+	assert.True(t, isTokenClient(d))
+
+	RevokeOVClient(c)
+	assert.False(t, isTokenClient(c))
+	assert.Equal(t, ErrSessionTokenRevoked, authorizeOV(c), "and never logs in")
+	assert.NoError(t, authorizeOV(d))
+	RevokeOVClient(d)
+}
@@ -0,0 +1,98 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+const (
+	globalSettingsURI     = "/rest/global-settings"
+	sessionIdleTimeoutURI = "/rest/sessions/idle-timeout"
+
+	// minSessionRefresh - never refresh a session more often than this
+	minSessionRefresh = 30 * time.Second
+)
+
+// GlobalSetting - a named appliance setting
+type GlobalSetting struct {
+	URI   string `json:"uri,omitempty"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+	ETag  string `json:"eTag,omitempty"`
+}
+
+// GetGlobalSetting - read an appliance setting by name
+func GetGlobalSetting(c *ov.OVClient, name string) (GlobalSetting, error) {
+	var s GlobalSetting
+	data, err := ovCall(c, rest.GET, globalSettingsURI+"/"+name, nil)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// SetGlobalSetting - change an appliance setting
+func SetGlobalSetting(c *ov.OVClient, name, value string) error {
+	s, err := GetGlobalSetting(c, name)
+	if err != nil {
+		return err
+	}
+	if s.Value == value {
+		return nil
+	}
+	s.Value = value
+	_, err = ovCall(c, rest.PUT, globalSettingsURI+"/"+name, s)
+	return err
+}
+
+// SessionSettings - how long the appliance keeps an unused session
+type SessionSettings struct {
+	IdleTimeout time.Duration
+}
+
+// RefreshInterval - how often a client should use or refresh its session
+// to keep it, well inside the idle timeout
+func (s SessionSettings) RefreshInterval() time.Duration {
+	if s.IdleTimeout <= 0 {
+		return 0
+	}
+	interval := s.IdleTimeout / 2
+	if interval < minSessionRefresh {
+		interval = minSessionRefresh
+	}
+	return interval
+}
+
+// idleTimeoutBody - the idle timeout resource, in milliseconds
+type idleTimeoutBody struct {
+	IdleTimeout int64 `json:"idleTimeout"`
+}
+
+// GetSessionSettings - read the session idle timeout
+func GetSessionSettings(c *ov.OVClient) (SessionSettings, error) {
+	data, err := ovCall(c, rest.GET, sessionIdleTimeoutURI, nil)
+	if err != nil {
+		return SessionSettings{}, err
+	}
+	var body idleTimeoutBody
+	if err := json.Unmarshal(data, &body); err != nil {
+		return SessionSettings{}, err
+	}
+	return SessionSettings{IdleTimeout: time.Duration(body.IdleTimeout) * time.Millisecond}, nil
+}
+
+// SetSessionIdleTimeout - change the session idle timeout, ie; to outlast
+// long provisioning operations
+func SetSessionIdleTimeout(c *ov.OVClient, timeout time.Duration) error {
+	if timeout < time.Minute {
+		return fmt.Errorf("session idle timeout %s is shorter than a minute", timeout)
+	}
+	_, err := ovCall(c, rest.POST, sessionIdleTimeoutURI, idleTimeoutBody{IdleTimeout: int64(timeout / time.Millisecond)})
+	return err
+}
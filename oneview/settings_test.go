@@ -0,0 +1,14 @@
+package oneview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRefreshInterval(t *testing.T) {
+	assert.Equal(t, 12*time.Hour, SessionSettings{IdleTimeout: 24 * time.Hour}.RefreshInterval())
+	assert.Equal(t, minSessionRefresh, SessionSettings{IdleTimeout: 40 * time.Second}.RefreshInterval())
+	assert.Equal(t, time.Duration(0), SessionSettings{}.RefreshInterval())
+}
@@ -0,0 +1,48 @@
+package oneview
+
+import "sync"
+
+// flightCall - a call in progress, or finished, for one key
+type flightCall struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+	// dups - callers that waited on this call rather than making their own
+	dups int
+}
+
+// flightGroup - collapses concurrent calls for the same key into one, the
+// callers that arrive while it runs share its result
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// Do - run fn for key unless a call for key is already running, in which
+// case wait for it.  shared is true when the result went to more than one
+// caller.
+func (g *flightGroup) Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+	c := &flightCall{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.val, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	shared = c.dups > 0
+	g.mu.Unlock()
+	return c.val, c.err, shared
+}
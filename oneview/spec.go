@@ -0,0 +1,308 @@
+package oneview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"gopkg.in/yaml.v2"
+)
+
+// MachineSpec - a machine described in one file rather than many flags, see
+// --oneview-spec.  Every field maps to a create flag; flags given a value
+// other than their default win over the spec.
+type MachineSpec struct {
+	ServerTemplate string          `json:"serverTemplate,omitempty"`
+	Hardware       HardwareSpec    `json:"hardware,omitempty"`
+	Connections    ConnectionsSpec `json:"connections,omitempty"`
+	OS             OSSpec          `json:"os,omitempty"`
+	ILO            ILOSpec         `json:"ilo,omitempty"`
+	// Attributes - extra ICsp custom attributes for the os build plans
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Storage - volumes to attach to the profile, created from a volume
+	// template when they do not exist
+	Storage []StorageSpec `json:"storage,omitempty"`
+}
+
+// HardwareSpec - server hardware selection
+type HardwareSpec struct {
+	MinMemoryGb        int    `json:"minMemoryGb,omitempty"`
+	MinCores           int    `json:"minCores,omitempty"`
+	ServerHardwareType string `json:"serverHardwareType,omitempty"`
+	Generation         string `json:"generation,omitempty"`
+	BootMode           string `json:"bootMode,omitempty"`
+}
+
+// ConnectionsSpec - profile connection settings
+type ConnectionsSpec struct {
+	HideUnusedFlexNics *bool    `json:"hideUnusedFlexNics,omitempty"`
+	PortAllocation     string   `json:"portAllocation,omitempty"`
+	BootVlan           string   `json:"bootVlan,omitempty"`
+	Ports              []string `json:"ports,omitempty"`
+	PublicSlotID       int      `json:"publicSlotId,omitempty"`
+	PublicConnection   string   `json:"publicConnectionName,omitempty"`
+}
+
+// OSSpec - os deployment
+type OSSpec struct {
+	Plans   []string `json:"plans,omitempty"`
+	Timeout int      `json:"timeoutMinutes,omitempty"`
+	SSHUser string   `json:"sshUser,omitempty"`
+	SSHPort int      `json:"sshPort,omitempty"`
+}
+
+// ILOSpec - ilo account used while adding the server to ICsp
+type ILOSpec struct {
+	User string `json:"user,omitempty"`
+	Port int    `json:"port,omitempty"`
+}
+
+// StorageSpec - a volume attached to the profile
+type StorageSpec struct {
+	// Volume - name of the storage volume
+	Volume string `json:"volume"`
+	// Template - volume template to create the volume from when it does not
+	// exist, the rest of the settings only apply then
+	Template         string `json:"template,omitempty"`
+	SizeGb           int    `json:"sizeGb,omitempty"`
+	ProvisioningType string `json:"provisioningType,omitempty"`
+	Shareable        bool   `json:"shareable,omitempty"`
+}
+
+// volumeRequest - the volume to create from the template
+func (s StorageSpec) volumeRequest() VolumeRequest {
+	return VolumeRequest{Name: s.Volume, Template: s.Template, SizeGb: s.SizeGb, ProvisioningType: s.ProvisioningType, Shareable: s.Shareable}
+}
+
+// LoadMachineSpec - read and validate a spec, json or yaml
+func LoadMachineSpec(path string) (*MachineSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := parseMachineSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid --oneview-spec %s : %w", path, err)
+	}
+	return spec, nil
+}
+
+// parseMachineSpec - decode and validate a spec document
+func parseMachineSpec(data []byte) (*MachineSpec, error) {
+	var raw interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		raw = stringKeys(raw)
+	}
+	if _, ok := raw.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("spec must be a mapping")
+	}
+	if unknown := unknownFields(raw, reflect.TypeOf(MachineSpec{}), ""); len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown fields %s", strings.Join(unknown, ", "))
+	}
+	// decode through json so both formats share the struct tags
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	spec := &MachineSpec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+	return spec, spec.validate()
+}
+
+// stringKeys - yaml mappings decode with interface{} keys, turn them into
+// the string keyed maps json gives so both formats are checked alike
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = stringKeys(e)
+		}
+	}
+	return v
+}
+
+// unknownFields - paths of keys in raw with no matching json field in t
+func unknownFields(raw interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+		for k, v := range m {
+			ft, ok := fields[k]
+			if !ok {
+				unknown = append(unknown, path+k)
+				continue
+			}
+			unknown = append(unknown, unknownFields(v, ft, path+k+".")...)
+		}
+	case reflect.Slice:
+		if list, ok := raw.([]interface{}); ok {
+			for i, v := range list {
+				unknown = append(unknown, unknownFields(v, t.Elem(), fmt.Sprintf("%s%d.", path, i))...)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// validate - check values the flags would also reject
+func (s *MachineSpec) validate() error {
+	if _, err := ParseGeneration(s.Hardware.Generation); err != nil {
+		return err
+	}
+	if _, err := normalizeBootMode(s.Hardware.BootMode); err != nil {
+		return err
+	}
+	if _, err := newNetworkSettings("", s.Connections.PortAllocation, ""); err != nil {
+		return err
+	}
+	if _, err := parseBootVlan(s.Connections.BootVlan); err != nil {
+		return err
+	}
+	for name, v := range map[string]int{
+		"hardware.minMemoryGb":     s.Hardware.MinMemoryGb,
+		"hardware.minCores":        s.Hardware.MinCores,
+		"connections.publicSlotId": s.Connections.PublicSlotID,
+		"os.timeoutMinutes":        s.OS.Timeout,
+		"os.sshPort":               s.OS.SSHPort,
+		"ilo.port":                 s.ILO.Port,
+	} {
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	for _, p := range s.OS.Plans {
+		if strings.TrimSpace(p) == "" {
+			return ErrDriverMissingBuildPlanOption
+		}
+	}
+	for i, v := range s.Storage {
+		if v.Volume == "" {
+			return fmt.Errorf("storage.%d has no volume", i)
+		}
+		if v.Template != "" {
+			if err := v.volumeRequest().Validate(); err != nil {
+				return fmt.Errorf("storage.%d: %w", i, err)
+			}
+		} else if v.SizeGb != 0 || v.ProvisioningType != "" || v.Shareable {
+			return fmt.Errorf("storage.%d settings need a template", i)
+		}
+	}
+	return nil
+}
+
+// flagValues - the spec as create flag values, keyed by flag name
+func (s *MachineSpec) flagValues() map[string]interface{} {
+	values := map[string]interface{}{}
+	setString := func(name, v string) {
+		if v != "" {
+			values[name] = v
+		}
+	}
+	setInt := func(name string, v int) {
+		if v != 0 {
+			values[name] = v
+		}
+	}
+	setString("oneview-server-template", s.ServerTemplate)
+	setInt("oneview-min-memory-gb", s.Hardware.MinMemoryGb)
+	setInt("oneview-min-cores", s.Hardware.MinCores)
+	setString("oneview-server-hardware-type", s.Hardware.ServerHardwareType)
+	setString("oneview-hardware-generation", s.Hardware.Generation)
+	setString("oneview-boot-mode", s.Hardware.BootMode)
+	if s.Connections.HideUnusedFlexNics != nil {
+		values["oneview-hide-unused-flexnics"] = strconv.FormatBool(*s.Connections.HideUnusedFlexNics)
+	}
+	setString("oneview-port-allocation", s.Connections.PortAllocation)
+	setString("oneview-boot-vlan", s.Connections.BootVlan)
+	setString("oneview-connection-ports", strings.Join(s.Connections.Ports, ","))
+	setInt("oneview-public-slotid", s.Connections.PublicSlotID)
+	setString("oneview-public-connection-name", s.Connections.PublicConnection)
+	setString("oneview-os-plans", strings.Join(s.OS.Plans, ","))
+	setInt("oneview-os-timeout", s.OS.Timeout)
+	setString("oneview-ssh-user", s.OS.SSHUser)
+	setInt("oneview-ssh-port", s.OS.SSHPort)
+	setString("oneview-ilo-user", s.ILO.User)
+	setInt("oneview-ilo-port", s.ILO.Port)
+	return values
+}
+
+// specOptions - driver options where flags left at their default take the
+// value from the spec
+type specOptions struct {
+	drivers.DriverOptions
+	spec     map[string]interface{}
+	defaults map[string]interface{}
+}
+
+// newSpecOptions - layer a spec under the flags
+func newSpecOptions(flags drivers.DriverOptions, spec *MachineSpec, createFlags []mcnflag.Flag) *specOptions {
+	defaults := map[string]interface{}{}
+	for _, f := range createFlags {
+		defaults[f.String()] = f.Default()
+	}
+	return &specOptions{DriverOptions: flags, spec: spec.flagValues(), defaults: defaults}
+}
+
+// fromSpec - the spec value for key, when the flag was left at its default
+func (o *specOptions) fromSpec(key string, flag interface{}) (interface{}, bool) {
+	v, ok := o.spec[key]
+	if !ok || !reflect.DeepEqual(flag, o.defaults[key]) {
+		return nil, false
+	}
+	return v, true
+}
+
+// String - the flag, or the spec when the flag has its default
+func (o *specOptions) String(key string) string {
+	flag := o.DriverOptions.String(key)
+	if v, ok := o.fromSpec(key, flag); ok {
+		return v.(string)
+	}
+	return flag
+}
+
+// Int - the flag, or the spec when the flag has its default
+func (o *specOptions) Int(key string) int {
+	flag := o.DriverOptions.Int(key)
+	if v, ok := o.fromSpec(key, flag); ok {
+		return v.(int)
+	}
+	return flag
+}
@@ -0,0 +1,138 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpecYAML = `
+# docker host spec
+serverTemplate: DOCKER_SPEC
+hardware:
+  minMemoryGb: 128
+  generation: gen9
+  bootMode: uefi
+connections:
+  hideUnusedFlexNics: true
+  portAllocation: explicit
+  ports: ["Flb 1:1-a", 'Flb 1:2-a']
+os:
+  plans:
+  - RHEL71_DOCKER_1.8
+  - "DOCKER #2"
+  timeoutMinutes: 45
+attributes:
+  docker_version: "1.12"
+storage:
+  - volume: docker-data
+`
+
+func TestParseMachineSpecYAML(t *testing.T) {
+	spec, err := parseMachineSpec([]byte(testSpecYAML))
+	assert.NoError(t, err)
+	assert.Equal(t, "DOCKER_SPEC", spec.ServerTemplate)
+	assert.Equal(t, 128, spec.Hardware.MinMemoryGb)
+	assert.Equal(t, []string{"Flb 1:1-a", "Flb 1:2-a"}, spec.Connections.Ports)
+	assert.Equal(t, []string{"RHEL71_DOCKER_1.8", "DOCKER #2"}, spec.OS.Plans)
+	assert.Equal(t, map[string]string{"docker_version": "1.12"}, spec.Attributes)
+	assert.Equal(t, []StorageSpec{{Volume: "docker-data"}}, spec.Storage)
+	assert.True(t, *spec.Connections.HideUnusedFlexNics)
+}
+
+func TestParseMachineSpecJSON(t *testing.T) {
+	spec, err := parseMachineSpec([]byte(`{"serverTemplate": "T", "os": {"plans": ["P"]}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "T", spec.ServerTemplate)
+	assert.Equal(t, []string{"P"}, spec.OS.Plans)
+}
+
+func TestParseMachineSpecInvalid(t *testing.T) {
+	_, err := parseMachineSpec([]byte("hardware:\n  minMemory: 128\nstorage:\n- size: 10\n"))
+	assert.EqualError(t, err, "unknown fields hardware.minMemory, storage.0.size")
+
+	_, err = parseMachineSpec([]byte("hardware:\n  generation: gen7\n"))
+	assert.Error(t, err)
+
+	_, err = parseMachineSpec([]byte("hardware:\n  minCores: -1\n"))
+	assert.Error(t, err)
+
+	_, err = parseMachineSpec([]byte("- a\n- b\n"))
+	assert.Error(t, err)
+}
+
+func TestParseMachineSpecStorageTemplate(t *testing.T) {
+	spec, err := parseMachineSpec([]byte("storage:\n- volume: data\n  template: thin\n  sizeGb: 20\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, VolumeRequest{Name: "data", Template: "thin", SizeGb: 20}, spec.Storage[0].volumeRequest())
+
+	_, err = parseMachineSpec([]byte("storage:\n- volume: data\n  sizeGb: 20\n"))
+	assert.EqualError(t, err, "storage.0 settings need a template")
+
+	_, err = parseMachineSpec([]byte("storage:\n- volume: data\n  template: thin\n  provisioningType: thick\n"))
+	assert.Error(t, err)
+}
+
+type testDriverOptions map[string]interface{}
+
+func (o testDriverOptions) String(key string) string {
+	v, _ := o[key].(string)
+	return v
+}
+func (o testDriverOptions) StringSlice(key string) []string {
+	v, _ := o[key].([]string)
+	return v
+}
+func (o testDriverOptions) Int(key string) int {
+	v, _ := o[key].(int)
+	return v
+}
+func (o testDriverOptions) Bool(key string) bool {
+	v, _ := o[key].(bool)
+	return v
+}
+
+func TestSpecOptionsPrecedence(t *testing.T) {
+	spec, err := parseMachineSpec([]byte(testSpecYAML))
+	assert.NoError(t, err)
+	flags := testDriverOptions{
+		"oneview-server-template": "DOCKER_1.8_OVTEMP", // default, spec wins
+		"oneview-min-memory-gb":   256,                 // set, flag wins
+		"oneview-os-timeout":      0,
+		"oneview-ssh-user":        "docker",
+		"oneview-os-plans":        "RHEL71_DOCKER_1.8",
+	}
+	var opts drivers.DriverOptions = newSpecOptions(flags, spec, (&Driver{}).GetCreateFlags())
+	assert.Equal(t, "DOCKER_SPEC", opts.String("oneview-server-template"))
+	assert.Equal(t, 256, opts.Int("oneview-min-memory-gb"))
+	assert.Equal(t, 45, opts.Int("oneview-os-timeout"))
+	assert.Equal(t, "docker", opts.String("oneview-ssh-user"))
+	assert.Equal(t, "true", opts.String("oneview-hide-unused-flexnics"))
+	assert.Equal(t, "RHEL71_DOCKER_1.8,DOCKER #2", opts.String("oneview-os-plans"))
+}
+
+func TestParseMachineSpecYAMLErrors(t *testing.T) {
+	_, err := parseMachineSpec([]byte("serverTemplate: [a\n"))
+	assert.Error(t, err)
+	_, err = parseMachineSpec([]byte("- a\n- b\n"))
+	assert.EqualError(t, err, "spec must be a mapping")
+}
+
+func TestAttachVolumes(t *testing.T) {
+	profile := map[string]interface{}{
+		"sanStorage": map[string]interface{}{
+			"volumeAttachments": []interface{}{map[string]interface{}{"id": 1.0, "volumeUri": "/rest/storage-volumes/A"}},
+		},
+	}
+	volumes := []map[string]interface{}{
+		{"uri": "/rest/storage-volumes/A"},
+		{"uri": "/rest/storage-volumes/B", "storageSystemUri": "/rest/storage-systems/S"},
+	}
+	assert.True(t, attachVolumes(profile, volumes))
+	san := profile["sanStorage"].(map[string]interface{})
+	attachments := san["volumeAttachments"].([]interface{})
+	assert.Len(t, attachments, 2)
+	assert.Equal(t, int64(2), attachments[1].(map[string]interface{})["id"])
+	assert.False(t, attachVolumes(profile, volumes))
+}
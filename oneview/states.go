@@ -0,0 +1,123 @@
+package oneview
+
+import (
+	"encoding/json"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// ProfileState - lifecycle state of a server profile.
+//
+// A new profile goes Creating then Normal, or CreateFailed.  Changes go
+// Updating then Normal, or UpdateFailed, and removal goes Deleting, or
+// DeleteFailed.  Api 120 only reports Creating, Normal, Deleting and the
+// failed states, updates there show as Normal with inProgress set.  Api 300
+// added Applying for profiles being applied from a template and Unmanaged for
+// profiles whose hardware was removed.
+type ProfileState string
+
+// profile states
+const (
+	ProfileStateCreating     ProfileState = "Creating"
+	ProfileStateNormal       ProfileState = "Normal"
+	ProfileStateUpdating     ProfileState = "Updating"
+	ProfileStateApplying     ProfileState = "Applying"
+	ProfileStateDeleting     ProfileState = "Deleting"
+	ProfileStateCreateFailed ProfileState = "CreateFailed"
+	ProfileStateUpdateFailed ProfileState = "UpdateFailed"
+	ProfileStateDeleteFailed ProfileState = "DeleteFailed"
+	ProfileStateUnmanaged    ProfileState = "Unmanaged"
+)
+
+// IsApplying - the appliance is working on the profile
+func (s ProfileState) IsApplying() bool {
+	switch s {
+	case ProfileStateCreating, ProfileStateUpdating, ProfileStateApplying, ProfileStateDeleting:
+		return true
+	}
+	return false
+}
+
+// IsFailed - the last change to the profile failed
+func (s ProfileState) IsFailed() bool {
+	switch s {
+	case ProfileStateCreateFailed, ProfileStateUpdateFailed, ProfileStateDeleteFailed:
+		return true
+	}
+	return false
+}
+
+// IsStable - the profile is applied and nothing is in progress
+func (s ProfileState) IsStable() bool {
+	return s == ProfileStateNormal
+}
+
+// ResourceStatus - health the appliance reports for a resource
+type ResourceStatus string
+
+// resource statuses
+const (
+	StatusOK       ResourceStatus = "OK"
+	StatusWarning  ResourceStatus = "Warning"
+	StatusCritical ResourceStatus = "Critical"
+	StatusDisabled ResourceStatus = "Disabled"
+	StatusUnknown  ResourceStatus = "Unknown"
+)
+
+// IsHealthy - ok or only warnings
+func (s ResourceStatus) IsHealthy() bool {
+	return s == StatusOK || s == StatusWarning
+}
+
+// IsCritical - the resource has failed health
+func (s ResourceStatus) IsCritical() bool {
+	return s == StatusCritical
+}
+
+// TaskState - state of an appliance task
+type TaskState string
+
+// task states reported by the appliance
+const (
+	TaskStateNew         TaskState = "New"
+	TaskStatePending     TaskState = "Pending"
+	TaskStateStarting    TaskState = "Starting"
+	TaskStateRunning     TaskState = "Running"
+	TaskStateSuspended   TaskState = "Suspended"
+	TaskStateInterrupted TaskState = "Interrupted"
+	TaskStateStopping    TaskState = "Stopping"
+	TaskStateCancelling  TaskState = "Cancelling"
+	TaskStateCompleted   TaskState = "Completed"
+	TaskStateWarning     TaskState = "Warning"
+	TaskStateError       TaskState = "Error"
+	TaskStateTerminated  TaskState = "Terminated"
+	TaskStateKilled      TaskState = "Killed"
+)
+
+// IsDone - the task reached a final state
+func (s TaskState) IsDone() bool {
+	switch s {
+	case TaskStateCompleted, TaskStateWarning, TaskStateError, TaskStateTerminated, TaskStateKilled:
+		return true
+	}
+	return false
+}
+
+// IsFailed - the task finished without completing its work
+func (s TaskState) IsFailed() bool {
+	return s.IsDone() && s != TaskStateCompleted && s != TaskStateWarning
+}
+
+// getProfileState - current state of a profile
+func getProfileState(c *ov.OVClient, uri string) (ProfileState, error) {
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return "", err
+	}
+	var p ServerProfileSummary
+	if err := json.Unmarshal(data, &p); err != nil {
+		return "", err
+	}
+	return p.State, nil
+}
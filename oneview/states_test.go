@@ -0,0 +1,31 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileState(t *testing.T) {
+	assert.True(t, ProfileStateCreating.IsApplying())
+	assert.True(t, ProfileStateDeleting.IsApplying())
+	assert.False(t, ProfileStateNormal.IsApplying())
+	assert.True(t, ProfileStateUpdateFailed.IsFailed())
+	assert.False(t, ProfileStateUpdating.IsFailed())
+	assert.True(t, ProfileStateNormal.IsStable())
+	assert.False(t, ProfileState("").IsStable())
+}
+
+func TestTaskState(t *testing.T) {
+	assert.False(t, TaskStateRunning.IsDone())
+	assert.True(t, TaskStateWarning.IsDone())
+	assert.False(t, TaskStateWarning.IsFailed())
+	assert.True(t, TaskStateKilled.IsFailed())
+	assert.False(t, TaskStateCancelling.IsFailed())
+}
+
+func TestResourceStatus(t *testing.T) {
+	assert.True(t, StatusWarning.IsHealthy())
+	assert.False(t, StatusUnknown.IsHealthy())
+	assert.True(t, StatusCritical.IsCritical())
+}
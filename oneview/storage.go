@@ -0,0 +1,77 @@
+package oneview
+
+import (
+	"fmt"
+)
+
+const storageVolumesURI = "/rest/storage-volumes"
+
+// attachVolumes - add san volume attachments for volumes missing from a raw
+// profile, volumes are raw storage volume resources
+func attachVolumes(profile map[string]interface{}, volumes []map[string]interface{}) bool {
+	if len(volumes) == 0 {
+		return false
+	}
+	san, _ := profile["sanStorage"].(map[string]interface{})
+	if san == nil {
+		san = map[string]interface{}{}
+	}
+	attachments, _ := san["volumeAttachments"].([]interface{})
+	attached := map[string]bool{}
+	nextID := int64(1)
+	for _, a := range attachments {
+		if m, ok := a.(map[string]interface{}); ok {
+			if uri, _ := m["volumeUri"].(string); uri != "" {
+				attached[uri] = true
+			}
+			if id, _ := jsonInt(m["id"]); id >= nextID {
+				nextID = id + 1
+			}
+		}
+	}
+	changed := false
+	for _, v := range volumes {
+		uri, _ := v["uri"].(string)
+		if attached[uri] {
+			continue
+		}
+		attachments = append(attachments, map[string]interface{}{
+			"id":                     nextID,
+			"volumeUri":              uri,
+			"volumeStorageSystemUri": v["storageSystemUri"],
+			"lunType":                "Auto",
+		})
+		attached[uri] = true
+		nextID++
+		changed = true
+	}
+	if !changed {
+		return false
+	}
+	san["manageSanStorage"] = true
+	san["volumeAttachments"] = attachments
+	profile["sanStorage"] = san
+	return true
+}
+
+// storageChange - profile change attaching the machine storage volumes
+func (d *Driver) storageChange() profileChange {
+	return func(profile map[string]interface{}) (bool, error) {
+		var volumes []map[string]interface{}
+		for _, name := range d.StorageVolumes {
+			uri, err := findURIByName(d.ClientOV, storageVolumesURI, name)
+			if err != nil {
+				return false, err
+			}
+			if uri == "" {
+				return false, fmt.Errorf("unable to find storage volume %s", name)
+			}
+			volume, err := getResourceMap(d.ClientOV, uri)
+			if err != nil {
+				return false, err
+			}
+			volumes = append(volumes, volume)
+		}
+		return attachVolumes(profile, volumes), nil
+	}
+}
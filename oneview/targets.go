@@ -0,0 +1,124 @@
+package oneview
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	availableTargetsURI = serverProfilesURI + "/available-targets"
+	availableServersURI = serverProfilesURI + "/available-servers"
+)
+
+// AvailableTarget - somewhere a profile can go, server hardware without a
+// profile or an empty bay a profile can be assigned to ahead of the blade
+type AvailableTarget struct {
+	ServerHardwareURI     string `json:"serverHardwareUri,omitempty"`
+	ServerHardwareName    string `json:"serverHardwareName,omitempty"`
+	ServerHardwareTypeURI string `json:"serverHardwareTypeUri,omitempty"`
+	EnclosureGroupURI     string `json:"enclosureGroupUri,omitempty"`
+	EnclosureURI          string `json:"enclosureUri,omitempty"`
+	EnclosureName         string `json:"enclosureName,omitempty"`
+	EnclosureBay          int    `json:"enclosureBay,omitempty"`
+	PowerState            string `json:"powerState,omitempty"`
+}
+
+// IsEmptyBay - true for a bay with no blade in it
+func (t AvailableTarget) IsEmptyBay() bool {
+	return t.ServerHardwareURI == ""
+}
+
+// targetQuery - the placement filters, empty ones are left out
+func targetQuery(enclosureGroupURI, serverHardwareTypeURI string) map[string]interface{} {
+	q := map[string]interface{}{}
+	if enclosureGroupURI != "" {
+		q["enclosureGroupUri"] = enclosureGroupURI
+	}
+	if serverHardwareTypeURI != "" {
+		q["serverHardwareTypeUri"] = serverHardwareTypeURI
+	}
+	return q
+}
+
+// GetAvailableTargets - the hardware and empty bays a profile for the
+// enclosure group and server hardware type can be assigned to, either may be
+// empty to not filter on it
+func GetAvailableTargets(c *ov.OVClient, enclosureGroupURI, serverHardwareTypeURI string) ([]AvailableTarget, error) {
+	data, err := ovQueryCall(c, rest.GET, availableTargetsURI, targetQuery(enclosureGroupURI, serverHardwareTypeURI), nil)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Targets []AvailableTarget `json:"targets"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	sort.Sort(targetsByLocation(body.Targets))
+	return body.Targets, nil
+}
+
+// GetAvailableServers - the server hardware with no profile for the
+// enclosure group and server hardware type, older api versions only have
+// this rather than available targets
+func GetAvailableServers(c *ov.OVClient, enclosureGroupURI, serverHardwareTypeURI string) ([]AvailableTarget, error) {
+	data, err := ovQueryCall(c, rest.GET, availableServersURI, targetQuery(enclosureGroupURI, serverHardwareTypeURI), nil)
+	if err != nil {
+		return nil, err
+	}
+	var servers []AvailableTarget
+	if err := json.Unmarshal(data, &servers); err != nil {
+		return nil, err
+	}
+	sort.Sort(targetsByLocation(servers))
+	return servers, nil
+}
+
+// targetsByLocation - enclosure then bay, rack servers by name
+type targetsByLocation []AvailableTarget
+
+func (t targetsByLocation) Len() int      { return len(t) }
+func (t targetsByLocation) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+func (t targetsByLocation) Less(i, j int) bool {
+	if t[i].EnclosureName != t[j].EnclosureName {
+		return t[i].EnclosureName < t[j].EnclosureName
+	}
+	if t[i].EnclosureBay != t[j].EnclosureBay {
+		return t[i].EnclosureBay < t[j].EnclosureBay
+	}
+	return t[i].ServerHardwareName < t[j].ServerHardwareName
+}
+
+// onlyTargets - the candidates a profile can be assigned to
+func onlyTargets(candidates []ServerHardwareInventory, targets []AvailableTarget) []ServerHardwareInventory {
+	ok := map[string]bool{}
+	for _, t := range targets {
+		ok[t.ServerHardwareURI] = true
+	}
+	var placeable []ServerHardwareInventory
+	for _, h := range candidates {
+		if !ok[h.URI] {
+			log.Debugf("skipping %s : not an available target for the template", h.Name)
+			continue
+		}
+		placeable = append(placeable, h)
+	}
+	return placeable
+}
+
+// templateTargets - the available targets for a template, nil when the
+// appliance can not say so the candidates are left as they are
+func templateTargets(c *ov.OVClient, template map[string]interface{}) []AvailableTarget {
+	eg, _ := template["enclosureGroupUri"].(string)
+	sht, _ := template["serverHardwareTypeUri"].(string)
+	targets, err := GetAvailableTargets(c, eg, sht)
+	if err != nil {
+		log.Debugf("unable to read the available targets, using the hardware list : %s", err)
+		return nil
+	}
+	return targets
+}
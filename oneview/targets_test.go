@@ -0,0 +1,33 @@
+package oneview
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailableTargets(t *testing.T) {
+	var body struct {
+		Targets []AvailableTarget `json:"targets"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"type": "AvailableTargetsV3", "targets": [
+		{"enclosureName": "enc1", "enclosureBay": 3},
+		{"serverHardwareUri": "/rest/server-hardware/2", "serverHardwareName": "enc1, bay 2", "enclosureName": "enc1", "enclosureBay": 2},
+		{"serverHardwareUri": "/rest/server-hardware/1", "serverHardwareName": "enc0, bay 7", "enclosureName": "enc0", "enclosureBay": 7}
+	]}`), &body))
+	sort.Sort(targetsByLocation(body.Targets))
+	assert.Equal(t, "/rest/server-hardware/1", body.Targets[0].ServerHardwareURI)
+	assert.Equal(t, 2, body.Targets[1].EnclosureBay)
+	assert.True(t, body.Targets[2].IsEmptyBay())
+
+	candidates := []ServerHardwareInventory{{URI: "/rest/server-hardware/1"}, {URI: "/rest/server-hardware/3"}}
+	assert.Equal(t, []ServerHardwareInventory{{URI: "/rest/server-hardware/1"}}, onlyTargets(candidates, body.Targets))
+}
+
+func TestTargetQuery(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{}, targetQuery("", ""))
+	assert.Equal(t, map[string]interface{}{"enclosureGroupUri": "/rest/enclosure-groups/E", "serverHardwareTypeUri": "/rest/server-hardware-types/T"},
+		targetQuery("/rest/enclosure-groups/E", "/rest/server-hardware-types/T"))
+}
@@ -0,0 +1,190 @@
+package oneview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/machine/libmachine/log"
+)
+
+var (
+	// taskPollInterval - how often we check on a running task
+	taskPollInterval = 5 * time.Second
+	// taskTimeout - how long we wait for any single task to finish
+	taskTimeout = 90 * time.Minute
+)
+
+// TaskError - error reported by a OneView task
+type TaskError struct {
+	ErrorCode string `json:"errorCode,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// Task - the parts of a OneView task resource the driver watches
+type Task struct {
+	Type            string      `json:"type,omitempty"`
+	URI             string      `json:"uri,omitempty"`
+	Name            string      `json:"name,omitempty"`
+	TaskState       TaskState   `json:"taskState,omitempty"`
+	TaskStatus      string      `json:"taskStatus,omitempty"`
+	PercentComplete int         `json:"percentComplete,omitempty"`
+	IsCancellable   bool        `json:"isCancellable,omitempty"`
+	TaskErrors      []TaskError `json:"taskErrors,omitempty"`
+	Created         Timestamp   `json:"created,omitempty"`
+	Modified        Timestamp   `json:"modified,omitempty"`
+	// ProgressUpdates - the steps the task went through so far, ie;
+	// "Applying connections", oldest first
+	ProgressUpdates []TaskProgressUpdate `json:"progressUpdates,omitempty"`
+	// AssociatedResource - the resource the task works on, for creates it
+	// is the new resource
+	AssociatedResource AssociatedResource `json:"associatedResource,omitempty"`
+}
+
+// AssociatedResource - the resource a task belongs to
+type AssociatedResource struct {
+	ResourceURI      string `json:"resourceUri,omitempty"`
+	ResourceName     string `json:"resourceName,omitempty"`
+	ResourceCategory string `json:"resourceCategory,omitempty"`
+}
+
+// Duration - how long the task ran, or has been running for when not done
+func (t Task) Duration(now time.Time) time.Duration {
+	if t.Created.IsZero() {
+		return 0
+	}
+	if t.isDone() && !t.Modified.IsZero() {
+		return t.Modified.Sub(t.Created.Time)
+	}
+	return now.Sub(t.Created.Time)
+}
+
+// TaskProgressUpdate - one step of a task
+type TaskProgressUpdate struct {
+	ID           int    `json:"id,omitempty"`
+	StatusUpdate string `json:"statusUpdate,omitempty"`
+}
+
+// updatesAfter - the step messages with an id above after, and the highest
+// id seen, to report each step once while polling
+func (t Task) updatesAfter(after int) ([]string, int) {
+	var msgs []string
+	last := after
+	for _, u := range t.ProgressUpdates {
+		if u.ID <= after || u.StatusUpdate == "" {
+			continue
+		}
+		msgs = append(msgs, u.StatusUpdate)
+		if u.ID > last {
+			last = u.ID
+		}
+	}
+	return msgs, last
+}
+
+// isDone - true when the task reached a final state
+func (t Task) isDone() bool {
+	return t.TaskState.IsDone()
+}
+
+// TaskFailedError - a task that finished without completing, use errors.As
+// to get at the task and its errors
+type TaskFailedError struct {
+	Task Task
+}
+
+func (e *TaskFailedError) Error() string {
+	var msgs []string
+	for _, te := range e.Task.TaskErrors {
+		msgs = append(msgs, te.Message)
+	}
+	return fmt.Sprintf("task %s (%s) ended in state %s: %s", e.Task.Name, e.Task.URI, e.Task.TaskState, strings.Join(msgs, "; "))
+}
+
+// err - the error for a finished task, nil when it completed
+func (t Task) err() error {
+	if !t.TaskState.IsFailed() {
+		return nil
+	}
+	return &TaskFailedError{Task: t}
+}
+
+// isTaskType - true when a resource type is one of the task types, ie; TaskResourceV2
+func isTaskType(t string) bool {
+	return strings.HasPrefix(t, "TaskResource")
+}
+
+// waitForTaskResponse - wait on the task returned in the body of an async call
+func waitForTaskResponse(c *ov.OVClient, data []byte) error {
+	uri, err := taskURI(data)
+	if err != nil {
+		return err
+	}
+	return waitForTask(c, uri)
+}
+
+// waitForTask - poll a task uri until it finishes, times out or the driver
+// is interrupted
+func waitForTask(c *ov.OVClient, uri string) error {
+	return waitForTaskContext(interruptCtx, c, uri)
+}
+
+// waitForTaskContext - poll a task uri until it finishes or times out, the
+// task is cancelled on the appliance when ctx is done first
+func waitForTaskContext(ctx context.Context, c *ov.OVClient, uri string) error {
+	_, err := waitForTaskResult(ctx, c, uri)
+	return err
+}
+
+// waitForTaskResult - waitForTaskContext, also handing back the last state
+// of the task, ie; to find the resource it created
+func waitForTaskResult(ctx context.Context, c *ov.OVClient, uri string) (Task, error) {
+	return waitForTaskProgress(ctx, c, uri, defaultProgress())
+}
+
+// waitForTaskProgress - waitForTaskResult, reporting progress to out under
+// the task name, the percent complete as the action and each new step of
+// the task as a message
+func waitForTaskProgress(ctx context.Context, c *ov.OVClient, uri string, out ProgressSink) (Task, error) {
+	start := time.Now()
+	lastUpdate := 0
+	for {
+		var t Task
+		data, err := ovCall(c, rest.GET, uri, nil)
+		if err != nil {
+			return t, err
+		}
+		if err := json.Unmarshal(data, &t); err != nil {
+			return t, err
+		}
+		var msgs []string
+		msgs, lastUpdate = t.updatesAfter(lastUpdate)
+		for _, m := range msgs {
+			out.WriteProgress(progress.Progress{ID: t.Name, Message: m})
+		}
+		out.WriteProgress(progress.Progress{
+			ID:         t.Name,
+			Action:     fmt.Sprintf("%s %d%%", t.TaskState, t.PercentComplete),
+			LastUpdate: t.isDone(),
+		})
+		if t.isDone() {
+			return t, t.err()
+		}
+		if time.Since(start) > taskTimeout {
+			return t, fmt.Errorf("timed out waiting on task %s (%s) after %s", t.Name, uri, taskTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			if err := CancelTask(c, uri); err != nil {
+				log.Warnf("unable to cancel task %s (%s) : %s", t.Name, uri, err)
+			}
+			return t, fmt.Errorf("%w: %v", ErrCancelled, ctx.Err())
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package oneview
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/docker/docker/pkg/progress"
+)
+
+// TaskWait - a task to wait on, with the label its progress is shown under
+type TaskWait struct {
+	Label string
+	URI   string
+}
+
+// TaskResult - how one of the tasks given to WaitForTasks ended
+type TaskResult struct {
+	Label string
+	Task  Task
+	Err   error
+}
+
+// TaskResults - results in the order the tasks were given
+type TaskResults []TaskResult
+
+// Err - nil when every task completed, otherwise the failures by label,
+// errors.Is and errors.As see the first failure
+func (r TaskResults) Err() error {
+	var failed []string
+	var first *TaskResult
+	for i := range r {
+		if r[i].Err == nil {
+			continue
+		}
+		if first == nil {
+			first = &r[i]
+		}
+		failed = append(failed, fmt.Sprintf("%s: %s", r[i].Label, r[i].Err))
+	}
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%s: %w", first.Label, first.Err)
+	}
+	return fmt.Errorf("%d tasks failed: %s: %w; %s", len(failed), first.Label, first.Err, strings.Join(failed[1:], "; "))
+}
+
+// WaitForTasks - wait on several tasks at once.  Their progress goes to out,
+// or the driver log when nil, as one line with every task in the order given,
+// so parallel waits do not interleave.  Each task has its own result, one
+// failing does not stop the wait on the others.
+func WaitForTasks(ctx context.Context, c *ov.OVClient, waits []TaskWait, out ProgressSink) TaskResults {
+	if out == nil {
+		out = defaultProgress()
+	}
+	mux := newTaskMux(waits, out)
+	results := make(TaskResults, len(waits))
+	var wg sync.WaitGroup
+	for i, w := range waits {
+		results[i].Label = w.Label
+		wg.Add(1)
+		go func(i int, w TaskWait) {
+			defer wg.Done()
+			t, err := waitForTaskProgress(ctx, c, w.URI, mux.output(i))
+			results[i].Task, results[i].Err = t, err
+		}(i, w)
+	}
+	wg.Wait()
+	return results
+}
+
+// taskMux - gathers the progress of several task waits into a single
+// progress line, in the order the tasks were given
+type taskMux struct {
+	mu     sync.Mutex
+	out    ProgressSink
+	labels []string
+	status []string
+	done   []bool
+}
+
+func newTaskMux(waits []TaskWait, out ProgressSink) *taskMux {
+	m := &taskMux{
+		out:    out,
+		labels: make([]string, len(waits)),
+		status: make([]string, len(waits)),
+		done:   make([]bool, len(waits)),
+	}
+	for i, w := range waits {
+		m.labels[i] = w.Label
+		m.status[i] = "waiting"
+	}
+	return m
+}
+
+// output - the progress output for the task at i
+func (m *taskMux) output(i int) ProgressSink {
+	return taskMuxOutput{m: m, i: i}
+}
+
+func (m *taskMux) update(i int, p progress.Progress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p.Message != "" {
+		return m.out.WriteProgress(progress.Progress{ID: m.labels[i], Message: p.Message})
+	}
+	m.status[i] = p.Action
+	m.done[i] = m.done[i] || p.LastUpdate
+	all := true
+	parts := make([]string, len(m.labels))
+	for j := range m.labels {
+		parts[j] = fmt.Sprintf("%s %s", m.labels[j], m.status[j])
+		all = all && m.done[j]
+	}
+	return m.out.WriteProgress(progress.Progress{
+		ID:         "tasks",
+		Action:     strings.Join(parts, ", "),
+		LastUpdate: all,
+	})
+}
+
+// taskMuxOutput - ProgressSink for one task of a taskMux
+type taskMuxOutput struct {
+	m *taskMux
+	i int
+}
+
+// WriteProgress - record the update for this task
+func (o taskMuxOutput) WriteProgress(p progress.Progress) error {
+	return o.m.update(o.i, p)
+}
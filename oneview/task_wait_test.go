@@ -0,0 +1,77 @@
+package oneview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/pkg/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordProgress struct {
+	updates []progress.Progress
+}
+
+func (r *recordProgress) WriteProgress(p progress.Progress) error {
+	r.updates = append(r.updates, p)
+	return nil
+}
+
+func TestTaskMux(t *testing.T) {
+	out := &recordProgress{}
+	m := newTaskMux([]TaskWait{{Label: "volume"}, {Label: "firmware"}}, out)
+	m.output(1).WriteProgress(progress.Progress{Action: "Running 10%"})
+	m.output(0).WriteProgress(progress.Progress{Action: "Completed 100%", LastUpdate: true})
+	m.output(1).WriteProgress(progress.Progress{Message: "staging"})
+	m.output(1).WriteProgress(progress.Progress{Action: "Completed 100%", LastUpdate: true})
+
+	assert.Len(t, out.updates, 4)
+	assert.Equal(t, "volume waiting, firmware Running 10%", out.updates[0].Action)
+	assert.Equal(t, "volume Completed 100%, firmware Running 10%", out.updates[1].Action)
+	assert.False(t, out.updates[1].LastUpdate)
+	assert.Equal(t, progress.Progress{ID: "firmware", Message: "staging"}, out.updates[2])
+	assert.True(t, out.updates[3].LastUpdate)
+}
+
+func TestTaskResultsErr(t *testing.T) {
+	assert.NoError(t, TaskResults{{Label: "a"}, {Label: "b"}}.Err())
+
+	failed := &TaskFailedError{Task: Task{Name: "Create", TaskState: TaskStateError}}
+	err := TaskResults{{Label: "a"}, {Label: "b", Err: failed}}.Err()
+	var tf *TaskFailedError
+	assert.True(t, errors.As(err, &tf))
+	assert.Contains(t, err.Error(), "b: task Create")
+
+	err = TaskResults{{Label: "a", Err: ErrCancelled}, {Label: "b", Err: failed}}.Err()
+	assert.True(t, errors.Is(err, ErrCancelled))
+	assert.Contains(t, err.Error(), "2 tasks failed: a: ")
+}
+
+func TestTaskURI(t *testing.T) {
+	uri, err := taskURI([]byte(`{"type": "TaskResourceV2", "uri": "/rest/tasks/1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "/rest/tasks/1", uri)
+
+	uri, err = taskURI([]byte(`{"type": "ServerProfileV5", "uri": "/rest/server-profiles/P", "taskUri": "/rest/tasks/2"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "/rest/tasks/2", uri)
+
+	_, err = taskURI([]byte(`{"type": "ServerProfileV5", "uri": "/rest/server-profiles/P"}`))
+	assert.EqualError(t, err, "appliance did not return a task to wait on")
+}
+
+func TestTaskUpdatesAfter(t *testing.T) {
+	task := Task{ProgressUpdates: []TaskProgressUpdate{
+		{ID: 1, StatusUpdate: "Validating the profile"},
+		{ID: 2, StatusUpdate: "Applying connections"},
+		{ID: 3},
+	}}
+	msgs, last := task.updatesAfter(0)
+	assert.Equal(t, []string{"Validating the profile", "Applying connections"}, msgs)
+	assert.Equal(t, 2, last)
+
+	task.ProgressUpdates = append(task.ProgressUpdates, TaskProgressUpdate{ID: 4, StatusUpdate: "Powering on"})
+	msgs, last = task.updatesAfter(last)
+	assert.Equal(t, []string{"Powering on"}, msgs)
+	assert.Equal(t, 4, last)
+}
@@ -0,0 +1,303 @@
+package oneview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+// kinds of template change
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// template sections compared by DiffTemplates
+const (
+	SectionConnections = "connections"
+	SectionBIOS        = "bios"
+	SectionFirmware    = "firmware"
+	SectionStorage     = "storage"
+)
+
+// TemplateChange - one difference between two templates
+type TemplateChange struct {
+	Section string `json:"section"`
+	// Item - what changed within the section, ie; connection 1
+	Item string `json:"item"`
+	// Attribute - the attribute of the item, empty when the whole item was
+	// added or removed
+	Attribute string      `json:"attribute,omitempty"`
+	Kind      string      `json:"kind"`
+	Old       interface{} `json:"old,omitempty"`
+	New       interface{} `json:"new,omitempty"`
+	// PowerOff - profiles need their server powered off to take the change
+	PowerOff bool `json:"powerOff"`
+}
+
+// String - ie; ~ connection 1 requestedMbps: 2500 -> 5000
+func (c TemplateChange) String() string {
+	what := c.Item
+	if c.Attribute != "" {
+		what += " " + c.Attribute
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		if c.Attribute == "" {
+			return "+ " + what
+		}
+		return fmt.Sprintf("+ %s: %s", what, diffValue(c.New))
+	case ChangeRemoved:
+		if c.Attribute == "" {
+			return "- " + what
+		}
+		return fmt.Sprintf("- %s: %s", what, diffValue(c.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", what, diffValue(c.Old), diffValue(c.New))
+}
+
+// TemplateDiff - the changes going from one template to another
+type TemplateDiff struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Changes []TemplateChange `json:"changes"`
+}
+
+// Empty - true when the templates match in every compared section
+func (d TemplateDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// PowerOff - true when moving profiles to the new template needs downtime
+func (d TemplateDiff) PowerOff() bool {
+	for _, c := range d.Changes {
+		if c.PowerOff {
+			return true
+		}
+	}
+	return false
+}
+
+// String - report of the changes grouped by section
+func (d TemplateDiff) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s -> %s\n", d.From, d.To)
+	if d.Empty() {
+		b.WriteString("  no changes\n")
+		return b.String()
+	}
+	section := ""
+	for _, c := range d.Changes {
+		if c.Section != section {
+			section = c.Section
+			fmt.Fprintf(&b, "%s:\n", section)
+		}
+		fmt.Fprintf(&b, "  %s\n", c)
+	}
+	if d.PowerOff() {
+		b.WriteString("existing machines need a power off to take these changes\n")
+	} else {
+		b.WriteString("existing machines can take these changes online\n")
+	}
+	return b.String()
+}
+
+// DiffTemplatesByName - compare two server templates on the appliance
+func DiffTemplatesByName(c *ov.OVClient, a, b string) (TemplateDiff, error) {
+	ta, err := getServerTemplate(c, a)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+	tb, err := getServerTemplate(c, b)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+	return DiffTemplates(ta, tb), nil
+}
+
+// DiffTemplates - compare the connections, bios, firmware and storage of two
+// raw templates.  Identities the appliance assigns are ignored.
+func DiffTemplates(a, b map[string]interface{}) TemplateDiff {
+	d := TemplateDiff{}
+	d.From, _ = a["name"].(string)
+	d.To, _ = b["name"].(string)
+
+	// connections, network and bandwidth changes apply online but moving a
+	// connection to another port or function type does not
+	d.diffKeyed(SectionConnections, "connection", listByKey(a["connections"], "id"), listByKey(b["connections"], "id"),
+		connectionIdentityAttributes, func(attr string) bool {
+			return attr == "portId" || attr == "functionType"
+		})
+
+	// bios settings need a reboot
+	biosA, _ := a["bios"].(map[string]interface{})
+	biosB, _ := b["bios"].(map[string]interface{})
+	d.diffItem(SectionBIOS, "bios", withoutKeys(biosA, "overriddenSettings"), withoutKeys(biosB, "overriddenSettings"), nil, always)
+	d.diffKeyed(SectionBIOS, "setting", listByKey(biosA["overriddenSettings"], "id"), listByKey(biosB["overriddenSettings"], "id"), nil, always)
+
+	// firmware is installed with the server off
+	fwA, _ := a["firmware"].(map[string]interface{})
+	fwB, _ := b["firmware"].(map[string]interface{})
+	d.diffItem(SectionFirmware, "firmware", fwA, fwB, nil, always)
+
+	// local storage needs the server off, san volumes attach online
+	localA, _ := a["localStorage"].(map[string]interface{})
+	localB, _ := b["localStorage"].(map[string]interface{})
+	d.diffItem(SectionStorage, "local storage", localA, localB, nil, always)
+	sanA, _ := a["sanStorage"].(map[string]interface{})
+	sanB, _ := b["sanStorage"].(map[string]interface{})
+	d.diffItem(SectionStorage, "san storage", withoutKeys(sanA, "volumeAttachments"), withoutKeys(sanB, "volumeAttachments"), nil, never)
+	d.diffKeyed(SectionStorage, "volume attachment", listByKey(sanA["volumeAttachments"], "id"), listByKey(sanB["volumeAttachments"], "id"), nil, never)
+	return d
+}
+
+func always(string) bool { return true }
+func never(string) bool  { return false }
+
+// diffKeyed - compare items matched up by key
+func (d *TemplateDiff) diffKeyed(section, item string, a, b map[string]map[string]interface{}, ignore []string, powerOff func(string) bool) {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Sort(naturalKeys(keys))
+	for _, k := range keys {
+		name := item + " " + k
+		ia, inA := a[k]
+		ib, inB := b[k]
+		switch {
+		case !inA:
+			d.Changes = append(d.Changes, TemplateChange{Section: section, Item: name, Kind: ChangeAdded, New: ib, PowerOff: powerOff("")})
+		case !inB:
+			d.Changes = append(d.Changes, TemplateChange{Section: section, Item: name, Kind: ChangeRemoved, Old: ia, PowerOff: powerOff("")})
+		default:
+			d.diffItem(section, name, ia, ib, ignore, powerOff)
+		}
+	}
+}
+
+// diffItem - compare the flattened attributes of one item
+func (d *TemplateDiff) diffItem(section, item string, a, b map[string]interface{}, ignore []string, powerOff func(string) bool) {
+	fa, fb := map[string]interface{}{}, map[string]interface{}{}
+	flattenAttributes("", a, fa)
+	flattenAttributes("", b, fb)
+	for _, k := range ignore {
+		delete(fa, k)
+		delete(fb, k)
+	}
+	var attrs []string
+	for k := range fa {
+		attrs = append(attrs, k)
+	}
+	for k := range fb {
+		if _, ok := fa[k]; !ok {
+			attrs = append(attrs, k)
+		}
+	}
+	sort.Strings(attrs)
+	for _, attr := range attrs {
+		va, inA := fa[attr]
+		vb, inB := fb[attr]
+		c := TemplateChange{Section: section, Item: item, Attribute: attr, Old: va, New: vb, PowerOff: powerOff(attr)}
+		switch {
+		case !inA:
+			c.Kind = ChangeAdded
+		case !inB:
+			c.Kind = ChangeRemoved
+		case reflect.DeepEqual(va, vb):
+			continue
+		default:
+			c.Kind = ChangeModified
+		}
+		d.Changes = append(d.Changes, c)
+	}
+}
+
+// flattenAttributes - nested maps become dotted attribute names, ie;
+// boot.priority, nulls are left out
+func flattenAttributes(prefix string, m map[string]interface{}, out map[string]interface{}) {
+	for k, v := range m {
+		name := prefix + k
+		switch t := v.(type) {
+		case nil:
+		case map[string]interface{}:
+			flattenAttributes(name+".", t, out)
+		default:
+			out[name] = v
+		}
+	}
+}
+
+// listByKey - raw list items by the string form of one of their attributes
+func listByKey(v interface{}, key string) map[string]map[string]interface{} {
+	items := map[string]map[string]interface{}{}
+	list, _ := v.([]interface{})
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		k := fmt.Sprint(i)
+		if id, ok := m[key]; ok && id != nil {
+			k = fmt.Sprint(id)
+		}
+		items[k] = m
+	}
+	return items
+}
+
+// withoutKeys - shallow copy of m without the keys
+func withoutKeys(m map[string]interface{}, keys ...string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	for _, k := range keys {
+		delete(out, k)
+	}
+	return out
+}
+
+// diffValue - short printable form of a value
+func diffValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// naturalKeys - numeric keys in number order, then the rest by name
+type naturalKeys []string
+
+func (k naturalKeys) Len() int      { return len(k) }
+func (k naturalKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k naturalKeys) Less(i, j int) bool {
+	if len(k[i]) != len(k[j]) && isDigits(k[i]) && isDigits(k[j]) {
+		return len(k[i]) < len(k[j])
+	}
+	return k[i] < k[j]
+}
+
+// isDigits - true for a non empty string of decimal digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,53 @@
+package oneview
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTemplate(t *testing.T, data string) map[string]interface{} {
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(data), &m))
+	return m
+}
+
+func TestDiffTemplates(t *testing.T) {
+	a := testTemplate(t, `{
+		"name": "DOCKER_V1", "uri": "/rest/server-profile-templates/1",
+		"connections": [
+			{"id": 1, "name": "public", "networkUri": "/rest/ethernet-networks/A", "requestedMbps": 2500, "portId": "Auto"},
+			{"id": 2, "name": "private", "networkUri": "/rest/ethernet-networks/B", "requestedMbps": 2500}],
+		"bios": {"manageBios": true, "overriddenSettings": [{"id": "PowerRegulator", "value": "StaticHighPerf"}]},
+		"firmware": {"manageFirmware": false},
+		"sanStorage": {"manageSanStorage": true, "volumeAttachments": [{"id": 1, "volumeUri": "/rest/storage-volumes/V"}]}
+	}`)
+	b := testTemplate(t, `{
+		"name": "DOCKER_V2", "uri": "/rest/server-profile-templates/2",
+		"connections": [
+			{"id": 1, "name": "public", "networkUri": "/rest/ethernet-networks/A", "requestedMbps": 5000, "portId": "Auto"},
+			{"id": 10, "name": "storage", "networkUri": "/rest/ethernet-networks/C"}],
+		"bios": {"manageBios": true, "overriddenSettings": [{"id": "PowerRegulator", "value": "StaticHighPerf"}]},
+		"firmware": {"manageFirmware": false},
+		"sanStorage": {"manageSanStorage": true, "volumeAttachments": [{"id": 1, "volumeUri": "/rest/storage-volumes/V"}]}
+	}`)
+
+	d := DiffTemplates(a, b)
+	assert.Equal(t, "DOCKER_V1", d.From)
+	assert.Len(t, d.Changes, 3)
+	assert.Equal(t, "~ connection 1 requestedMbps: 2500 -> 5000", d.Changes[0].String())
+	assert.Equal(t, "- connection 2", d.Changes[1].String())
+	assert.Equal(t, "+ connection 10", d.Changes[2].String())
+	assert.False(t, d.Changes[0].PowerOff)
+
+	// a bios change needs downtime
+	b["bios"].(map[string]interface{})["overriddenSettings"] = []interface{}{
+		map[string]interface{}{"id": "PowerRegulator", "value": "DynamicPowerSavings"}}
+	d = DiffTemplates(a, b)
+	assert.True(t, d.PowerOff())
+	assert.True(t, strings.Contains(d.String(), "bios:\n  ~ setting PowerRegulator value: \"StaticHighPerf\" -> \"DynamicPowerSavings\"\n"))
+
+	assert.True(t, DiffTemplates(a, a).Empty())
+}
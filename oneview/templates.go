@@ -0,0 +1,258 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	serverProfilesURI         = "/rest/server-profiles"
+	serverProfileTemplatesURI = "/rest/server-profile-templates"
+
+	// templatesAPIVersion - first api version with server profile templates,
+	// before that a template is a server profile with no hardware assigned
+	templatesAPIVersion = 200
+)
+
+// profileIdentityAttributes - attributes the appliance owns on a profile that
+// must not be copied into a new one
+var profileIdentityAttributes = []string{
+	"uri", "eTag", "uuid", "serialNumber", "taskUri", "serverHardwareUri",
+	"status", "state", "inProgress", "created", "modified", "category",
+	"templateCompliance", "associatedServer", "enclosureBay", "enclosureUri",
+}
+
+// connectionIdentityAttributes - identities the appliance assigns per connection
+var connectionIdentityAttributes = []string{
+	"mac", "wwnn", "wwpn", "state", "status", "interconnectUri", "deploymentStatus",
+}
+
+// getServerTemplate - find a server template by name, on api versions before
+// templates existed this is a server profile with no hardware
+func getServerTemplate(c *ov.OVClient, name string) (map[string]interface{}, error) {
+	uri := serverProfilesURI
+	if c.APIVersion >= templatesAPIVersion {
+		uri = serverProfileTemplatesURI
+	}
+	query := map[string]interface{}{"filter": []string{applianceFilter("name", name)}}
+	var found []map[string]interface{}
+	err := listMembers(c, uri, query, func(members json.RawMessage) error {
+		var page []map[string]interface{}
+		if err := decodeJSON(members, &page); err != nil {
+			return err
+		}
+		found = append(found, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("unable to find server template %s", name)
+	}
+	return found[0], nil
+}
+
+// newProfileFromTemplate - render a new profile body from a template and
+// place it on the hardware
+func newProfileFromTemplate(c *ov.OVClient, template map[string]interface{}, name, hardwareURI string) (map[string]interface{}, error) {
+	var profile map[string]interface{}
+	templateURI, _ := template["uri"].(string)
+	if c.APIVersion >= templatesAPIVersion && templateURI != "" {
+		data, err := ovCall(c, rest.GET, templateURI+"/new-profile", nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeJSON(data, &profile); err != nil {
+			return nil, err
+		}
+	} else {
+		profile = copyProfileBody(template)
+	}
+	profile["name"] = name
+	profile["serverHardwareUri"] = hardwareURI
+	return profile, nil
+}
+
+// copyProfileBody - copy a profile without any of the identities the
+// appliance assigned to it
+func copyProfileBody(source map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(source)
+	var profile map[string]interface{}
+	decodeJSON(data, &profile)
+	for _, k := range profileIdentityAttributes {
+		delete(profile, k)
+	}
+	for _, conn := range profileConnections(profile) {
+		for _, k := range connectionIdentityAttributes {
+			delete(conn, k)
+		}
+	}
+	return profile
+}
+
+// submitProfile - post a new profile and wait for the appliance to apply it,
+// reporting the apply progress to out.  The appliance goes ahead despite the
+// warnings in force.
+func submitProfile(c *ov.OVClient, profile map[string]interface{}, force []ForceOption, out ProgressSink) error {
+	if err := validateProfileMap(profile, c.APIVersion); err != nil {
+		return err
+	}
+	log.Debugf("submitting profile %v for hardware %v", profile["name"], profile["serverHardwareUri"])
+	data, err := ovCall(c, rest.POST, withForce(serverProfilesURI, force), profile)
+	if err != nil {
+		return err
+	}
+	uri, err := taskURI(data)
+	if err != nil {
+		return err
+	}
+	_, err = waitForTaskProgress(interruptCtx, c, uri, out)
+	return err
+}
+
+// SubmitNewProfile - create a server profile and block until the appliance
+// has applied it, a failed apply returns a *TaskFailedError
+func SubmitNewProfile(c *ov.OVClient, profile ov.ServerProfile, force ...ForceOption) error {
+	return SubmitNewProfileWithProgress(c, profile, defaultProgress(), force...)
+}
+
+// SubmitNewProfileWithProgress - SubmitNewProfile, reporting the percent
+// complete and each step of the apply to out, ie; a docker progress bar or
+// a ProgressFunc.  An apply takes 10 minutes or more.
+func SubmitNewProfileWithProgress(c *ov.OVClient, profile ov.ServerProfile, out ProgressSink, force ...ForceOption) error {
+	if profile.Type == "" {
+		profile.Type = profileType(c.APIVersion)
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return submitProfile(c, raw, force, out)
+}
+
+// CreateProfileFromTemplate - create profileName on the hardware from the
+// named server template and block until the appliance has applied it
+func CreateProfileFromTemplate(c *ov.OVClient, templateName, profileName string, hardware ov.ServerHardware) error {
+	if hardware.URI.IsNil() {
+		return fmt.Errorf("no server hardware to create profile %s on", profileName)
+	}
+	template, err := getServerTemplate(c, templateName)
+	if err != nil {
+		return err
+	}
+	profile, err := newProfileFromTemplate(c, template, profileName, hardware.URI.String())
+	if err != nil {
+		return err
+	}
+	log.Infof("Creating profile %s from %s on %s", profileName, templateName, hardware.Name)
+	return submitProfile(c, profile, nil, defaultProgress())
+}
+
+// CloneProfile - copy the sourceName profile to newName on the target
+// hardware, leaving out the identities so the copy gets its own
+func CloneProfile(c *ov.OVClient, sourceName, newName, targetHardwareURI string, force ...ForceOption) error {
+	if targetHardwareURI == "" {
+		return fmt.Errorf("no server hardware to clone profile %s onto", sourceName)
+	}
+	uri, err := findURIByName(c, serverProfilesURI, sourceName)
+	if err != nil {
+		return err
+	}
+	if uri == "" {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, sourceName)
+	}
+	source, err := getResourceMap(c, uri)
+	if err != nil {
+		return err
+	}
+	log.Infof("Cloning profile %s to %s", sourceName, newName)
+	return submitProfile(c, cloneProfileBody(source, newName, targetHardwareURI), force, defaultProgress())
+}
+
+// cloneProfileBody - the body of a copy of source named name on the hardware
+func cloneProfileBody(source map[string]interface{}, name, hardwareURI string) map[string]interface{} {
+	profile := copyProfileBody(source)
+	profile["name"] = name
+	profile["serverHardwareUri"] = hardwareURI
+	return profile
+}
+
+// DeleteProfile - delete the named server profile and wait for the task
+func DeleteProfile(c *ov.OVClient, name string) error {
+	return DeleteProfileWithProgress(c, name, defaultProgress())
+}
+
+// DeleteProfileWithProgress - DeleteProfile, reporting the task progress to out
+func DeleteProfileWithProgress(c *ov.OVClient, name string, out ProgressSink) error {
+	uri, err := findURIByName(c, serverProfilesURI, name)
+	if err != nil {
+		return err
+	}
+	if uri == "" {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	log.Debugf("deleting profile %s (%s)", name, uri)
+	return deleteResourceProgress(c, uri, out)
+}
+
+// UpdateProfile - change the server profile at uri with change and put it
+// with its eTag as If-Match.  When the profile changed on the appliance in
+// between, the 412 is answered by applying change again to the current one.
+func UpdateProfile(c *ov.OVClient, uri string, change func(profile map[string]interface{}), force ...ForceOption) error {
+	for attempt := 0; ; attempt++ {
+		profile, err := getResourceMap(c, uri)
+		if err != nil {
+			return err
+		}
+		change(profile)
+		err = putIfMatch(c, withForce(uri, force), profile)
+		if attempt > 0 || !isPreconditionFailed(err) {
+			return err
+		}
+		log.Debugf("profile %s changed since it was read, changing the current version", uri)
+		recordRetry(c)
+	}
+}
+
+// putIfMatch - put a raw resource conditional on its eTag, when it has one,
+// and wait on the task
+func putIfMatch(c *ov.OVClient, uri string, resource map[string]interface{}) error {
+	var headers map[string]string
+	if etag, _ := resource["eTag"].(string); etag != "" {
+		headers = map[string]string{"If-Match": etag}
+	}
+	data, err := ovHeaderCall(c, rest.PUT, uri, headers, resource)
+	if err != nil {
+		return err
+	}
+	return waitForTaskResponse(c, data)
+}
+
+// taskURI - the task to wait on from the response to an async call, the
+// body is the task itself or, on some api versions, carries its taskUri
+func taskURI(data []byte) (string, error) {
+	var t struct {
+		URI     string `json:"uri"`
+		Type    string `json:"type"`
+		TaskURI string `json:"taskUri"`
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return "", err
+	}
+	switch {
+	case t.TaskURI != "":
+		return t.TaskURI, nil
+	case t.URI != "" && (t.Type == "" || isTaskType(t.Type)):
+		return t.URI, nil
+	}
+	return "", fmt.Errorf("appliance did not return a task to wait on")
+}
@@ -0,0 +1,98 @@
+package oneview
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateProfileReappliesChange(t *testing.T) {
+	uri := "/rest/server-profiles/412a"
+	body := func(etag, bios, description string) func(interface{}) {
+		return func(b interface{}) {
+			assert.Equal(t, map[string]interface{}{"uri": uri, "eTag": etag, "bios": bios, "description": description}, b)
+		}
+	}
+	c, done := fakeOV(t,
+		fakeCall{method: "GET", uri: uri, data: `{"uri": "/rest/server-profiles/412a", "eTag": "1", "bios": "x", "description": ""}`},
+		fakeCall{method: "PUT", uri: uri, body: body("1", "x", "mine"), err: errors.New("Error with request: Response Status: 412 Precondition Failed")},
+		// the concurrent bios change is kept, only the description is changed again
+		fakeCall{method: "GET", uri: uri, data: `{"uri": "/rest/server-profiles/412a", "eTag": "2", "bios": "y", "description": ""}`},
+		fakeCall{method: "PUT", uri: uri, body: body("2", "y", "mine"), data: `{"uri":"/rest/tasks/1","type":"TaskResourceV2"}`},
+		fakeCall{method: "GET", uri: "/rest/tasks/1", data: `{"name":"Update","taskState":"Completed","percentComplete":100}`},
+	)
+	defer done()
+	assert.NoError(t, UpdateProfile(c, uri, func(p map[string]interface{}) { p["description"] = "mine" }))
+}
+
+func TestIsPreconditionFailed(t *testing.T) {
+	assert.True(t, isPreconditionFailed(applianceError(errors.New("Error with request: /rest/server-profiles/1 Response Status: 412 Precondition Failed"))))
+	assert.False(t, isPreconditionFailed(applianceError(errors.New("Error with request: /rest/server-profiles/412a Response Status: 404 Not Found"))))
+	assert.False(t, isPreconditionFailed(errors.New("precondition failed")))
+	assert.False(t, isPreconditionFailed(nil))
+}
+
+func TestCreateProfileFromTemplateNeedsHardware(t *testing.T) {
+	err := CreateProfileFromTemplate(&ov.OVClient{}, "template", "docker1", ov.ServerHardware{})
+	assert.EqualError(t, err, "no server hardware to create profile docker1 on")
+}
+
+func TestCloneProfileBody(t *testing.T) {
+	source := map[string]interface{}{
+		"name": "docker1", "uri": "/rest/server-profiles/1", "uuid": "U1", "serialNumber": "VCG1",
+		"taskUri": "/rest/tasks/1", "eTag": "3", "serverHardwareUri": "/rest/server-hardware/1",
+		"serverHardwareTypeUri": "/rest/server-hardware-types/T",
+		"connections":           []interface{}{map[string]interface{}{"id": 1.0, "mac": "AA:BB:CC:00:00:01", "networkUri": "/rest/ethernet-networks/N"}},
+	}
+	clone := cloneProfileBody(source, "docker2", "/rest/server-hardware/2")
+	assert.Equal(t, map[string]interface{}{
+		"name": "docker2", "serverHardwareUri": "/rest/server-hardware/2",
+		"serverHardwareTypeUri": "/rest/server-hardware-types/T",
+		"connections":           []interface{}{map[string]interface{}{"id": json.Number("1"), "networkUri": "/rest/ethernet-networks/N"}},
+	}, clone)
+	assert.Equal(t, "docker1", source["name"])
+}
+
+func TestDeleteProfileNotFound(t *testing.T) {
+	c, done := fakeOV(t, fakeCall{method: "GET", uri: serverProfilesURI, data: `{"members":[]}`})
+	defer done()
+	assert.True(t, errors.Is(DeleteProfile(c, "docker-1"), ErrProfileNotFound))
+}
+
+func TestDeleteProfileWithProgress(t *testing.T) {
+	list := fakeCall{method: "GET", uri: serverProfilesURI, data: `{"members":[{"uri":"/rest/server-profiles/1","name":"docker-1"}]}`}
+	var steps []string
+	out := ProgressFunc(func(p progress.Progress) error {
+		steps = append(steps, p.Action)
+		return nil
+	})
+
+	// an appliance that deletes straight away answers with no body
+	c, done := fakeOV(t, list, fakeCall{method: "DELETE", uri: "/rest/server-profiles/1"})
+	assert.NoError(t, DeleteProfileWithProgress(c, "docker-1", out))
+	done()
+	assert.Empty(t, steps)
+
+	c, done = fakeOV(t, list,
+		fakeCall{method: "DELETE", uri: "/rest/server-profiles/1", data: `{"uri":"/rest/tasks/1","type":"TaskResourceV2"}`},
+		fakeCall{method: "GET", uri: "/rest/tasks/1", data: `{"name":"Delete","taskState":"Running","percentComplete":50}`},
+		fakeCall{method: "GET", uri: "/rest/tasks/1", data: `{"name":"Delete","taskState":"Completed","percentComplete":100}`})
+	assert.NoError(t, DeleteProfileWithProgress(c, "docker-1", out))
+	done()
+	assert.Equal(t, []string{"Running 50%", "Completed 100%"}, steps)
+
+	c, done = fakeOV(t, list,
+		fakeCall{method: "DELETE", uri: "/rest/server-profiles/1", data: `{"uri":"/rest/tasks/2","type":"TaskResourceV2"}`},
+		fakeCall{method: "GET", uri: "/rest/tasks/2", data: `{"name":"Delete","taskState":"Error","percentComplete":100}`})
+	var failed *TaskFailedError
+	assert.True(t, errors.As(DeleteProfileWithProgress(c, "docker-1", out), &failed))
+	done()
+
+	c, done = fakeOV(t, list, fakeCall{method: "DELETE", uri: "/rest/server-profiles/1", data: `{"name":"docker-1"}`})
+	assert.EqualError(t, DeleteProfileWithProgress(c, "docker-1", out), "appliance did not return a task to wait on")
+	done()
+}
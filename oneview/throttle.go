@@ -0,0 +1,240 @@
+package oneview
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+// headers appliances, and api gateways in front of them, use to say how
+// many more calls and sessions they take
+const (
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+	headerSessionCount  = "X-Session-Count"
+	headerSessionLimit  = "X-Session-Limit"
+	headerRetryAfter    = "Retry-After"
+)
+
+const (
+	// throttleLowPercent - share of the rate limit left below which calls
+	// are spread out until the limit resets
+	throttleLowPercent = 10
+	// sessionHighPercent - share of the appliance sessions in use from which
+	// a warning is logged
+	sessionHighPercent = 90
+	// maxThrottleDelay - longest a single call is held back
+	maxThrottleDelay = 30 * time.Second
+	// throttledBackoff - how long to slow down after the rest library was
+	// throttled, it does not hand back the Retry-After header
+	throttledBackoff = 5 * time.Second
+)
+
+// sessionLimitMessages - message fragments of appliance errors about
+// running out of sessions
+var sessionLimitMessages = []string{"session limit", "maximum number of sessions", "too many sessions", "sessions exceeded"}
+
+// ApplianceLimits - what an appliance last said about its rate and session
+// limits, counts it did not send are -1
+type ApplianceLimits struct {
+	Limit       int
+	Remaining   int
+	Reset       time.Time
+	Sessions    int
+	MaxSessions int
+	// RetryAfter - no calls should be made before this
+	RetryAfter time.Time
+	Updated    time.Time
+}
+
+// Nearing - true when few calls are left before the rate limit resets
+func (l ApplianceLimits) Nearing() bool {
+	return l.Limit > 0 && l.Remaining >= 0 && l.Remaining*100 < l.Limit*throttleLowPercent
+}
+
+// SessionsHigh - true when the appliance is close to its session limit
+func (l ApplianceLimits) SessionsHigh() bool {
+	return l.MaxSessions > 0 && l.Sessions*100 >= l.MaxSessions*sessionHighPercent
+}
+
+// String - ie; 12 of 500 calls left until 10:04:05, 45 of 48 sessions
+func (l ApplianceLimits) String() string {
+	var parts []string
+	if l.Limit > 0 && l.Remaining >= 0 {
+		s := fmt.Sprintf("%d of %d calls left", l.Remaining, l.Limit)
+		if !l.Reset.IsZero() {
+			s += " until " + l.Reset.Format("15:04:05")
+		}
+		parts = append(parts, s)
+	}
+	if l.MaxSessions > 0 && l.Sessions >= 0 {
+		parts = append(parts, fmt.Sprintf("%d of %d sessions", l.Sessions, l.MaxSessions))
+	}
+	if !l.RetryAfter.IsZero() {
+		parts = append(parts, "retry after "+l.RetryAfter.Format("15:04:05"))
+	}
+	if len(parts) == 0 {
+		return "no limits reported"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// delay - how long to hold back the next call at now, spreading the calls
+// left evenly until the limit resets
+func (l ApplianceLimits) delay(now time.Time) time.Duration {
+	var d time.Duration
+	if l.RetryAfter.After(now) {
+		d = l.RetryAfter.Sub(now)
+	} else if l.Nearing() && l.Reset.After(now) {
+		d = l.Reset.Sub(now) / time.Duration(l.Remaining+1)
+	}
+	if d > maxThrottleDelay {
+		d = maxThrottleDelay
+	}
+	return d
+}
+
+var (
+	limitsMu        sync.Mutex
+	applianceLimits = map[string]ApplianceLimits{}
+)
+
+// limitsKey - appliances are told apart by scheme and host
+func limitsKey(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSuffix(endpoint, "/"))
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+// GetApplianceLimits - the limits the appliance at endpoint last reported,
+// false when it never sent any
+func GetApplianceLimits(endpoint string) (ApplianceLimits, bool) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	l, ok := applianceLimits[limitsKey(endpoint)]
+	return l, ok
+}
+
+// headerInt - an integer header, -1 when missing or not a number
+func headerInt(h http.Header, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(h.Get(name)))
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// parseLimits - the limits in the headers of a response, ok is false when
+// there are none.  The reset is either seconds from now or a unix time.
+func parseLimits(h http.Header, status int, now time.Time) (ApplianceLimits, bool) {
+	l := ApplianceLimits{
+		Limit:       headerInt(h, headerRateLimit),
+		Remaining:   headerInt(h, headerRateRemaining),
+		Sessions:    headerInt(h, headerSessionCount),
+		MaxSessions: headerInt(h, headerSessionLimit),
+		Updated:     now,
+	}
+	if reset := headerInt(h, headerRateReset); reset >= 0 {
+		if reset > 1000000000 {
+			l.Reset = time.Unix(int64(reset), 0)
+		} else {
+			l.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable || h.Get(headerRetryAfter) != "" {
+		l.RetryAfter = now.Add(parseRetryAfter(h.Get(headerRetryAfter), now))
+	}
+	ok := l.Limit >= 0 || l.Remaining >= 0 || l.Sessions >= 0 || l.MaxSessions >= 0 || !l.RetryAfter.IsZero()
+	return l, ok
+}
+
+// recordLimits - keep what a response said about the appliance limits
+func recordLimits(endpoint string, h http.Header, status int) {
+	l, ok := parseLimits(h, status, time.Now())
+	if !ok {
+		return
+	}
+	limitsMu.Lock()
+	applianceLimits[limitsKey(endpoint)] = l
+	limitsMu.Unlock()
+	if l.SessionsHigh() {
+		log.Warnf("Appliance %s is near its session limit, %s", endpoint, l)
+	}
+}
+
+// throttle - hold back a call to the appliance at endpoint while it is
+// near its rate limit or asked us to retry later, rather than have it fail,
+// returns how long the call was held back
+func throttle(endpoint string) time.Duration {
+	limitsMu.Lock()
+	l, ok := applianceLimits[limitsKey(endpoint)]
+	limitsMu.Unlock()
+	if !ok {
+		return 0
+	}
+	d := l.delay(time.Now())
+	if d <= 0 {
+		return 0
+	}
+	log.Debugf("Slowing down calls to %s for %s, %s", endpoint, d, l)
+	time.Sleep(d)
+	return d
+}
+
+// checkLimitError - note throttling the rest library ran into, it only
+// hands back the error, and say plainly what the appliance refused.  429
+// and 503 come back as a *BusyError so callers can defer the work.
+func checkLimitError(endpoint string, err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, sessionLimitMessages):
+		return fmt.Errorf("appliance %s has no sessions left, log out idle sessions or share one with ONEVIEW_OV_SESSION_TOKEN : %w", endpoint, err)
+	case statusCodeOf(err) == http.StatusTooManyRequests:
+		now := time.Now()
+		limitsMu.Lock()
+		l, ok := applianceLimits[limitsKey(endpoint)]
+		if !ok {
+			l = ApplianceLimits{Limit: -1, Remaining: -1, Sessions: -1, MaxSessions: -1}
+		}
+		l.RetryAfter, l.Updated = now.Add(throttledBackoff), now
+		applianceLimits[limitsKey(endpoint)] = l
+		limitsMu.Unlock()
+		return &BusyError{
+			RetryAfter: throttledBackoff,
+			Err:        fmt.Errorf("appliance %s is throttling calls, slowing down : %w", endpoint, err),
+		}
+	case statusCodeOf(err) == http.StatusServiceUnavailable:
+		return &BusyError{
+			RetryAfter: defaultBusyRetry,
+			Err:        fmt.Errorf("appliance %s is unavailable : %w", endpoint, err),
+		}
+	}
+	return err
+}
+
+// limitsTransport - records the limits in every response of the driver's
+// own http clients
+type limitsTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip - http.RoundTripper
+func (t limitsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		recordLimits(req.URL.Scheme+"://"+req.URL.Host, resp.Header, resp.StatusCode)
+	}
+	return resp, err
+}
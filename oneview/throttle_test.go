@@ -0,0 +1,73 @@
+package oneview
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimits(t *testing.T) {
+	now := time.Date(2016, 5, 1, 10, 0, 0, 0, time.UTC)
+	_, ok := parseLimits(http.Header{}, http.StatusOK, now)
+	assert.False(t, ok)
+
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", "500")
+	h.Set("X-RateLimit-Remaining", "20")
+	h.Set("X-RateLimit-Reset", "60")
+	h.Set("X-Session-Count", "45")
+	h.Set("X-Session-Limit", "48")
+	l, ok := parseLimits(h, http.StatusOK, now)
+	assert.True(t, ok)
+	assert.Equal(t, 500, l.Limit)
+	assert.Equal(t, now.Add(time.Minute), l.Reset)
+	assert.True(t, l.Nearing())
+	assert.True(t, l.SessionsHigh())
+	assert.Equal(t, "20 of 500 calls left until 10:01:00, 45 of 48 sessions", l.String())
+	// the 20 calls left are spread over the minute until the reset
+	assert.Equal(t, 60*time.Second/21, l.delay(now))
+	assert.Equal(t, time.Duration(0), l.delay(now.Add(2*time.Minute)))
+
+	h = http.Header{}
+	h.Set("Retry-After", "120")
+	l, ok = parseLimits(h, http.StatusTooManyRequests, now)
+	assert.True(t, ok)
+	assert.False(t, l.Nearing())
+	assert.Equal(t, maxThrottleDelay, l.delay(now))
+	assert.Equal(t, 10*time.Second, l.delay(now.Add(110*time.Second)))
+}
+
+func TestCheckLimitError(t *testing.T) {
+	endpoint := "https://throttled.example.com"
+	assert.Nil(t, checkLimitError(endpoint, nil))
+	other := errors.New("404 Not Found")
+	assert.Equal(t, other, checkLimitError(endpoint, other))
+	// a uri echoed in the body is no status
+	other = applianceError(errors.New("Error with request: /rest/server-profiles/a429b Response Status: 404 Not Found"))
+	assert.Equal(t, other, checkLimitError(endpoint, other))
+	assert.Equal(t, CategoryUser, CategoryOf(other))
+	_, ok := GetApplianceLimits(endpoint)
+	assert.False(t, ok)
+
+	err := checkLimitError(endpoint, applianceError(errors.New("Response Status: 403 Forbidden, maximum number of sessions reached")))
+	assert.Contains(t, err.Error(), "has no sessions left")
+	assert.Contains(t, err.Error(), "ONEVIEW_OV_SESSION_TOKEN")
+
+	err = checkLimitError(endpoint+"/", applianceError(errors.New("Response Status: 429 Too Many Requests")))
+	assert.Contains(t, err.Error(), "is throttling calls")
+	l, ok := GetApplianceLimits(endpoint)
+	assert.True(t, ok)
+	assert.True(t, l.RetryAfter.After(time.Now()))
+	assert.Equal(t, CategoryTransient, CategoryOf(err))
+	var busy *BusyError
+	assert.True(t, errors.As(err, &busy))
+	assert.Equal(t, throttledBackoff, busy.RetryAfter)
+
+	err = checkLimitError(endpoint, applianceError(errors.New("Response Status: 503 Service Unavailable")))
+	assert.True(t, errors.As(err, &busy))
+	assert.Equal(t, defaultBusyRetry, busy.RetryAfter)
+	assert.Contains(t, err.Error(), "503 Service Unavailable")
+}
@@ -0,0 +1,74 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timestampLayouts - formats the appliances have used for created, modified
+// and task times, tried in order
+var timestampLayouts = []string{
+	"2006-01-02T15:04:05.000Z07:00",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05Z0700",
+	"20060102T150405.000Z07:00",
+	"20060102T150405.000Z0700",
+	"20060102T150405Z07:00",
+	"20060102T150405Z0700",
+	"2006-01-02 15:04:05.000Z07:00",
+}
+
+// Timestamp - an appliance time, always held in UTC.  Times without a zone
+// are taken to be UTC, which is what the appliance reports.
+type Timestamp struct {
+	time.Time
+}
+
+// ParseTimestamp - parse any of the appliance time formats
+func ParseTimestamp(s string) (Timestamp, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Timestamp{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Timestamp{t.UTC()}, nil
+		}
+	}
+	// no zone at all, ie; 20150831T154835.250
+	for _, layout := range []string{"2006-01-02T15:04:05.000", "2006-01-02T15:04:05", "20060102T150405.000", "20060102T150405"} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return Timestamp{t}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("unrecognized appliance timestamp %q", s)
+}
+
+// UnmarshalJSON - read a timestamp string, empty and null give the zero time
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	var s string
+	if string(b) == "null" {
+		*t = Timestamp{}
+		return nil
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	ts, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	*t = ts
+	return nil
+}
+
+// MarshalJSON - write in the format current appliances use
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(t.UTC().Format("2006-01-02T15:04:05.000Z"))
+}
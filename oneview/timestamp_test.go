@@ -0,0 +1,45 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2015, 8, 31, 15, 48, 35, 250000000, time.UTC)
+	for _, s := range []string{
+		"20150831T154835.250Z",
+		"2015-08-31T15:48:35.250Z",
+		"2015-08-31T15:48:35.250+0000",
+		"2015-08-31T17:48:35.250+02:00",
+		"2015-08-31T15:48:35.250",
+	} {
+		ts, err := ParseTimestamp(s)
+		assert.NoError(t, err, s)
+		assert.True(t, want.Equal(ts.Time), s)
+		assert.Equal(t, time.UTC, ts.Location(), s)
+	}
+	ts, err := ParseTimestamp("")
+	assert.NoError(t, err)
+	assert.True(t, ts.IsZero())
+	_, err = ParseTimestamp("last tuesday")
+	assert.Error(t, err)
+}
+
+func TestTimestampJSON(t *testing.T) {
+	var task Task
+	data := `{"name":"Create","taskState":"Completed","created":"2015-08-31T15:48:35.250Z","modified":"20150831T155035.250Z"}`
+	assert.NoError(t, json.Unmarshal([]byte(data), &task))
+	assert.Equal(t, 2*time.Minute, task.Duration(time.Now()))
+
+	b, err := json.Marshal(task.Created)
+	assert.NoError(t, err)
+	assert.Equal(t, `"2015-08-31T15:48:35.250Z"`, string(b))
+
+	var p ServerProfileSummary
+	assert.NoError(t, json.Unmarshal([]byte(`{"created":null,"modified":""}`), &p))
+	assert.True(t, p.Created.IsZero())
+}
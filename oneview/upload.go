@@ -0,0 +1,86 @@
+package oneview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+)
+
+const firmwareBundlesURI = "/rest/firmware-bundles"
+
+// UploadFirmwareBundle - stage a service pack for proliant (spp) iso on the
+// appliance, reporting upload progress to out
+func UploadFirmwareBundle(c *ov.OVClient, path string, out ProgressSink) error {
+	return uploadFile(c, firmwareBundlesURI, path, out)
+}
+
+// uploadFile - stream a file to the appliance as a multipart upload, without
+// holding it in memory, and wait on the task the appliance hands back
+func uploadFile(c *ov.OVClient, uri, path string, out ProgressSink) error {
+	if out == nil {
+		out = defaultProgress()
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(path)
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", name)
+		if err == nil {
+			src := newProgressReader(f, out, info.Size(), name, "Uploading")
+			_, err = io.Copy(part, src)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := newApplianceRequest(c, "POST", uri)
+	if err != nil {
+		pr.Close()
+		return err
+	}
+	req.Body = pr
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("uploadfilename", name)
+
+	// uploads of several GB can take much longer than normal calls
+	client := newApplianceHTTPClient(c.SSLVerify)
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return &ApplianceError{StatusCode: resp.StatusCode, Err: fmt.Errorf("upload of %s failed with %s : %s", name, resp.Status, errorBody(data))}
+	}
+
+	// some appliance versions finish the upload synchronously
+	var t Task
+	if err := json.Unmarshal(data, &t); err != nil || t.URI == "" || !isTaskType(t.Type) {
+		return nil
+	}
+	return waitForTask(c, t.URI)
+}
@@ -0,0 +1,115 @@
+package oneview
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// fieldAlias - an attribute some api versions name differently.  Path is
+// where the Go models have it, with [] after a list of objects, ie;
+// connections[].requestedMbps, and Old the name appliances before Until use.
+type fieldAlias struct {
+	Path  string
+	Old   string
+	Until int
+}
+
+// profileFieldAliases - profile and template attributes renamed between api
+// versions, the models use the current names.  Only renames the OneView api
+// reference documents belong here, each with a test.
+var profileFieldAliases = []fieldAlias{}
+
+// split - the parents of the attribute and its current name
+func (a fieldAlias) split() ([]string, string) {
+	parts := strings.Split(a.Path, ".")
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// eachObject - run fn on every object at the parents below node, a list at
+// the top runs it on each member
+func eachObject(node interface{}, parents []string, fn func(m map[string]interface{})) {
+	switch n := node.(type) {
+	case []interface{}:
+		for _, member := range n {
+			eachObject(member, parents, fn)
+		}
+	case map[string]interface{}:
+		if len(parents) == 0 {
+			fn(n)
+			return
+		}
+		name := parents[0]
+		if strings.HasSuffix(name, "[]") {
+			list, _ := n[strings.TrimSuffix(name, "[]")].([]interface{})
+			for _, member := range list {
+				eachObject(member, parents[1:], fn)
+			}
+			return
+		}
+		eachObject(n[name], parents[1:], fn)
+	}
+}
+
+// normalizeFields - move attributes under an old name to the current one,
+// whatever the api version, the current name wins when both are there
+func normalizeFields(raw interface{}, aliases []fieldAlias) {
+	for _, a := range aliases {
+		parents, name := a.split()
+		eachObject(raw, parents, func(m map[string]interface{}) {
+			v, ok := m[a.Old]
+			if !ok {
+				return
+			}
+			delete(m, a.Old)
+			if _, has := m[name]; !has {
+				m[name] = v
+			}
+		})
+	}
+}
+
+// versionFields - rename current attributes to what an appliance at the
+// api version takes
+func versionFields(raw interface{}, aliases []fieldAlias, apiVersion int) {
+	for _, a := range aliases {
+		if apiVersion >= a.Until {
+			continue
+		}
+		parents, name := a.split()
+		eachObject(raw, parents, func(m map[string]interface{}) {
+			if v, ok := m[name]; ok {
+				delete(m, name)
+				m[a.Old] = v
+			}
+		})
+	}
+}
+
+// decodeVersioned - json.Unmarshal into a model, taking attributes under
+// the names of any api version rather than leaving them zero
+func decodeVersioned(data []byte, out interface{}, aliases []fieldAlias) error {
+	var raw interface{}
+	if err := decodeJSON(data, &raw); err != nil {
+		return err
+	}
+	normalizeFields(raw, aliases)
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(normalized, out)
+}
+
+// encodeVersioned - a model as raw attributes named for the api version
+func encodeVersioned(in interface{}, aliases []fieldAlias, apiVersion int) (map[string]interface{}, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := decodeJSON(data, &raw); err != nil {
+		return nil, err
+	}
+	versionFields(raw, aliases, apiVersion)
+	return raw, nil
+}
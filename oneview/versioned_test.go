@@ -0,0 +1,63 @@
+package oneview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testFieldAliases - renames to exercise the aliasing with, not ones the
+// appliance made
+var testFieldAliases = []fieldAlias{
+	{Path: "boot.order", Old: "bootOrder", Until: 300},
+	{Path: "connections[].requestedMbps", Old: "requestedBandwidth", Until: 200},
+}
+
+func TestDecodeVersioned(t *testing.T) {
+	old := []byte(`{"name": "docker1", "boot": {"manageBoot": true, "bootOrder": ["PXE", "HardDisk"]},
+		"connections": [{"id": 1, "requestedBandwidth": "2500"}, {"id": 2, "requestedMbps": "1000", "requestedBandwidth": "9000"}]}`)
+	var spec ServerProfileSpec
+	assert.NoError(t, decodeVersioned(old, &spec, testFieldAliases))
+	assert.Equal(t, []string{"PXE", "HardDisk"}, spec.Boot.Order)
+	assert.Equal(t, "2500", spec.Connections[0].RequestedMbps)
+	// the current name wins
+	assert.Equal(t, "1000", spec.Connections[1].RequestedMbps)
+
+	var list []ServerProfileSpec
+	assert.NoError(t, decodeVersioned([]byte(`[{"boot": {"bootOrder": ["CD"]}}]`), &list, testFieldAliases))
+	assert.Equal(t, []string{"CD"}, list[0].Boot.Order)
+}
+
+func TestEncodeVersioned(t *testing.T) {
+	spec := ServerProfileSpec{
+		Name:        "docker1",
+		Boot:        &ProfileBoot{ManageBoot: true, Order: []string{"PXE"}},
+		Connections: []ProfileConnection{{ID: 1, RequestedMbps: "2500"}},
+	}
+	raw, err := encodeVersioned(spec, testFieldAliases, 120)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"PXE"}, raw["boot"].(map[string]interface{})["bootOrder"])
+	assert.Equal(t, "2500", raw["connections"].([]interface{})[0].(map[string]interface{})["requestedBandwidth"])
+
+	raw, _ = encodeVersioned(spec, testFieldAliases, 200)
+	assert.Contains(t, raw["boot"], "bootOrder")
+	assert.Contains(t, raw["connections"].([]interface{})[0], "requestedMbps")
+
+	raw, _ = encodeVersioned(spec, testFieldAliases, 300)
+	assert.Contains(t, raw["boot"], "order")
+}
+
+func TestProfileFieldsKeepTheirNames(t *testing.T) {
+	spec := ServerProfileSpec{
+		Boot:        &ProfileBoot{ManageBoot: true, Order: []string{"PXE"}},
+		Connections: []ProfileConnection{{ID: 1, RequestedMbps: "2500"}},
+	}
+	// boot.order and requestedMbps are what every api version the driver
+	// talks to takes, httpboot reads boot.order at all of them
+	for _, version := range []int{120, 201, 300, 800} {
+		raw, err := encodeVersioned(spec, profileFieldAliases, version)
+		assert.NoError(t, err)
+		assert.Contains(t, raw["boot"], "order", version)
+		assert.Contains(t, raw["connections"].([]interface{})[0], "requestedMbps", version)
+	}
+}
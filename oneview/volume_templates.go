@@ -0,0 +1,229 @@
+package oneview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/HewlettPackard/oneview-golang/ov"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const storageVolumeTemplatesURI = "/rest/storage-volume-templates"
+
+// volumePropertiesAPIVersion - first api version describing volume templates
+// and volume requests with properties rather than provisioning settings
+const volumePropertiesAPIVersion = 600
+
+// volume provisioning types
+const (
+	ProvisioningThin = "Thin"
+	ProvisioningFull = "Full"
+)
+
+// StorageVolumeTemplate - what storage admins allow volumes to be created
+// with, the appliance holds requests to the template's limits
+type StorageVolumeTemplate struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state,omitempty"`
+	// Provisioning - settings of templates before volumePropertiesAPIVersion
+	Provisioning struct {
+		Capacity       string `json:"capacity,omitempty"`
+		ProvisionType  string `json:"provisionType,omitempty"`
+		Shareable      bool   `json:"shareable,omitempty"`
+		StoragePoolURI string `json:"storagePoolUri,omitempty"`
+	} `json:"provisioning,omitempty"`
+	// Properties - settings of later templates, each with a default and limits
+	Properties map[string]VolumeTemplateProperty `json:"properties,omitempty"`
+}
+
+// VolumeTemplateProperty - one setting of a volume template
+type VolumeTemplateProperty struct {
+	Default interface{}   `json:"default,omitempty"`
+	Minimum json.Number   `json:"minimum,omitempty"`
+	Maximum json.Number   `json:"maximum,omitempty"`
+	Enum    []interface{} `json:"enum,omitempty"`
+}
+
+// ListStorageVolumeTemplates - the volume templates, by name
+func ListStorageVolumeTemplates(c *ov.OVClient) ([]StorageVolumeTemplate, error) {
+	var list []StorageVolumeTemplate
+	err := listOrdered(c, storageVolumeTemplatesURI, ListOptions{}, func(members json.RawMessage) error {
+		var page []StorageVolumeTemplate
+		if err := json.Unmarshal(members, &page); err != nil {
+			return err
+		}
+		list = append(list, page...)
+		return nil
+	})
+	return list, err
+}
+
+// GetStorageVolumeTemplate - a volume template by name
+func GetStorageVolumeTemplate(c *ov.OVClient, name string) (StorageVolumeTemplate, error) {
+	var t StorageVolumeTemplate
+	uri, err := findURIByName(c, storageVolumeTemplatesURI, name)
+	if err != nil {
+		return t, err
+	}
+	if uri == "" {
+		return t, fmt.Errorf("unable to find storage volume template %s", name)
+	}
+	data, err := ovCall(c, rest.GET, uri, nil)
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// VolumeRequest - a volume to create from a template, settings left empty
+// take the template default
+type VolumeRequest struct {
+	Name             string `json:"name"`
+	Template         string `json:"template"`
+	SizeGb           int    `json:"sizeGb,omitempty"`
+	ProvisioningType string `json:"provisioningType,omitempty"`
+	Shareable        bool   `json:"shareable,omitempty"`
+}
+
+// Validate - check the request before sending it to the appliance, the
+// capacity limits are the template's and the appliance enforces them
+func (v VolumeRequest) Validate() error {
+	if v.Name == "" {
+		return fmt.Errorf("volume request needs a name")
+	}
+	if v.Template == "" {
+		return fmt.Errorf("volume %s needs a volume template", v.Name)
+	}
+	if v.SizeGb < 0 {
+		return fmt.Errorf("volume %s size must not be negative", v.Name)
+	}
+	switch v.ProvisioningType {
+	case "", ProvisioningThin, ProvisioningFull:
+	default:
+		return fmt.Errorf("volume %s provisioning type %s must be %s or %s", v.Name, v.ProvisioningType, ProvisioningThin, ProvisioningFull)
+	}
+	return nil
+}
+
+// volumeRequestBody - the storage volume create body for a template, in the
+// form the api version takes
+func volumeRequestBody(v VolumeRequest, t StorageVolumeTemplate, apiVersion int) map[string]interface{} {
+	size := ""
+	if v.SizeGb > 0 {
+		size = strconv.FormatInt(int64(v.SizeGb)<<30, 10)
+	}
+	if apiVersion >= volumePropertiesAPIVersion {
+		properties := map[string]interface{}{
+			"name":        v.Name,
+			"isShareable": v.Shareable,
+		}
+		if p, ok := t.Properties["storagePool"]; ok && p.Default != nil {
+			properties["storagePool"] = p.Default
+		}
+		if size != "" {
+			properties["size"] = json.Number(size)
+		}
+		if v.ProvisioningType != "" {
+			properties["provisioningType"] = v.ProvisioningType
+		}
+		return map[string]interface{}{
+			"properties":  properties,
+			"templateUri": t.URI,
+			"isPermanent": true,
+		}
+	}
+	provisioning := map[string]interface{}{
+		"shareable":      v.Shareable,
+		"storagePoolUri": t.Provisioning.StoragePoolURI,
+	}
+	if size != "" {
+		provisioning["requestedCapacity"] = size
+	}
+	if v.ProvisioningType != "" {
+		provisioning["provisionType"] = v.ProvisioningType
+	}
+	return map[string]interface{}{
+		"name":                   v.Name,
+		"templateUri":            t.URI,
+		"isPermanent":            true,
+		"provisioningParameters": provisioning,
+	}
+}
+
+// submitVolume - start creating a volume from its template, returns the task
+func submitVolume(c *ov.OVClient, v VolumeRequest) (string, error) {
+	if err := v.Validate(); err != nil {
+		return "", err
+	}
+	t, err := GetStorageVolumeTemplate(c, v.Template)
+	if err != nil {
+		return "", err
+	}
+	data, err := ovCall(c, rest.POST, storageVolumesURI, volumeRequestBody(v, t, c.APIVersion))
+	if err != nil {
+		return "", fmt.Errorf("unable to create volume %s from template %s : %w", v.Name, v.Template, err)
+	}
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return "", err
+	}
+	if task.URI == "" {
+		return "", fmt.Errorf("appliance did not return a task to wait on")
+	}
+	return task.URI, nil
+}
+
+// CreateVolumeFromTemplate - create a volume from a template, returns the
+// volume uri
+func CreateVolumeFromTemplate(ctx context.Context, c *ov.OVClient, v VolumeRequest) (string, error) {
+	uri, err := submitVolume(c, v)
+	if err != nil {
+		return "", err
+	}
+	t, err := waitForTaskResult(ctx, c, uri)
+	if err != nil {
+		return "", err
+	}
+	return t.AssociatedResource.ResourceURI, nil
+}
+
+// CreateVolumes - create the volumes that do not exist yet, in parallel.
+// Volumes are found by name, so a create run again reuses them.
+func CreateVolumes(ctx context.Context, c *ov.OVClient, requests []VolumeRequest) error {
+	var waits []TaskWait
+	for _, v := range requests {
+		uri, err := findURIByName(c, storageVolumesURI, v.Name)
+		if err != nil {
+			return err
+		}
+		if uri != "" {
+			log.Debugf("storage volume %s exists at %s", v.Name, uri)
+			continue
+		}
+		log.Infof("Creating storage volume %s from template %s", v.Name, v.Template)
+		task, err := submitVolume(c, v)
+		if err != nil {
+			return err
+		}
+		waits = append(waits, TaskWait{Label: v.Name, URI: task})
+	}
+	if len(waits) == 0 {
+		return nil
+	}
+	return WaitForTasks(ctx, c, waits, nil).Err()
+}
+
+// createDataVolumes - create the machine data volumes from their templates,
+// storageChange attaches them with the other volumes
+func (d *Driver) createDataVolumes() error {
+	if len(d.DataVolumes) == 0 {
+		return nil
+	}
+	return CreateVolumes(interruptCtx, d.ClientOV, d.DataVolumes)
+}
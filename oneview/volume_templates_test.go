@@ -0,0 +1,42 @@
+package oneview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeRequestBody(t *testing.T) {
+	v := VolumeRequest{Name: "data", Template: "thin", SizeGb: 2, ProvisioningType: ProvisioningThin}
+
+	var tmpl StorageVolumeTemplate
+	tmpl.URI = "/rest/storage-volume-templates/T"
+	tmpl.Provisioning.StoragePoolURI = "/rest/storage-pools/P"
+	body := volumeRequestBody(v, tmpl, 500)
+	assert.Equal(t, "data", body["name"])
+	assert.Equal(t, map[string]interface{}{
+		"shareable":         false,
+		"storagePoolUri":    "/rest/storage-pools/P",
+		"requestedCapacity": "2147483648",
+		"provisionType":     "Thin",
+	}, body["provisioningParameters"])
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"uri": "/rest/storage-volume-templates/T",
+		"properties": {"storagePool": {"default": "/rest/storage-pools/P"}, "size": {"minimum": 1073741824, "maximum": 10737418240}}}`), &tmpl))
+	body = volumeRequestBody(VolumeRequest{Name: "data", Template: "thin"}, tmpl, 600)
+	assert.Equal(t, "/rest/storage-volume-templates/T", body["templateUri"])
+	assert.Equal(t, map[string]interface{}{
+		"name":        "data",
+		"isShareable": false,
+		"storagePool": "/rest/storage-pools/P",
+	}, body["properties"])
+}
+
+func TestVolumeRequestValidate(t *testing.T) {
+	assert.NoError(t, VolumeRequest{Name: "data", Template: "thin"}.Validate())
+	assert.Error(t, VolumeRequest{Name: "data"}.Validate())
+	assert.Error(t, VolumeRequest{Template: "thin"}.Validate())
+	assert.Error(t, VolumeRequest{Name: "data", Template: "thin", SizeGb: -1}.Validate())
+	assert.Error(t, VolumeRequest{Name: "data", Template: "thin", ProvisioningType: "Thick"}.Validate())
+}
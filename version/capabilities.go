@@ -0,0 +1,139 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Feature names that orchestration layers can check with Supports
+const (
+	FeatureServerTemplates = "templates"
+	FeatureICSP            = "icsp"
+	FeatureImageStreamer   = "image-streamer"
+	FeatureSCMB            = "scmb"
+)
+
+// features supported by this build, keep this up to date when adding or
+// removing support for a OneView or ICSP feature
+var features = map[string]bool{
+	FeatureServerTemplates: true,
+	FeatureICSP:            true,
+	FeatureImageStreamer:   false,
+	FeatureSCMB:            false,
+}
+
+// SemVer is a parsed semantic version, ie; 0.9-dev
+type SemVer struct {
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease string
+}
+
+// String formats the version as major.minor.patch[-prerelease]
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	return s
+}
+
+// LessThan is true when v is an older release than o
+func (v SemVer) LessThan(o SemVer) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	if v.Patch != o.Patch {
+		return v.Patch < o.Patch
+	}
+	// a pre-release sorts before the release it leads up to
+	if v.PreRelease == "" || o.PreRelease == "" {
+		return v.PreRelease != "" && o.PreRelease == ""
+	}
+	return preReleaseLess(v.PreRelease, o.PreRelease)
+}
+
+// preReleaseLess - compare pre-releases one dot separated part at a time,
+// the number ending a part by value so rc2 sorts before rc10
+func preReleaseLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		aw, an := splitNumber(as[i])
+		bw, bn := splitNumber(bs[i])
+		if aw != bw {
+			return aw < bw
+		}
+		if an != bn {
+			return an < bn
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// splitNumber - a pre-release part split into its text and the number it
+// ends with, -1 when it has none
+func splitNumber(s string) (string, int) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(s[i:])
+	if err != nil {
+		return s, -1
+	}
+	return s[:i], n
+}
+
+// ParseSemVer parses versions like 0.9, 0.8.0-rc1 or v1.2.3
+func ParseSemVer(s string) (SemVer, error) {
+	var v SemVer
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.Index(core, "-"); i >= 0 {
+		v.PreRelease = core[i+1:]
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) < 1 || len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// BuildCapabilities describes this build of the driver and the features it supports
+type BuildCapabilities struct {
+	Version   SemVer
+	GitCommit string
+	Features  map[string]bool
+}
+
+// Supports is true when this build supports the named feature
+func (c BuildCapabilities) Supports(feature string) bool {
+	return c.Features[feature]
+}
+
+// Capabilities returns the capabilities of this build
+func Capabilities() BuildCapabilities {
+	v, _ := ParseSemVer(Version)
+	c := BuildCapabilities{
+		Version:   v,
+		GitCommit: GitCommit,
+		Features:  make(map[string]bool, len(features)),
+	}
+	for k, b := range features {
+		c.Features[k] = b
+	}
+	return c
+}
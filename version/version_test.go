@@ -1 +1,57 @@
 package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSemVer(t *testing.T) {
+	v, err := ParseSemVer("0.9-dev")
+	assert.NoError(t, err)
+	assert.Equal(t, SemVer{Major: 0, Minor: 9, PreRelease: "dev"}, v)
+	assert.Equal(t, "0.9.0-dev", v.String())
+
+	v, err = ParseSemVer("v1.2.3")
+	assert.NoError(t, err)
+	assert.Equal(t, SemVer{Major: 1, Minor: 2, Patch: 3}, v)
+
+	_, err = ParseSemVer("1.x")
+	assert.Error(t, err)
+	_, err = ParseSemVer("1.2.3.4")
+	assert.Error(t, err)
+}
+
+func TestSemVerLessThan(t *testing.T) {
+	rc, _ := ParseSemVer("0.8.0-rc1")
+	rel, _ := ParseSemVer("0.8.0")
+	next, _ := ParseSemVer("0.9")
+	assert.True(t, rc.LessThan(rel))
+	assert.False(t, rel.LessThan(rc))
+	assert.True(t, rel.LessThan(next))
+	assert.False(t, next.LessThan(next))
+
+	rc2, _ := ParseSemVer("0.8.0-rc2")
+	rc10, _ := ParseSemVer("0.8.0-rc10")
+	assert.True(t, rc2.LessThan(rc10))
+	assert.False(t, rc10.LessThan(rc2))
+	assert.True(t, rc.LessThan(rc2))
+
+	beta, _ := ParseSemVer("0.8.0-beta.2")
+	beta11, _ := ParseSemVer("0.8.0-beta.11")
+	alpha, _ := ParseSemVer("0.8.0-alpha")
+	assert.True(t, beta.LessThan(beta11))
+	assert.True(t, alpha.LessThan(beta))
+	assert.True(t, beta.LessThan(rc))
+}
+
+func TestCapabilities(t *testing.T) {
+	c := Capabilities()
+	assert.True(t, c.Supports(FeatureServerTemplates))
+	assert.False(t, c.Supports(FeatureImageStreamer))
+	assert.False(t, c.Supports("unknown"))
+
+	// callers can not change what the build supports
+	c.Features[FeatureSCMB] = true
+	assert.False(t, Capabilities().Supports(FeatureSCMB))
+}